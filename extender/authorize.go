@@ -0,0 +1,93 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package extender
+
+import (
+	"crypto/x509"
+	"net/http"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// clientAllowlist holds the identities of the client certificates allowed to call the extender. A certificate is
+// identified by its subject common name and each of its subject alternative names.
+type clientAllowlist map[string]struct{}
+
+// newClientAllowlist returns the allowlist of the comma separated identities, or nil if there are none.
+func newClientAllowlist(identities string) clientAllowlist {
+	var allowlist clientAllowlist
+
+	for _, identity := range strings.Split(identities, ",") {
+		identity = strings.TrimSpace(identity)
+		if identity == "" {
+			continue
+		}
+
+		if allowlist == nil {
+			allowlist = clientAllowlist{}
+		}
+
+		allowlist[identity] = struct{}{}
+	}
+
+	return allowlist
+}
+
+// authorize serves the requests of allowed clients with next. Other requests are answered with a 403 and audited,
+// logging who made them and what they asked for.
+func (a clientAllowlist) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cert *x509.Certificate
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			cert = r.TLS.PeerCertificates[0]
+		}
+
+		if cert != nil && a.allows(cert) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		subject, identities := "", []string{}
+		if cert != nil {
+			subject, identities = cert.Subject.String(), certIdentities(cert)
+		}
+
+		klog.InfoS("Audit: request from unauthorized client rejected", "component", "extender", "method", r.Method,
+			"path", r.URL.Path, "remoteAddr", r.RemoteAddr, "subject", subject, "identities", identities)
+		w.WriteHeader(http.StatusForbidden)
+	})
+}
+
+func (a clientAllowlist) allows(cert *x509.Certificate) bool {
+	for _, identity := range certIdentities(cert) {
+		if _, ok := a[identity]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// certIdentities returns the subject common name and the subject alternative names of the certificate.
+func certIdentities(cert *x509.Certificate) []string {
+	identities := []string{}
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+
+	identities = append(identities, cert.DNSNames...)
+	identities = append(identities, cert.EmailAddresses...)
+
+	for _, ip := range cert.IPAddresses {
+		identities = append(identities, ip.String())
+	}
+
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+
+	return identities
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package extender
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/klog/v2"
+)
+
+func TestNewClientAllowlist(t *testing.T) {
+	tests := []struct {
+		name       string
+		identities string
+		want       clientAllowlist
+	}{
+		{"empty", "", nil},
+		{"only separators", " , ,", nil},
+		{"identities", "kube-scheduler, scheduler.example.com ,", clientAllowlist{"kube-scheduler": {}, "scheduler.example.com": {}}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newClientAllowlist(tt.identities); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newClientAllowlist() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientAllowlist_allows(t *testing.T) {
+	uri, _ := url.Parse("spiffe://cluster.local/ns/kube-system/sa/kube-scheduler")
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "kube-scheduler"},
+		DNSNames:       []string{"scheduler.example.com"},
+		EmailAddresses: []string{"scheduler@example.com"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:           []*url.URL{uri},
+	}
+
+	tests := []struct {
+		name      string
+		allowlist string
+		want      bool
+	}{
+		{"common name", "kube-scheduler", true},
+		{"DNS name", "scheduler.example.com", true},
+		{"email address", "scheduler@example.com", true},
+		{"IP address", "10.0.0.1", true},
+		{"URI", "spiffe://cluster.local/ns/kube-system/sa/kube-scheduler", true},
+		{"any listed identity", "other,kube-scheduler", true},
+		{"other identity", "other", false},
+		{"subject isn't an identity", "CN=kube-scheduler", false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newClientAllowlist(tt.allowlist).allows(cert); got != tt.want {
+				t.Errorf("allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientAllowlist_authorize(t *testing.T) {
+	var logs bytes.Buffer
+
+	klog.LogToStderr(false)
+	klog.SetOutput(&logs)
+
+	defer klog.LogToStderr(true)
+
+	allowed := &x509.Certificate{Subject: pkix.Name{CommonName: "kube-scheduler"}}
+	other := &x509.Certificate{Subject: pkix.Name{CommonName: "intruder"}, DNSNames: []string{"intruder.example.com"}}
+
+	tests := []struct {
+		name      string
+		cert      *x509.Certificate
+		want      int
+		wantAudit []string
+	}{
+		{"allowed client", allowed, http.StatusOK, nil},
+		{"other client", other, http.StatusForbidden, []string{"/scheduler/bind", "CN=intruder", "intruder.example.com"}},
+		{"no client certificate", nil, http.StatusForbidden, []string{"/scheduler/bind"}},
+	}
+
+	handler := newClientAllowlist("kube-scheduler").authorize(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+
+			r := httptest.NewRequest(http.MethodPost, "/scheduler/bind", nil)
+			if tt.cert != nil {
+				r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{tt.cert}}
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			klog.Flush()
+
+			if w.Code != tt.want {
+				t.Errorf("status = %v, want %v", w.Code, tt.want)
+			}
+
+			audit := logs.String()
+			if (tt.wantAudit == nil) != (audit == "") {
+				t.Errorf("audit log = %q, want an entry: %v", audit, tt.wantAudit != nil)
+			}
+
+			for _, want := range tt.wantAudit {
+				if !strings.Contains(audit, want) {
+					t.Errorf("audit log = %q, want it to contain %q", audit, want)
+				}
+			}
+		})
+	}
+}
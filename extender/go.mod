@@ -5,6 +5,7 @@ go 1.21
 require (
 	k8s.io/client-go v0.28.4
 	k8s.io/klog/v2 v2.110.1
+	k8s.io/kube-scheduler v0.28.4
 )
 
 require (
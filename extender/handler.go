@@ -0,0 +1,124 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package extender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"k8s.io/klog/v2"
+)
+
+// maxRequestSize is the largest request body, in bytes, read from the scheduler. Requests to extenders which aren't
+// nodeCacheCapable hold every candidate node, each taking a few kB, so this leaves room for clusters of thousands of
+// nodes.
+const maxRequestSize = 50 * 1000 * 1000
+
+var (
+	// ErrNotSupported is returned by a Scheduler for the verbs it doesn't support. It's answered with a 404.
+	ErrNotSupported = errors.New("not supported by this extender")
+	errEmptyBody    = errors.New("request body empty")
+)
+
+// statusError is an error answered with a specific HTTP status code.
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// WithStatus returns the error to be answered with the HTTP status code instead of a 500 by the handlers of Handle.
+func WithStatus(err error, code int) error {
+	return &statusError{code: code, err: err}
+}
+
+// errorResponse is the body of failed requests. Its error field is also the one of the filter and bind results.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handle returns a handler serving requests decoded into A with serve, writing its result as JSON. Bodies larger than
+// the size limit or which can't be decoded are answered with a 413 or a 400. Errors from serve are answered with a
+// 500, a 404 for ErrNotSupported or the status given with WithStatus. Failed requests are logged and answered with a
+// JSON body holding the error.
+func Handle[A, R any](serve func(ctx context.Context, args *A) (*R, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		args, err := decodeArgs[A](w, r)
+		if err != nil {
+			writeError(w, err)
+
+			return
+		}
+
+		result, err := serve(r.Context(), args)
+		if err != nil {
+			writeError(w, err)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// decodeArgs reads the JSON request body into A.
+func decodeArgs[A any](w http.ResponseWriter, r *http.Request) (*A, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, WithStatus(errEmptyBody, http.StatusBadRequest)
+	}
+
+	defer r.Body.Close()
+
+	args := new(A)
+
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(args)
+
+	var tooLarge *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &tooLarge):
+		return nil, WithStatus(fmt.Errorf("request size too large: %w", err), http.StatusRequestEntityTooLarge)
+	case err != nil:
+		return nil, WithStatus(fmt.Errorf("error decoding request: %w", err), http.StatusBadRequest)
+	}
+
+	return args, nil
+}
+
+// writeError logs the error of a request and answers it with the status code of the error.
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+
+	var withStatus *statusError
+
+	switch {
+	case errors.As(err, &withStatus):
+		code = withStatus.code
+	case errors.Is(err, ErrNotSupported):
+		code = http.StatusNotFound
+	}
+
+	klog.V(l2).InfoS("Request failed: "+err.Error(), "component", "extender")
+	writeJSON(w, code, errorResponse{Error: err.Error()})
+}
+
+// writeJSON writes the response with the status code and the body encoded as JSON.
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		klog.V(l2).InfoS("Encode error: "+err.Error(), "component", "extender")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package extender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var errServe = errors.New("serve failed")
+
+type testArgs struct {
+	Name string `json:"name"`
+}
+
+type testResult struct {
+	Greeting string `json:"greeting"`
+}
+
+// spaces reads as an endless run of spaces, which is valid JSON whitespace.
+type spaces struct{}
+
+func (spaces) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+
+	return len(p), nil
+}
+
+func TestHandle(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      io.Reader
+		serveErr  error
+		wantCode  int
+		wantBody  string
+		wantError string
+	}{
+		{"decoded request", strings.NewReader(`{"name": "node A"}`), nil, http.StatusOK, `{"greeting":"hello node A"}`, ""},
+		{"empty body", nil, nil, http.StatusBadRequest, "", errEmptyBody.Error()},
+		{"invalid body", strings.NewReader(`{"name":`), nil, http.StatusBadRequest, "", "error decoding request: unexpected EOF"},
+		{"body too large", io.LimitReader(spaces{}, maxRequestSize+1), nil, http.StatusRequestEntityTooLarge, "",
+			"request size too large: http: request body too large"},
+		{"not supported", strings.NewReader(`{}`), ErrNotSupported, http.StatusNotFound, "", ErrNotSupported.Error()},
+		{"error with status", strings.NewReader(`{}`), WithStatus(errServe, http.StatusConflict), http.StatusConflict, "",
+			errServe.Error()},
+		{"wrapped error with status", strings.NewReader(`{}`), fmt.Errorf("wrapped: %w", WithStatus(errServe, http.StatusConflict)),
+			http.StatusConflict, "", "wrapped: " + errServe.Error()},
+		{"error", strings.NewReader(`{}`), errServe, http.StatusInternalServerError, "", errServe.Error()},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Handle(func(_ context.Context, args *testArgs) (*testResult, error) {
+				if tt.serveErr != nil {
+					return nil, tt.serveErr
+				}
+
+				return &testResult{Greeting: "hello " + args.Name}, nil
+			})
+
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(http.MethodPost, "/scheduler/filter", tt.body))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Handle() status = %v, want %v", w.Code, tt.wantCode)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Handle() content type = %q, want application/json", got)
+			}
+
+			if tt.wantError == "" {
+				if got := strings.TrimSpace(w.Body.String()); got != tt.wantBody {
+					t.Errorf("Handle() body = %v, want %v", got, tt.wantBody)
+				}
+
+				return
+			}
+
+			response := errorResponse{}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Error != tt.wantError {
+				t.Errorf("Handle() error = %q (%v), want %q", response.Error, err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestWithStatus(t *testing.T) {
+	err := WithStatus(errServe, http.StatusConflict)
+
+	if !errors.Is(err, errServe) {
+		t.Errorf("WithStatus() doesn't wrap the error")
+	}
+
+	if err.Error() != errServe.Error() {
+		t.Errorf("WithStatus() error = %q, want %q", err.Error(), errServe.Error())
+	}
+}
@@ -5,22 +5,29 @@
 package extender
 
 import (
+	"context"
 	"crypto/tls"
-	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
-	"os"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"k8s.io/klog/v2"
 )
 
 const (
-	l2           = 2
-	readTimeout  = 5
-	writeTimeout = 10
-	maxHeader    = 1000
+	l2              = 2
+	readTimeout     = 5
+	writeTimeout    = 10
+	shutdownTimeout = 20
+	maxHeader       = 1000
 )
 
+var errShuttingDown = errors.New("shutting down")
+
 // postOnly check if the method type is POST.
 func postOnly(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -38,8 +45,8 @@ func postOnly(next http.HandlerFunc) http.HandlerFunc {
 // contentLength check the if the request size is adequate.
 func contentLength(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if r.ContentLength > 1*1000*1000*1000 {
-			w.WriteHeader(http.StatusInternalServerError)
+		if r.ContentLength > maxRequestSize {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
 			klog.V(l2).InfoS("request size too large", "component", "extender")
 
 			return
@@ -94,77 +101,152 @@ func errorHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotFound)
 }
 
-// StartServer starts the HTTP server needed for the scheduler extender.
-// It registers the handlers and checks for existing telemetry policies.
-func (m Server) StartServer(port string, certFile string, keyFile string, caFile string, unsafe bool) {
+// StartServer starts the HTTP server needed for the scheduler extender and, with a HealthPort, the health server.
+// Unless unsafe, the extender is served over TLS with the TLSOptions, reloading the certificates when their files
+// change, and only to the allowed clients if any.
+// It serves until the context is done, then stops accepting requests and drains the requests in flight before
+// returning. It returns an error if a server can't be configured or fails, after stopping the others.
+func (m Server) StartServer(ctx context.Context, port string, certFile string, keyFile string, caFile string, unsafe bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	mx := http.NewServeMux()
 	mx.HandleFunc("/", handlerWithMiddleware(errorHandler))
-	mx.HandleFunc("/scheduler/prioritize", handlerWithMiddleware(m.Prioritize))
-	mx.HandleFunc("/scheduler/filter", handlerWithMiddleware(m.Filter))
-	mx.HandleFunc("/scheduler/bind", handlerWithMiddleware(m.Bind))
+	mx.HandleFunc("/scheduler/prioritize", handlerWithMiddleware(Handle(m.Prioritize)))
+	mx.HandleFunc("/scheduler/filter", handlerWithMiddleware(Handle(m.Filter)))
+	mx.HandleFunc("/scheduler/bind", handlerWithMiddleware(Handle(m.Bind)))
 
-	var err error
+	if preempter, ok := m.Scheduler.(Preempter); ok {
+		mx.HandleFunc("/scheduler/preempt", handlerWithMiddleware(Handle(preempter.ProcessPreemption)))
+	}
+
+	if explainer, ok := m.Scheduler.(Explainer); ok {
+		mx.HandleFunc("/scheduler/explain", handlerWithMiddleware(explainer.Explain))
+	}
+
+	var handler http.Handler = mx
+
+	if allowlist := newClientAllowlist(m.TLSOptions.AllowedClients); allowlist != nil {
+		if unsafe {
+			return fmt.Errorf("%w: allowed clients can't be authenticated without TLS", errTLSOption)
+		}
+
+		handler = allowlist.authorize(mx)
+	}
 
-	if unsafe {
-		klog.V(l2).InfoS("Extender Listening on HTTP "+port, "component", "extender")
+	srv := newServer(port, handler)
+	serve := srv.ListenAndServe
 
-		srv := &http.Server{
-			Addr:              ":" + port,
-			Handler:           mx,
-			ReadHeaderTimeout: readTimeout * time.Second,
-			WriteTimeout:      writeTimeout * time.Second,
-			MaxHeaderBytes:    maxHeader,
+	if !unsafe {
+		tlsConfig, err := m.TLSOptions.config()
+		if err != nil {
+			return err
 		}
 
-		err = srv.ListenAndServe()
+		reloader, err := newCertReloader(certFile, keyFile, caFile)
 		if err != nil {
-			klog.V(l2).InfoS("Listening on HTTP failed: "+err.Error(), "component", "extender")
+			return err
 		}
-	} else {
-		srv := configureSecureServer(port, caFile)
-		srv.Handler = mx
-		klog.V(l2).InfoS("Extender Listening on HTTPS "+port, "component", "extender")
 
-		klog.Fatal(srv.ListenAndServeTLS(certFile, keyFile))
+		go reloader.run(ctx)
+
+		srv.TLSConfig = reloader.tlsConfig(tlsConfig)
+		srv.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+		serve = func() error { return srv.ListenAndServeTLS("", "") }
+	}
+
+	var shuttingDown atomic.Bool
+
+	servers := []*http.Server{srv}
+	errs := make(chan error, 2)
+
+	go func() {
+		klog.V(l2).InfoS("Extender Listening on "+port+", HTTPS: "+strconv.FormatBool(!unsafe), "component", "extender")
+		errs <- serverError("extender", serve())
+	}()
+
+	if m.HealthPort != "" {
+		health := newServer(m.HealthPort, m.healthHandler(&shuttingDown))
+		servers = append(servers, health)
+
+		go func() {
+			klog.V(l2).InfoS("Health endpoints listening on HTTP "+m.HealthPort, "component", "extender")
+			errs <- serverError("health", health.ListenAndServe())
+		}()
+	}
+
+	var err error
+
+	select {
+	case <-ctx.Done():
+		klog.V(l2).InfoS("Shutting down, draining requests in flight", "component", "extender")
+	case err = <-errs:
+	}
+
+	shuttingDown.Store(true)
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout*time.Second)
+	defer cancelShutdown()
+
+	for _, server := range servers {
+		if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil && err == nil {
+			err = fmt.Errorf("server shutdown failed: %w", shutdownErr)
+		}
 	}
 
-	klog.V(l2).InfoS("Scheduler extender server failed to start "+err.Error(), "component", "extender")
+	return err
 }
 
-// Configuration values including algorithms etc for the TAS scheduling endpoint.
-func configureSecureServer(port string, caFile string) *http.Server {
-	caCert, err := os.ReadFile(caFile)
-	if err != nil {
-		klog.V(l2).InfoS("caCert read failed: "+err.Error(), "component", "extender")
+// serverError returns the error a server stopped with, unless it was shut down.
+func serverError(name string, err error) error {
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
 	}
 
-	caCertPool := x509.NewCertPool()
-	caCertPool.AppendCertsFromPEM(caCert)
-
-	cfg := &tls.Config{
-		MinVersion:               tls.VersionTLS12,
-		CurvePreferences:         []tls.CurveID{tls.CurveP521, tls.CurveP384, tls.CurveP256},
-		ClientCAs:                caCertPool,
-		ClientAuth:               tls.RequireAndVerifyClientCert,
-		PreferServerCipherSuites: true,
-		InsecureSkipVerify:       false,
-		CipherSuites: []uint16{
-			// tls 1.2
-			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-			// tls 1.3 configuration not supported
-		},
+	return fmt.Errorf("%v server failed: %w", name, err)
+}
+
+// healthHandler serves the unauthenticated health endpoints. /healthz succeeds while the process serves requests and
+// /readyz once the Readiness is ready, until the server shuts down.
+func (m Server) healthHandler(shuttingDown *atomic.Bool) http.Handler {
+	mx := http.NewServeMux()
+	mx.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})
+	mx.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		err := errShuttingDown
+		if !shuttingDown.Load() {
+			err = m.ready()
+		}
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = io.WriteString(w, "ok")
+	})
+
+	return mx
+}
+
+// ready returns why the extender isn't ready, or nil if it is or has no Readiness.
+func (m Server) ready() error {
+	if m.Readiness == nil {
+		return nil
 	}
 
-	srv := &http.Server{
+	return m.Readiness.Ready()
+}
+
+// newServer returns a server with the timeouts and limits of the extender.
+func newServer(port string, handler http.Handler) *http.Server {
+	return &http.Server{
 		Addr:              ":" + port,
-		Handler:           nil,
+		Handler:           handler,
 		ReadHeaderTimeout: readTimeout * time.Second,
 		WriteTimeout:      writeTimeout * time.Second,
 		MaxHeaderBytes:    maxHeader,
-		TLSConfig:         cfg,
-		TLSNextProto:      make(map[string]func(*http.Server, *tls.Conn, http.Handler)),
 	}
-
-	return srv
 }
@@ -0,0 +1,98 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package extender
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+var errNotReady = errors.New("not ready")
+
+type readinessFunc func() error
+
+func (f readinessFunc) Ready() error {
+	return f()
+}
+
+func TestHandlerWithMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		contentType   string
+		contentLength int64
+		wantCode      int
+	}{
+		{"valid request", http.MethodPost, "application/json", 2, http.StatusOK},
+		{"wrong content type", http.MethodPost, "text/plain", 2, http.StatusNotFound},
+		{"too large", http.MethodPost, "application/json", maxRequestSize + 1, http.StatusRequestEntityTooLarge},
+		{"not a post", http.MethodGet, "application/json", 2, http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			handler := handlerWithMiddleware(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			r := httptest.NewRequest(tt.method, "/scheduler/filter", strings.NewReader("{}"))
+			r.Header.Set("Content-Type", tt.contentType)
+			r.ContentLength = tt.contentLength
+
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("handlerWithMiddleware() status = %v, want %v", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		readiness    ReadinessChecker
+		shuttingDown bool
+		wantHealthz  int
+		wantReadyz   int
+		wantBody     string
+	}{
+		{"no readiness", nil, false, http.StatusOK, http.StatusOK, "ok"},
+		{"ready", readinessFunc(func() error { return nil }), false, http.StatusOK, http.StatusOK, "ok"},
+		{"not ready", readinessFunc(func() error { return errNotReady }), false, http.StatusOK, http.StatusServiceUnavailable,
+			errNotReady.Error()},
+		{"shutting down", readinessFunc(func() error { return nil }), true, http.StatusOK, http.StatusServiceUnavailable,
+			errShuttingDown.Error()},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var shuttingDown atomic.Bool
+
+			shuttingDown.Store(tt.shuttingDown)
+			handler := Server{Readiness: tt.readiness}.healthHandler(&shuttingDown)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+			if w.Code != tt.wantHealthz {
+				t.Errorf("/healthz status = %v, want %v", w.Code, tt.wantHealthz)
+			}
+
+			w = httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if w.Code != tt.wantReadyz || strings.TrimSpace(w.Body.String()) != tt.wantBody {
+				t.Errorf("/readyz = %v %q, want %v %q", w.Code, w.Body.String(), tt.wantReadyz, tt.wantBody)
+			}
+		})
+	}
+}
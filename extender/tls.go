@@ -0,0 +1,256 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package extender
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// certReloadPeriod is how often the certificate files are polled to pick up rotated certificates.
+const certReloadPeriod = 10 * time.Second
+
+// Defaults of the TLS options, matching the configuration of the extender before they were configurable.
+const (
+	defaultMinVersion   = "1.2"
+	defaultCipherSuites = "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"
+	defaultCurves       = "P521,P384,P256"
+)
+
+var (
+	errNoCACert  = errors.New("no CA certificate found")
+	errTLSOption = errors.New("invalid TLS option")
+)
+
+//nolint:gochecknoglobals // read only lookup tables of the TLS option names
+var (
+	tlsVersions = map[string]uint16{"1.2": tls.VersionTLS12, "1.3": tls.VersionTLS13}
+	tlsCurves   = map[string]tls.CurveID{
+		"P256":   tls.CurveP256,
+		"P384":   tls.CurveP384,
+		"P521":   tls.CurveP521,
+		"X25519": tls.X25519,
+	}
+)
+
+// TLSOptions are the TLS settings of the extender endpoint. Cipher suites and curves are comma separated lists of
+// their Go names, e.g. TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384 and P256. Empty options take the defaults.
+// Cipher suites only apply to TLS 1.2, as the TLS 1.3 ones aren't configurable.
+// AllowedClients is a comma separated list of the client certificate identities, subject common names or subject
+// alternative names, allowed to call the extender. Any client with a certificate signed by a trusted CA is allowed
+// when it's empty.
+type TLSOptions struct {
+	MinVersion     string
+	CipherSuites   string
+	Curves         string
+	AllowedClients string
+}
+
+// AddFlags registers the flags setting the options, with the defaults as their values.
+func (o *TLSOptions) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.MinVersion, "tlsMinVersion", defaultMinVersion, "minimum TLS version of the extender endpoint, 1.2 or 1.3")
+	fs.StringVar(&o.CipherSuites, "tlsCipherSuites", defaultCipherSuites,
+		"comma separated TLS 1.2 cipher suites of the extender endpoint, TLS 1.3 ones aren't configurable and are rejected")
+	fs.StringVar(&o.Curves, "tlsCurves", defaultCurves, "comma separated curve preferences of the extender endpoint, among "+
+		"P256, P384, P521 and X25519")
+	fs.StringVar(&o.AllowedClients, "allowedClients", "", "comma separated subject common names or alternative names of the "+
+		"client certificates allowed to call the extender, any client with a trusted certificate if empty")
+}
+
+// config returns the TLS configuration of the options. Clients must present a certificate signed by a trusted CA.
+func (o TLSOptions) config() (*tls.Config, error) {
+	minVersion, ok := tlsVersions[withDefault(o.MinVersion, defaultMinVersion)]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown TLS version %v", errTLSOption, o.MinVersion)
+	}
+
+	suites := map[string]*tls.CipherSuite{}
+	for _, suite := range tls.CipherSuites() {
+		suites[suite.Name] = suite
+	}
+
+	cipherSuites := []uint16{}
+
+	for _, name := range strings.Split(withDefault(o.CipherSuites, defaultCipherSuites), ",") {
+		suite, ok := suites[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown or insecure cipher suite %v", errTLSOption, name)
+		}
+
+		if !supportsTLS12(suite) {
+			return nil, fmt.Errorf("%w: TLS 1.3 cipher suite %v isn't configurable", errTLSOption, name)
+		}
+
+		cipherSuites = append(cipherSuites, suite.ID)
+	}
+
+	curves := []tls.CurveID{}
+
+	for _, name := range strings.Split(withDefault(o.Curves, defaultCurves), ",") {
+		curve, ok := tlsCurves[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown curve %v", errTLSOption, name)
+		}
+
+		curves = append(curves, curve)
+	}
+
+	return &tls.Config{
+		MinVersion:         minVersion,
+		CurvePreferences:   curves,
+		ClientAuth:         tls.RequireAndVerifyClientCert,
+		InsecureSkipVerify: false,
+		CipherSuites:       cipherSuites,
+	}, nil
+}
+
+func supportsTLS12(suite *tls.CipherSuite) bool {
+	for _, version := range suite.SupportedVersions {
+		if version == tls.VersionTLS12 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func withDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// certReloader holds the server certificate and the client CAs read from their files. The files aren't watched, they're
+// polled: read again every reload period and the certificates replaced when they changed, so that rotated certificates
+// are served without a restart.
+// Files which don't hold a valid certificate, like a certificate and a key written one after the other, are ignored
+// until the next period and the previous certificates kept.
+type certReloader struct {
+	certFile, keyFile, caFile string
+	mtx                       sync.RWMutex
+	certPEM, keyPEM, caPEM    []byte
+	cert                      *tls.Certificate
+	clientCAs                 *x509.CertPool
+}
+
+// newCertReloader returns a reloader with the certificates of the files, or an error if they can't be loaded.
+func newCertReloader(certFile, keyFile, caFile string) (*certReloader, error) {
+	reloader := &certReloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+
+	_, err := reloader.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// reload reads the files and replaces the certificates if they changed. It returns whether they were replaced.
+func (c *certReloader) reload() (bool, error) {
+	certPEM, err := os.ReadFile(c.certFile)
+	if err != nil {
+		return false, fmt.Errorf("cert read failed: %w", err)
+	}
+
+	keyPEM, err := os.ReadFile(c.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("key read failed: %w", err)
+	}
+
+	caPEM, err := os.ReadFile(c.caFile)
+	if err != nil {
+		return false, fmt.Errorf("caCert read failed: %w", err)
+	}
+
+	c.mtx.RLock()
+	unchanged := bytes.Equal(certPEM, c.certPEM) && bytes.Equal(keyPEM, c.keyPEM) && bytes.Equal(caPEM, c.caPEM)
+	c.mtx.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, fmt.Errorf("cert load failed: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return false, fmt.Errorf("%w: %v", errNoCACert, c.caFile)
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.certPEM, c.keyPEM, c.caPEM = certPEM, keyPEM, caPEM
+	c.cert, c.clientCAs = &cert, clientCAs
+
+	return true, nil
+}
+
+// run reloads the certificates every reload period until the context is done.
+func (c *certReloader) run(ctx context.Context) {
+	ticker := time.NewTicker(certReloadPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := c.reload()
+
+		switch {
+		case err != nil:
+			klog.V(l2).InfoS("Certificate reload failed, keeping the previous certificates: "+err.Error(), "component", "extender")
+		case reloaded:
+			klog.V(l2).InfoS("Certificates reloaded", "component", "extender")
+		}
+	}
+}
+
+// tlsConfig returns the configuration serving the current certificates, built from the base configuration.
+func (c *certReloader) tlsConfig(base *tls.Config) *tls.Config {
+	cfg := base.Clone()
+	cfg.GetCertificate = c.certificate
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		clientCfg := base.Clone()
+		clientCfg.GetCertificate = c.certificate
+		clientCfg.ClientCAs = c.currentClientCAs()
+
+		return clientCfg, nil
+	}
+
+	return cfg
+}
+
+func (c *certReloader) certificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.cert, nil
+}
+
+func (c *certReloader) currentClientCAs() *x509.CertPool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.clientCAs
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package extender
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// selfSignedCert returns the PEM encoded certificate and key of a new self-signed certificate for the common name.
+func selfSignedCert(t *testing.T, commonName string) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, name string, data []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func servedCommonName(t *testing.T, reloader *certReloader) string {
+	t.Helper()
+
+	cert, err := reloader.certificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloader_reload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+
+	oldCert, oldKey := selfSignedCert(t, "old")
+	writeFile(t, certFile, oldCert)
+	writeFile(t, keyFile, oldKey)
+	writeFile(t, caFile, oldCert)
+
+	reloader, err := newCertReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("newCertReloader() error = %v", err)
+	}
+
+	if reloaded, err := reloader.reload(); reloaded || err != nil {
+		t.Errorf("reload() of unchanged files = %v, %v, want false, nil", reloaded, err)
+	}
+
+	newCert, newKey := selfSignedCert(t, "new")
+	writeFile(t, certFile, newCert)
+
+	if reloaded, err := reloader.reload(); reloaded || err == nil {
+		t.Errorf("reload() of a new cert with the old key = %v, %v, want false and an error", reloaded, err)
+	}
+
+	if name := servedCommonName(t, reloader); name != "old" {
+		t.Errorf("served %v after a half written cert and key, want old", name)
+	}
+
+	writeFile(t, keyFile, newKey)
+
+	if reloaded, err := reloader.reload(); !reloaded || err != nil {
+		t.Errorf("reload() of a new cert and key = %v, %v, want true, nil", reloaded, err)
+	}
+
+	if name := servedCommonName(t, reloader); name != "new" {
+		t.Errorf("served %v after the cert and key were rotated, want new", name)
+	}
+
+	writeFile(t, caFile, []byte("not a certificate"))
+
+	if reloaded, err := reloader.reload(); reloaded || !errors.Is(err, errNoCACert) {
+		t.Errorf("reload() of an invalid CA = %v, %v, want false, %v", reloaded, err, errNoCACert)
+	}
+
+	if reloader.currentClientCAs() == nil {
+		t.Errorf("client CAs dropped after an invalid CA")
+	}
+}
+
+func TestNewCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+
+	cert, key := selfSignedCert(t, "extender")
+	writeFile(t, certFile, cert)
+	writeFile(t, keyFile, key)
+	writeFile(t, caFile, []byte{})
+
+	tests := []struct {
+		name    string
+		caFile  string
+		wantErr error
+	}{
+		{"missing CA", filepath.Join(dir, "missing.crt"), os.ErrNotExist},
+		{"empty CA", caFile, errNoCACert},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newCertReloader(certFile, keyFile, tt.caFile); !errors.Is(err, tt.wantErr) {
+				t.Errorf("newCertReloader() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSOptions_config(t *testing.T) {
+	tests := []struct {
+		name    string
+		options TLSOptions
+		wantErr bool
+	}{
+		{"defaults", TLSOptions{}, false},
+		{"configured", TLSOptions{MinVersion: "1.3", CipherSuites: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", Curves: "X25519"}, false},
+		{"unknown version", TLSOptions{MinVersion: "1.1"}, true},
+		{"unknown cipher suite", TLSOptions{CipherSuites: "TLS_UNKNOWN"}, true},
+		{"insecure cipher suite", TLSOptions{CipherSuites: "TLS_RSA_WITH_RC4_128_SHA"}, true},
+		{"TLS 1.3 cipher suite", TLSOptions{CipherSuites: "TLS_AES_128_GCM_SHA256"}, true},
+		{"unknown curve", TLSOptions{Curves: "P224"}, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.options.config()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("config() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				if !errors.Is(err, errTLSOption) {
+					t.Errorf("config() error = %v, want %v", err, errTLSOption)
+				}
+
+				return
+			}
+
+			if cfg.ClientAuth != tls.RequireAndVerifyClientCert {
+				t.Errorf("config() client auth = %v, want %v", cfg.ClientAuth, tls.RequireAndVerifyClientCert)
+			}
+		})
+	}
+}
@@ -5,17 +5,44 @@
 package extender
 
 import (
+	"context"
 	"net/http"
+
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
 )
 
-// Scheduler has the capabilities needed to prioritize and filter nodes based on http requests.
+// Scheduler has the capabilities needed to prioritize and filter nodes and to bind pods. The requests of the scheduler
+// are decoded and the results encoded by Handle, so implementations only deal with the scheduler extender types. An
+// implementation not supporting a verb returns ErrNotSupported.
 type Scheduler interface {
-	Bind(w http.ResponseWriter, r *http.Request)
-	Prioritize(w http.ResponseWriter, r *http.Request)
-	Filter(w http.ResponseWriter, r *http.Request)
+	Bind(ctx context.Context, args *extenderv1.ExtenderBindingArgs) (*extenderv1.ExtenderBindingResult, error)
+	Prioritize(ctx context.Context, args *extenderv1.ExtenderArgs) (*extenderv1.HostPriorityList, error)
+	Filter(ctx context.Context, args *extenderv1.ExtenderArgs) (*extenderv1.ExtenderFilterResult, error)
+}
+
+// Explainer is optionally implemented by a Scheduler to explain, on /scheduler/explain, how it filters and prioritizes
+// the nodes for a pod. Its request and response are specific to the Scheduler, which can serve them with Handle.
+type Explainer interface {
+	Explain(w http.ResponseWriter, r *http.Request)
+}
+
+// Preempter is optionally implemented by a Scheduler to take part in preemption, on /scheduler/preempt. It receives
+// the nodes the scheduler could preempt pods on with their victims and returns the nodes and victims it agrees with.
+type Preempter interface {
+	ProcessPreemption(ctx context.Context, args *extenderv1.ExtenderPreemptionArgs) (*extenderv1.ExtenderPreemptionResult, error)
+}
+
+// ReadinessChecker reports whether an extender is ready to serve requests, returning why it isn't otherwise.
+type ReadinessChecker interface {
+	Ready() error
 }
 
-// Server type wraps the implementation of the extender.
+// Server type wraps the implementation of the extender. HealthPort, if set, is the port of the unauthenticated
+// /healthz and /readyz endpoints, served over HTTP. Readiness decides /readyz, which succeeds as soon as the server
+// runs without it. TLSOptions set the TLS versions and algorithms of the extender endpoint.
 type Server struct {
 	Scheduler
+	HealthPort string
+	Readiness  ReadinessChecker
+	TLSOptions TLSOptions
 }
@@ -4,9 +4,12 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/intel/platform-aware-scheduling/extender"
 	"github.com/intel/platform-aware-scheduling/gpu-aware-scheduling/pkg/gpuscheduler"
@@ -31,16 +34,19 @@ const (
 
 func main() {
 	var (
-		kubeConfig, port, certFile, keyFile, caFile, balancedRes string
-		enableAllowlist, enableDenylist                          bool
-		burst, qps                                               uint
+		kubeConfig, port, healthPort, certFile, keyFile, caFile, balancedRes string
+		enableAllowlist, enableDenylist                                      bool
+		burst, qps                                                           uint
+		tlsOptions                                                           extender.TLSOptions
 	)
 
 	flag.StringVar(&kubeConfig, "kubeConfig", "/root/.kube/config", "location of kubernetes config file")
 	flag.StringVar(&port, "port", "9001", "port on which the scheduler extender will listen")
-	flag.StringVar(&certFile, "cert", "/etc/kubernetes/pki/ca.crt", "cert file extender will use for authentication")
-	flag.StringVar(&keyFile, "key", "/etc/kubernetes/pki/ca.key", "key file extender will use for authentication")
-	flag.StringVar(&caFile, "cacert", "/etc/kubernetes/pki/ca.crt", "ca file extender will use for authentication")
+	flag.StringVar(&healthPort, "healthPort", "", "HTTP port of the /healthz and /readyz endpoints, disabled if empty")
+	flag.StringVar(&certFile, "cert", "/etc/kubernetes/pki/ca.crt", "cert file extender will use for authentication, polled every 10s for rotation")
+	flag.StringVar(&keyFile, "key", "/etc/kubernetes/pki/ca.key", "key file extender will use for authentication, polled every 10s for rotation")
+	flag.StringVar(&caFile, "cacert", "/etc/kubernetes/pki/ca.crt", "ca file extender will use for authentication, polled every 10s for rotation")
+	tlsOptions.AddFlags(flag.CommandLine)
 	flag.BoolVar(&enableAllowlist, "enableAllowlist", false, "enable allowed GPUs annotation (csv list of names)")
 	flag.BoolVar(&enableDenylist, "enableDenylist", false, "enable denied GPUs annotation (csv list of names)")
 	flag.StringVar(&balancedRes, "balancedResource", "", "enable resource balacing within a node")
@@ -65,8 +71,19 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	gasscheduler := gpuscheduler.NewGASExtender(kubeClient, enableAllowlist, enableDenylist, balancedRes)
-	sch := extender.Server{Scheduler: gasscheduler}
-	sch.StartServer(port, certFile, keyFile, caFile, false)
+	sch := extender.Server{Scheduler: gasscheduler, HealthPort: healthPort, Readiness: gasscheduler, TLSOptions: tlsOptions}
+
+	err = sch.StartServer(ctx, port, certFile, keyFile, caFile, false)
+	if err != nil {
+		klog.Error("extender server failed: ", err.Error())
+		stop()
+		klog.Flush()
+		os.Exit(1)
+	}
+
 	klog.Flush()
 }
@@ -22,6 +22,10 @@ func (r *cacheAPI) FetchPod(cache *Cache, podNs, podName string) (*v1.Pod, error
 	return cache.fetchPod(podNs, podName)
 }
 
+func (r *cacheAPI) FetchNodePods(cache *Cache, nodeName string) ([]*v1.Pod, error) {
+	return cache.fetchNodePods(nodeName)
+}
+
 func (r *cacheAPI) GetNodeResourceStatus(cache *Cache, nodeName string) nodeResources {
 	return cache.getNodeResourceStatus(nodeName)
 }
@@ -35,3 +39,7 @@ func (r *cacheAPI) AdjustPodResourcesL(cache *Cache, pod *v1.Pod, adj bool, anno
 func (r *cacheAPI) GetNodeTileStatus(cache *Cache, nodeName string) nodeTiles {
 	return cache.getNodeTileStatus(nodeName)
 }
+
+func (r *cacheAPI) IsSynced(cache *Cache) bool {
+	return cache.isSynced()
+}
@@ -59,6 +59,29 @@ func (_m *MockCacheAPI) FetchNode(cache *Cache, nodeName string) (*v1.Node, erro
 	return r0, r1
 }
 
+// FetchNodePods provides a mock function with given fields: cache, nodeName
+func (_m *MockCacheAPI) FetchNodePods(cache *Cache, nodeName string) ([]*v1.Pod, error) {
+	ret := _m.Called(cache, nodeName)
+
+	var r0 []*v1.Pod
+	if rf, ok := ret.Get(0).(func(*Cache, string) []*v1.Pod); ok {
+		r0 = rf(cache, nodeName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*v1.Pod)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*Cache, string) error); ok {
+		r1 = rf(cache, nodeName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FetchPod provides a mock function with given fields: cache, podNS, podName
 func (_m *MockCacheAPI) FetchPod(cache *Cache, podNS string, podName string) (*v1.Pod, error) {
 	ret := _m.Called(cache, podNS, podName)
@@ -114,6 +137,20 @@ func (_m *MockCacheAPI) GetNodeTileStatus(cache *Cache, nodeName string) nodeTil
 	return r0
 }
 
+// IsSynced provides a mock function with given fields: cache
+func (_m *MockCacheAPI) IsSynced(cache *Cache) bool {
+	ret := _m.Called(cache)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*Cache) bool); ok {
+		r0 = rf(cache)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // NewCache provides a mock function with given fields: _a0
 func (_m *MockCacheAPI) NewCache(_a0 kubernetes.Interface) *Cache {
 	ret := _m.Called(_a0)
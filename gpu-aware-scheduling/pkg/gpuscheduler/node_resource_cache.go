@@ -17,6 +17,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -44,6 +45,7 @@ const (
 	pciGroupValue            = "PCI_GROUP"
 	tileString               = "gt"
 	expectedGpuSplitCount    = 2
+	podNodeNameIndex         = "nodeName"
 )
 
 //nolint:gochecknoglobals // only mocked APIs are allowed as globals
@@ -76,6 +78,8 @@ type patchValue struct {
 // all nodes for every scheduled pod.
 // The cache could be accessed from multiple goroutines and therefore needs concurrency protection,
 // which is achieved with a mutex.
+// The cache is synced once the event handlers have seen the initially listed pods and nodes and their work queues
+// have been emptied, so that the resource statuses account for the pods already running.
 type Cache struct {
 	clientset             kubernetes.Interface
 	sharedInformerFactory informers.SharedInformerFactory
@@ -83,6 +87,7 @@ type Cache struct {
 	podWorkQueue          workqueue.RateLimitingInterface
 	nodeWorkQueue         workqueue.RateLimitingInterface
 	podLister             corev1.PodLister
+	podIndexer            cache.Indexer
 	annotatedPods         map[string]string
 	nodeStatuses          map[string]nodeResources
 	nodeTileStatuses      map[string]nodeTiles
@@ -90,6 +95,8 @@ type Cache struct {
 	previousDeschedTiles  map[string][]string /* node -> list of card+tile combos "x.y" */
 	podDeschedStatuses    map[string]bool
 	rwmutex               sync.RWMutex
+	handlersSynced        []cache.InformerSynced
+	synced                atomic.Bool
 }
 
 // Node resources = a map of resourceMaps accessed by node gpu names.
@@ -161,6 +168,13 @@ func NewCache(client kubernetes.Interface) *Cache {
 	nodeLister := nodeInformer.Lister()
 	podInformer := sharedInformerFactory.Core().V1().Pods()
 	podLister := podInformer.Lister()
+
+	if err := podInformer.Informer().AddIndexers(cache.Indexers{podNodeNameIndex: podNodeName}); err != nil {
+		klog.Errorf("pod informer index init failure: %v", err)
+
+		return nil
+	}
+
 	stopChannel := signalHandler()
 
 	klog.V(logL1).Info("starting shared informer factory (cache)")
@@ -192,6 +206,7 @@ func NewCache(client kubernetes.Interface) *Cache {
 		podWorkQueue:          workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "podWorkQueue"),
 		nodeWorkQueue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "nodeWorkQueue"),
 		podLister:             podLister,
+		podIndexer:            podInformer.Informer().GetIndexer(),
 		annotatedPods:         make(map[string]string),
 		nodeStatuses:          make(map[string]nodeResources),
 		nodeTileStatuses:      make(map[string]nodeTiles),
@@ -201,8 +216,8 @@ func NewCache(client kubernetes.Interface) *Cache {
 		rwmutex:               sync.RWMutex{},
 	}
 
-	_, err := podInformer.Informer().AddEventHandler(cache.createFilteringPodResourceHandler())
-	_, err2 := nodeInformer.Informer().AddEventHandler(cache.createFilteringNodeResourceHandler())
+	podRegistration, err := podInformer.Informer().AddEventHandler(cache.createFilteringPodResourceHandler())
+	nodeRegistration, err2 := nodeInformer.Informer().AddEventHandler(cache.createFilteringNodeResourceHandler())
 
 	if err != nil || err2 != nil {
 		klog.Errorf("informer event handler init failure (%v, %v)", err, err2)
@@ -210,12 +225,35 @@ func NewCache(client kubernetes.Interface) *Cache {
 		return nil
 	}
 
+	cache.handlersSynced = append(cache.handlersSynced, podRegistration.HasSynced, nodeRegistration.HasSynced)
+
 	go func() { cache.startPodWork(stopChannel) }()
 	go func() { cache.startNodeWork(stopChannel) }()
 
 	return &cache
 }
 
+// isSynced returns true once the cache is synced. It stays synced afterwards, whatever is queued later.
+func (c *Cache) isSynced() bool {
+	if c.synced.Load() {
+		return true
+	}
+
+	for _, synced := range c.handlersSynced {
+		if !synced() {
+			return false
+		}
+	}
+
+	if c.podWorkQueue.Len() > 0 || c.nodeWorkQueue.Len() > 0 {
+		return false
+	}
+
+	c.synced.Store(true)
+
+	return true
+}
+
 func (c *Cache) podFilter(obj interface{}) bool {
 	var pod *v1.Pod
 
@@ -837,6 +875,34 @@ func (c *Cache) fetchPod(ns, name string) (*v1.Pod, error) {
 	return pod.DeepCopy(), nil
 }
 
+// fetchNodePods returns copies of the cached pods running on a node.
+func (c *Cache) fetchNodePods(nodeName string) ([]*v1.Pod, error) {
+	objs, err := c.podIndexer.ByIndex(podNodeNameIndex, nodeName)
+	if err != nil {
+		return nil, fmt.Errorf("pod list error: %w", err)
+	}
+
+	nodePods := []*v1.Pod{}
+
+	for _, obj := range objs {
+		if pod, ok := obj.(*v1.Pod); ok {
+			nodePods = append(nodePods, pod.DeepCopy())
+		}
+	}
+
+	return nodePods, nil
+}
+
+// podNodeName indexes the cached pods by the name of the node they're assigned to.
+func podNodeName(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return []string{}, nil
+	}
+
+	return []string{pod.Spec.NodeName}, nil
+}
+
 // getNodeTileStatus returns a copy of current tile status for a node.
 func (c *Cache) getNodeTileStatus(nodeName string) nodeTiles {
 	klog.V(logL4).Infof("getNodeTileStatus %v", nodeName)
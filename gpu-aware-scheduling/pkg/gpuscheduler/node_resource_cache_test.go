@@ -52,6 +52,39 @@ func TestNewCache(t *testing.T) {
 	})
 }
 
+func TestCacheIsSynced(t *testing.T) {
+	Convey("When I create a new cache", t, func() {
+		cach := NewCache(fake.NewSimpleClientset())
+		So(cach, ShouldNotBeNil)
+
+		Convey("It is synced once the initial objects are handled", func() {
+			So(cach.isSynced(), ShouldBeTrue)
+		})
+	})
+
+	Convey("When the cache has queued work", t, func() {
+		cach := &Cache{
+			podWorkQueue:  workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+			nodeWorkQueue: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		}
+		cach.nodeWorkQueue.Add(nodeWorkQueueItem{})
+		So(cach.isSynced(), ShouldBeFalse)
+
+		Convey("Or event handlers which haven't synced", func() {
+			cach.nodeWorkQueue.Get()
+			cach.handlersSynced = []cache.InformerSynced{func() bool { return false }}
+			So(cach.isSynced(), ShouldBeFalse)
+		})
+
+		Convey("It stays synced with work queued after it synced", func() {
+			cach.nodeWorkQueue.Get()
+			So(cach.isSynced(), ShouldBeTrue)
+			cach.podWorkQueue.Add(podWorkQueueItem{})
+			So(cach.isSynced(), ShouldBeTrue)
+		})
+	})
+}
+
 //nolint:gochecknoglobals // only test resource
 var dummyCache *Cache
 
@@ -263,6 +296,38 @@ func TestPodWork(t *testing.T) {
 	})
 }
 
+func TestFetchNodePods(t *testing.T) {
+	c := createMockCache()
+	c.podIndexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{podNodeNameIndex: podNodeName})
+
+	for name, nodeName := range map[string]string{"a": "node1", "b": "node2", "c": "node1", "pending": ""} {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, Spec: v1.PodSpec{NodeName: nodeName}}
+		if err := c.podIndexer.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	Convey("When fetching the pods of a node", t, func() {
+		pods, err := c.fetchNodePods("node1")
+		So(err, ShouldBeNil)
+
+		names := []string{}
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+
+		So(names, ShouldHaveLength, 2)
+		So(names, ShouldContain, "a")
+		So(names, ShouldContain, "c")
+	})
+
+	Convey("When fetching the pods of a node without pods", t, func() {
+		pods, err := c.fetchNodePods("node3")
+		So(err, ShouldBeNil)
+		So(pods, ShouldBeEmpty)
+	})
+}
+
 func TestNodeWork(t *testing.T) {
 	// to be able to call work() directly, we need a mock cache which doesn't call work() itself
 	cache := createMockCache()
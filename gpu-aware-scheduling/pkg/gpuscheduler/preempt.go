@@ -0,0 +1,183 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package gpuscheduler
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	ev1 "k8s.io/kube-scheduler/extender/v1"
+)
+
+// ProcessPreemption manages the preemption requests from the scheduler. It calls the preemption logic and returns
+// the nodes and victims to the scheduler.
+func (m *GASExtender) ProcessPreemption(_ context.Context, args *ev1.ExtenderPreemptionArgs) (*ev1.ExtenderPreemptionResult, error) {
+	klog.V(logL4).Info("preemption request received")
+
+	result := m.preemptNodes(args)
+
+	klog.V(logL4).Info("preemption function done")
+
+	return result, nil
+}
+
+// preemptNodes keeps the candidate nodes on which the pod fits once the GPU resources of the victims are released.
+// Of the victims using GPUs on a node only those whose release is needed for the pod to fit are kept, trying to spare
+// them in the order the scheduler sent them, most important first. Victims not using GPUs were chosen by the scheduler
+// for other resources and are kept. The extender isn't told which victims violate a PodDisruptionBudget, so the PDB
+// violations reported by the scheduler are kept, only capped by the number of victims left.
+func (m *GASExtender) preemptNodes(args *ev1.ExtenderPreemptionArgs) *ev1.ExtenderPreemptionResult {
+	result := &ev1.ExtenderPreemptionResult{NodeNameToMetaVictims: map[string]*ev1.MetaVictims{}}
+
+	if err := checkPod(args.Pod); err != nil {
+		klog.Errorf("preemption failed: %v", err)
+
+		return result
+	}
+
+	candidates, victimPods := preemptionCandidates(args)
+
+	m.rwmutex.Lock()
+	klog.V(logL5).Infof("preempt %v:%v locked", args.Pod.Namespace, args.Pod.Name)
+	defer m.rwmutex.Unlock()
+
+	for nodeName, victims := range candidates {
+		if kept := m.preemptionVictims(args.Pod, nodeName, victims, victimPods); kept != nil {
+			result.NodeNameToMetaVictims[nodeName] = kept
+		}
+	}
+
+	return result
+}
+
+// preemptionCandidates returns the victims of each candidate node by UID, and the victim pods by UID if the
+// scheduler sent them. The scheduler sends only the UIDs to nodeCacheCapable extenders.
+func preemptionCandidates(args *ev1.ExtenderPreemptionArgs) (map[string]*ev1.MetaVictims, map[string]*v1.Pod) {
+	if args.NodeNameToMetaVictims != nil {
+		return args.NodeNameToMetaVictims, nil
+	}
+
+	candidates := map[string]*ev1.MetaVictims{}
+	victimPods := map[string]*v1.Pod{}
+
+	for nodeName, victims := range args.NodeNameToVictims {
+		if victims == nil {
+			continue
+		}
+
+		candidates[nodeName] = &ev1.MetaVictims{Pods: []*ev1.MetaPod{}, NumPDBViolations: victims.NumPDBViolations}
+
+		for _, pod := range victims.Pods {
+			candidates[nodeName].Pods = append(candidates[nodeName].Pods, &ev1.MetaPod{UID: string(pod.UID)})
+			victimPods[string(pod.UID)] = pod
+		}
+	}
+
+	return candidates, victimPods
+}
+
+// preemptionVictims returns the victims to preempt on the node for the pod to fit, or nil if the pod doesn't fit
+// even with every victim preempted. This must be called with rwmutex locked.
+func (m *GASExtender) preemptionVictims(pod *v1.Pod, nodeName string, victims *ev1.MetaVictims,
+	victimPods map[string]*v1.Pod,
+) *ev1.MetaVictims {
+	if victims == nil {
+		return nil
+	}
+
+	node, err := m.getNodeForName(nodeName)
+	if err != nil {
+		return nil
+	}
+
+	gpuVictims := m.gpuVictims(nodeName, victims, victimPods)
+	releasing := map[string]bool{}
+
+	for uid := range gpuVictims {
+		releasing[uid] = true
+	}
+
+	if !m.fitsReleasing(pod, node, gpuVictims, releasing) {
+		klog.V(logL4).Infof("pod %v:%v doesn't fit node %v even with the victims preempted", pod.Namespace, pod.Name, nodeName)
+
+		return nil
+	}
+
+	kept := &ev1.MetaVictims{Pods: []*ev1.MetaPod{}, NumPDBViolations: victims.NumPDBViolations}
+
+	for _, victim := range victims.Pods {
+		if _, ok := gpuVictims[victim.UID]; ok {
+			releasing[victim.UID] = false
+
+			if m.fitsReleasing(pod, node, gpuVictims, releasing) {
+				klog.V(logL4).Infof("victim %v on node %v spared, its GPU resources aren't needed", victim.UID, nodeName)
+
+				continue
+			}
+
+			releasing[victim.UID] = true
+		}
+
+		kept.Pods = append(kept.Pods, victim)
+	}
+
+	kept.NumPDBViolations = min(kept.NumPDBViolations, int64(len(kept.Pods)))
+
+	return kept
+}
+
+// gpuVictims returns the victims with GPUs assigned on the node by UID.
+func (m *GASExtender) gpuVictims(nodeName string, victims *ev1.MetaVictims, victimPods map[string]*v1.Pod) map[string]*v1.Pod {
+	if victimPods == nil {
+		victimPods = map[string]*v1.Pod{}
+
+		nodePods, err := iCache.FetchNodePods(m.cache, nodeName)
+		if err != nil {
+			klog.Warningf("Pods of node %s couldn't be read: %v", nodeName, err)
+		}
+
+		for _, pod := range nodePods {
+			victimPods[string(pod.UID)] = pod
+		}
+	}
+
+	gpuVictims := map[string]*v1.Pod{}
+
+	for _, victim := range victims.Pods {
+		if pod, ok := victimPods[victim.UID]; ok && pod.Annotations[cardAnnotationName] != "" {
+			gpuVictims[victim.UID] = pod
+		}
+	}
+
+	return gpuVictims
+}
+
+// fitsReleasing checks if the pod fits the node with the GPU resources of the releasing victims released.
+// This must be called with rwmutex locked.
+func (m *GASExtender) fitsReleasing(pod *v1.Pod, node *v1.Node, gpuVictims map[string]*v1.Pod, releasing map[string]bool) bool {
+	view := &Cache{
+		annotatedPods:    map[string]string{},
+		nodeStatuses:     map[string]nodeResources{node.Name: iCache.GetNodeResourceStatus(m.cache, node.Name)},
+		nodeTileStatuses: map[string]nodeTiles{node.Name: iCache.GetNodeTileStatus(m.cache, node.Name)},
+	}
+
+	for uid, release := range releasing {
+		victim := gpuVictims[uid]
+		if !release || victim == nil {
+			continue
+		}
+
+		err := view.adjustPodResources(victim, remove, victim.Annotations[cardAnnotationName],
+			victim.Annotations[tileAnnotationName], node.Name)
+		if err != nil {
+			klog.Warningf("resources of victim %v couldn't be released: %v", uid, err)
+		}
+	}
+
+	released := &nodeState{nodeName: node.Name, resources: view.nodeStatuses[node.Name], tiles: view.nodeTileStatuses[node.Name]}
+	_, _, err := m.checkForSpaceAndRetrieveCards(pod, node, released)
+
+	return err == nil
+}
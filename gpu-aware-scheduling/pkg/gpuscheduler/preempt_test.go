@@ -0,0 +1,128 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !validation
+// +build !validation
+
+//nolint:testpackage
+package gpuscheduler
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/mock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+)
+
+func getVictimPod(uid, cards, pluginResourceName string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: uid, Namespace: "default", UID: types.UID(uid), Annotations: map[string]string{}},
+		Spec:       *getMockPodSpec(pluginResourceName),
+	}
+
+	if cards != "" {
+		pod.Annotations[cardAnnotationName] = cards
+	}
+
+	return pod
+}
+
+func victimUIDs(victims *extenderv1.MetaVictims) []string {
+	uids := []string{}
+	for _, pod := range victims.Pods {
+		uids = append(uids, pod.UID)
+	}
+
+	return uids
+}
+
+func TestPreemptNodes(t *testing.T) {
+	for _, pluginResourceName := range []string{i915PluginResource, xePluginResource} {
+		gas := getEmptyExtender()
+		mockCache := MockCacheAPI{}
+		origCacheAPI := iCache
+		iCache = &mockCache
+		pod := getFakePod(pluginResourceName)
+		node := getMockNode(1, 1, pluginResourceName, card0, "card1")
+		node.Name = nodename
+		// the cache returns a copy of the used resources, as the preemption releases victims from it
+		used := func(*Cache, string) nodeResources {
+			return nodeResources{card0: resourceMap{pluginResourceName: 1}, "card1": resourceMap{pluginResourceName: 1}}
+		}
+		gpuVictimA := getVictimPod("a", card0, pluginResourceName)
+		gpuVictimB := getVictimPod("b", "card1", pluginResourceName)
+		otherVictim := getVictimPod("c", "", pluginResourceName)
+
+		Convey("When the pod fits once a single GPU victim is preempted", t, func() {
+			mockCache.On("FetchNode", mock.Anything, nodename).Return(node, nil)
+			mockCache.On("GetNodeResourceStatus", mock.Anything, nodename).Return(used)
+			mockCache.On("GetNodeTileStatus", mock.Anything, nodename).Return(nodeTiles{})
+
+			args := extenderv1.ExtenderPreemptionArgs{Pod: pod, NodeNameToVictims: map[string]*extenderv1.Victims{
+				nodename: {Pods: []*v1.Pod{gpuVictimA, otherVictim, gpuVictimB}, NumPDBViolations: 1},
+			}}
+			result := gas.preemptNodes(&args)
+
+			Convey("the first GPU victim is spared and the other victims are kept", func() {
+				So(result.NodeNameToMetaVictims, ShouldContainKey, nodename)
+				So(victimUIDs(result.NodeNameToMetaVictims[nodename]), ShouldResemble, []string{"c", "b"})
+				So(result.NodeNameToMetaVictims[nodename].NumPDBViolations, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the scheduler sends only the victim UIDs", t, func() {
+			mockCache.On("FetchNodePods", mock.Anything, nodename).Return([]*v1.Pod{gpuVictimA, gpuVictimB}, nil).Once()
+
+			args := extenderv1.ExtenderPreemptionArgs{Pod: pod, NodeNameToMetaVictims: map[string]*extenderv1.MetaVictims{
+				nodename: {Pods: []*extenderv1.MetaPod{{UID: "b"}, {UID: "a"}}},
+			}}
+			result := gas.preemptNodes(&args)
+
+			Convey("the victims are looked up on the node", func() {
+				So(victimUIDs(result.NodeNameToMetaVictims[nodename]), ShouldResemble, []string{"a"})
+			})
+		})
+
+		Convey("When the scheduler reports more PDB violations than victims are kept", t, func() {
+			args := extenderv1.ExtenderPreemptionArgs{Pod: pod, NodeNameToVictims: map[string]*extenderv1.Victims{
+				nodename: {Pods: []*v1.Pod{gpuVictimA, gpuVictimB}, NumPDBViolations: 2},
+			}}
+			result := gas.preemptNodes(&args)
+
+			Convey("the violations are capped by the kept victims", func() {
+				So(victimUIDs(result.NodeNameToMetaVictims[nodename]), ShouldResemble, []string{"b"})
+				So(result.NodeNameToMetaVictims[nodename].NumPDBViolations, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the pod doesn't fit even with every victim preempted", t, func() {
+			args := extenderv1.ExtenderPreemptionArgs{Pod: pod, NodeNameToVictims: map[string]*extenderv1.Victims{
+				nodename: {Pods: []*v1.Pod{otherVictim}},
+			}}
+			result := gas.preemptNodes(&args)
+
+			Convey("the node is dropped", func() {
+				So(result.NodeNameToMetaVictims, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the node can't be read", t, func() {
+			mockCache.On("FetchNode", mock.Anything, "vanished").Return(nil, errMock).Once()
+
+			args := extenderv1.ExtenderPreemptionArgs{Pod: pod, NodeNameToVictims: map[string]*extenderv1.Victims{
+				"vanished": {Pods: []*v1.Pod{gpuVictimA}},
+			}}
+			result := gas.preemptNodes(&args)
+
+			Convey("the node is dropped", func() {
+				So(result.NodeNameToMetaVictims, ShouldBeEmpty)
+			})
+		})
+
+		iCache = origCacheAPI
+	}
+}
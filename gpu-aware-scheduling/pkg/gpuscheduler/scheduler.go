@@ -10,13 +10,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/http/httputil"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/intel/platform-aware-scheduling/extender"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -71,13 +71,14 @@ var (
 // Errors.
 var (
 	errNotFound    = errors.New("not found")
-	errEmptyBody   = errors.New("request body empty")
-	errDecode      = errors.New("error decoding request")
+	errFilter      = errors.New("filtering failed")
+	errBind        = errors.New("bind failed")
 	errWontFit     = errors.New("will not fit")
 	errExtractFail = errors.New("failed to extract value(s)")
 	errBadUID      = errors.New("provided UID is incorrect")
 	errAnnotation  = errors.New("malformed annotation")
 	errResConflict = errors.New("resources conflict")
+	errNotSynced   = errors.New("GPU resource cache not synced")
 )
 
 //nolint:gochecknoinits // only mocked APIs are allowed in here
@@ -95,6 +96,13 @@ type GASExtender struct {
 	denylistEnabled  bool
 }
 
+// nodeState is the GPU resources and tiles used on a node.
+type nodeState struct {
+	nodeName  string
+	resources nodeResources
+	tiles     nodeTiles
+}
+
 // Card represents a selected gpuName and optional xeLinkedTileIds to be used.
 type Card struct {
 	gpuName         string
@@ -604,6 +612,7 @@ func (m *GASExtender) getXELinkedCardsForContainerGPURequest(containerRequest, p
 	nodeResourcesUsed nodeResources,
 	nodeTilesAllocating nodeTiles,
 	gpuMap map[string]bool,
+	state *nodeState,
 ) ([]Card, bool, error) {
 	var preferred bool
 
@@ -636,7 +645,7 @@ func (m *GASExtender) getXELinkedCardsForContainerGPURequest(containerRequest, p
 		}
 
 		availableTiles := m.createAvailableXeLinkedTilesStat(node,
-			int(perGPUCapacity[gpuTileResource]), gpuNames, nodeTilesAllocating)
+			int(perGPUCapacity[gpuTileResource]), gpuNames, nodeTilesAllocating, state)
 
 		cardPair, err := m.findXeLinkedGPUPair(gpuNames, node, pod, nodeResourcesUsed, availableTiles, nodeTilesAllocating,
 			perGPUResourceRequest, perGPUCapacity, gpuMap, usedGPUmap)
@@ -848,7 +857,8 @@ func createGPUMaps(pod *v1.Pod, node *v1.Node, allGPUs []string) []map[string]bo
 
 // checkForSpaceAndRetrieveCards checks if pod fits into a node and returns the cards (gpus)
 // that are assigned to each container. If pod doesn't fit or any other error triggers, error is returned.
-func (m *GASExtender) checkForSpaceAndRetrieveCards(pod *v1.Pod, node *v1.Node) ([][]Card, bool, error) {
+// The GPU resources and tiles used on the node are read from state, or from the cache if state is nil.
+func (m *GASExtender) checkForSpaceAndRetrieveCards(pod *v1.Pod, node *v1.Node, state *nodeState) ([][]Card, bool, error) {
 	preferred := false
 	containerCards := [][]Card{}
 
@@ -874,7 +884,11 @@ func (m *GASExtender) checkForSpaceAndRetrieveCards(pod *v1.Pod, node *v1.Node)
 
 	perGPUCapacity := getPerGPUResourceCapacity(node, gpuCount)
 
-	nodeResourcesUsed, err := m.readNodeResources(node.Name)
+	if state == nil {
+		state = m.cachedNodeState(node.Name)
+	}
+
+	nodeResourcesUsed, err := state.resourceStatus()
 	if err != nil {
 		klog.Warningf("Node %s resources couldn't be read or node vanished", node.Name)
 
@@ -888,7 +902,7 @@ func (m *GASExtender) checkForSpaceAndRetrieveCards(pod *v1.Pod, node *v1.Node)
 
 	// create map for unavailable resources
 	tilesPerGpu := perGPUCapacity[gpuTileResource]
-	unavailableResources := m.createUnavailableNodeResources(node, tilesPerGpu)
+	unavailableResources := m.createUnavailableNodeResources(node, tilesPerGpu, state)
 
 	klog.V(logL4).Infof("Node %v unavailable resources: %v", node.Name, unavailableResources)
 
@@ -900,13 +914,13 @@ func (m *GASExtender) checkForSpaceAndRetrieveCards(pod *v1.Pod, node *v1.Node)
 	klog.V(logL4).Infof("Node %v used resources: %v", node.Name, nodeResourcesUsed)
 
 	containerCards, preferred, err = m.checkForSpaceResourceRequests(
-		perGPUCapacity, pod, node, nodeResourcesUsed, gpuMaps)
+		perGPUCapacity, pod, node, nodeResourcesUsed, gpuMaps, state)
 
 	return containerCards, preferred, err
 }
 
 func (m *GASExtender) checkForSpaceResourceRequests(perGPUCapacity resourceMap, pod *v1.Pod, node *v1.Node,
-	nodeResourcesUsed nodeResources, gpuMaps []map[string]bool,
+	nodeResourcesUsed nodeResources, gpuMaps []map[string]bool, state *nodeState,
 ) ([][]Card, bool, error) {
 	var err error
 
@@ -949,7 +963,7 @@ func (m *GASExtender) checkForSpaceResourceRequests(perGPUCapacity resourceMap,
 
 			if _, ok := pod.Annotations[xelinkAnnotationName]; ok {
 				cards, preferred, err = m.getXELinkedCardsForContainerGPURequest(containerRequest, perGPUCapacity,
-					node, pod, nodeResourcesUsed, nodeTilesAllocating, gpuMap)
+					node, pod, nodeResourcesUsed, nodeTilesAllocating, gpuMap, state)
 			} else {
 				cards, preferred, err = m.getCardsForContainerGPURequest(containerRequest, perGPUCapacity,
 					node, pod, nodeResourcesUsed, gpuMap)
@@ -1089,13 +1103,15 @@ func containerHasTiles(resources resourceMap) bool {
 
 // createUnavailableTilesStat returns disabled+descheduled+used+unusable (e.g. currently allocating)
 // tiles. May have duplicate indices.
-func (m *GASExtender) createUnavailableTilesStat(node *v1.Node, tilesPerGpu int, unusableTiles nodeTiles) nodeTiles {
+func (m *GASExtender) createUnavailableTilesStat(node *v1.Node, tilesPerGpu int, unusableTiles nodeTiles,
+	state *nodeState,
+) nodeTiles {
 	disabledTilesMap := createDisabledTileMapping(node.Labels)
 	// it is possible to have an invalid rule which would disable a non existing
 	// tile which would reduce the available resources even though it's not needed
 	disabledTilesMap = sanitizeTiles(disabledTilesMap, tilesPerGpu)
 
-	usedTilesStats := iCache.GetNodeTileStatus(m.cache, node.Name)
+	usedTilesStats := state.tileStatus()
 	combineMappings(disabledTilesMap, usedTilesStats)
 	// node tile status doesn't include currently allocating tiles yet
 	combineMappings(unusableTiles, usedTilesStats)
@@ -1109,10 +1125,11 @@ func (m *GASExtender) createAvailableXeLinkedTilesStat(node *v1.Node,
 	tileCapacityPerGPU int,
 	gpuNames []string,
 	nodeTilesAllocating nodeTiles,
+	state *nodeState,
 ) nodeTiles {
 	availableTiles := nodeTiles{}
 
-	unavailableTiles := m.createUnavailableTilesStat(node, tileCapacityPerGPU, nodeTilesAllocating)
+	unavailableTiles := m.createUnavailableTilesStat(node, tileCapacityPerGPU, nodeTilesAllocating, state)
 
 	for _, gpuName := range gpuNames {
 		gpuAvailableTiles := getXeLinkedTiles(gpuName, node)
@@ -1136,7 +1153,7 @@ func (m *GASExtender) createAvailableXeLinkedTilesStat(node *v1.Node,
 	return availableTiles
 }
 
-func (m *GASExtender) createUnavailableNodeResources(node *v1.Node, tileCapacityPerGPU int64) nodeResources {
+func (m *GASExtender) createUnavailableNodeResources(node *v1.Node, tileCapacityPerGPU int64, state *nodeState) nodeResources {
 	nodeRes := nodeResources{}
 
 	// for now, only "supported" unavailable resource is tiles
@@ -1145,7 +1162,7 @@ func (m *GASExtender) createUnavailableNodeResources(node *v1.Node, tileCapacity
 	// tile which would reduce the available resources even though it's not needed
 	disabledTilesMap = sanitizeTiles(disabledTilesMap, int(tileCapacityPerGPU))
 
-	usedTilesStats := iCache.GetNodeTileStatus(m.cache, node.Name)
+	usedTilesStats := state.tileStatus()
 
 	// iterate over the disabled and the used tiles
 	// for the tiles that are disabled but _not_ used, increase the usage
@@ -1323,7 +1340,7 @@ func (m *GASExtender) bindNode(ctx context.Context, args *ev1.ExtenderBindingArg
 		return result
 	}
 
-	cards, _, err := m.checkForSpaceAndRetrieveCards(pod, node)
+	cards, _, err := m.checkForSpaceAndRetrieveCards(pod, node, nil)
 	if err != nil {
 		return result
 	}
@@ -1407,7 +1424,7 @@ func (m *GASExtender) filterNodes(args *ev1.ExtenderArgs) *ev1.ExtenderFilterRes
 			continue
 		}
 
-		if _, preferred, err := m.checkForSpaceAndRetrieveCards(args.Pod, node); err == nil {
+		if _, preferred, err := m.checkForSpaceAndRetrieveCards(args.Pod, node, nil); err == nil {
 			if preferred {
 				preferredNodeNames = append(preferredNodeNames, nodeName)
 			} else {
@@ -1429,102 +1446,48 @@ func (m *GASExtender) filterNodes(args *ev1.ExtenderArgs) *ev1.ExtenderFilterRes
 	return result
 }
 
-// decodeRequest reads the json request into the given interface args.
-// It returns an error if the request is not in the required format.
-func (m *GASExtender) decodeRequest(args interface{}, request *http.Request) error {
-	if request.Body == nil {
-		return errEmptyBody
-	}
-
-	if klog.V(logL5).Enabled() {
-		requestDump, err := httputil.DumpRequest(request, true)
-		if err == nil {
-			klog.Infof("http-request:\n%v", string(requestDump))
-		}
-	}
-
-	decoder := json.NewDecoder(request.Body)
-
-	if err := decoder.Decode(&args); err != nil {
-		return errDecode
+// Ready returns an error until the cache of GPU resources used by the pods and nodes is synced.
+func (m *GASExtender) Ready() error {
+	if m.cache == nil || !iCache.IsSynced(m.cache) {
+		return errNotSynced
 	}
 
-	err := request.Body.Close()
-	if err != nil {
-		err = fmt.Errorf("failed to close request body: %w", err)
-	}
-
-	return err
-}
-
-// writeResponse takes the incoming interface and writes it as a http response if valid.
-func (m *GASExtender) writeResponse(w http.ResponseWriter, result interface{}) {
-	encoder := json.NewEncoder(w)
-	if err := encoder.Encode(result); err != nil {
-		http.Error(w, "Encode error", http.StatusBadRequest)
-	}
+	return nil
 }
 
 // Prioritize manages all prioritize requests from the scheduler extender.
-// Not implemented yet by GAS, hence response with StatusNotFound.
-func (m *GASExtender) Prioritize(w http.ResponseWriter, _ *http.Request) {
-	w.WriteHeader(http.StatusNotFound)
+// Not implemented yet by GAS.
+func (m *GASExtender) Prioritize(context.Context, *ev1.ExtenderArgs) (*ev1.HostPriorityList, error) {
+	return nil, extender.ErrNotSupported
 }
 
-// Filter manages all filter requests from the scheduler. First it decodes the request,
-// then it calls the filter logic and writes a response to the scheduler.
-func (m *GASExtender) Filter(writer http.ResponseWriter, request *http.Request) {
+// Filter manages all filter requests from the scheduler. It calls the filter logic and returns its result, failing
+// the request if the filtering failed.
+func (m *GASExtender) Filter(_ context.Context, args *ev1.ExtenderArgs) (*ev1.ExtenderFilterResult, error) {
 	klog.V(logL4).Info("filter request received")
 
-	// extenderArgs is too big of a struct for any sane create-function, funlen would fail
-	//nolint:exhaustruct
-	extenderArgs := ev1.ExtenderArgs{}
-
-	err := m.decodeRequest(&extenderArgs, request)
-	if err != nil {
-		klog.Errorf("cannot decode request %v", err)
-		writer.WriteHeader(http.StatusNotFound)
-
-		return
-	}
-
-	filteredNodes := m.filterNodes(&extenderArgs)
+	filteredNodes := m.filterNodes(args)
 	if filteredNodes.Error != "" {
-		klog.Error("filtering failed")
-		writer.WriteHeader(http.StatusNotFound)
+		return nil, extender.WithStatus(fmt.Errorf("%w: %v", errFilter, filteredNodes.Error), http.StatusNotFound)
 	}
 
-	m.writeResponse(writer, filteredNodes)
-	klog.V(logL4).Info("filter function done, responded")
+	klog.V(logL4).Info("filter function done")
+
+	return filteredNodes, nil
 }
 
 // Bind binds the pod to the node.
-func (m *GASExtender) Bind(writer http.ResponseWriter, request *http.Request) {
+func (m *GASExtender) Bind(ctx context.Context, args *ev1.ExtenderBindingArgs) (*ev1.ExtenderBindingResult, error) {
 	klog.V(logL4).Info("bind request received")
 
-	extenderArgs := ev1.ExtenderBindingArgs{
-		PodName:      "",
-		PodNamespace: "",
-		PodUID:       "",
-		Node:         "",
-	}
-
-	err := m.decodeRequest(&extenderArgs, request)
-	if err != nil {
-		klog.Errorf("cannot decode request %v", err)
-		writer.WriteHeader(http.StatusNotFound)
-
-		return
-	}
-
-	result := m.bindNode(request.Context(), &extenderArgs)
+	result := m.bindNode(ctx, args)
 	if result.Error != "" {
-		klog.Error("bind failed")
-		writer.WriteHeader(http.StatusNotFound)
+		return nil, extender.WithStatus(fmt.Errorf("%w: %v", errBind, result.Error), http.StatusNotFound)
 	}
 
-	m.writeResponse(writer, result)
-	klog.V(logL4).Info("bind function done, responded")
+	klog.V(logL4).Info("bind function done")
+
+	return result, nil
 }
 
 // error handler deals with requests sent to an invalid endpoint and returns a 404.
@@ -1534,16 +1497,37 @@ func (m *GASExtender) errorHandler(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusNotFound)
 }
 
-func (m *GASExtender) readNodeResources(nodeName string) (nodeResources, error) {
-	var err error
+// cachedNodeState returns the GPU resources and tiles used on the node as cached.
+func (m *GASExtender) cachedNodeState(nodeName string) *nodeState {
+	return &nodeState{
+		nodeName:  nodeName,
+		resources: iCache.GetNodeResourceStatus(m.cache, nodeName),
+		tiles:     iCache.GetNodeTileStatus(m.cache, nodeName),
+	}
+}
+
+// resourceStatus returns a copy of the GPU resources used on the node, or an error if the node isn't known.
+func (s *nodeState) resourceStatus() (nodeResources, error) {
+	if s.resources == nil {
+		return nil, errNotFound
+	}
 
-	resources := iCache.GetNodeResourceStatus(m.cache, nodeName)
+	resources := nodeResources{}
+	for cardName, used := range s.resources {
+		resources[cardName] = used.newCopy()
+	}
 
-	if resources == nil {
-		err = errNotFound
+	return resources, nil
+}
+
+// tileStatus returns a copy of the tiles used on the node.
+func (s *nodeState) tileStatus() nodeTiles {
+	tiles := nodeTiles{}
+	for gpuName, used := range s.tiles {
+		tiles[gpuName] = append([]int{}, used...)
 	}
 
-	return resources, err
+	return tiles
 }
 
 // return search map of container names that should have same GPU based on samegpuAnnotationName.
@@ -18,6 +18,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/intel/platform-aware-scheduling/extender"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/mock"
 	v1 "k8s.io/api/core/v1"
@@ -261,6 +262,31 @@ func TestSchedulingLogicBadParams(t *testing.T) {
 	iCache = origCacheAPI
 }
 
+func TestReady(t *testing.T) {
+	gas := &GASExtender{}
+	mockCache := MockCacheAPI{}
+	origCacheAPI := iCache
+	iCache = &mockCache
+
+	Convey("When the extender has no cache", t, func() {
+		So(gas.Ready(), ShouldEqual, errNotSynced)
+	})
+
+	gas.cache = &Cache{}
+
+	Convey("When the cache isn't synced", t, func() {
+		mockCache.On("IsSynced", mock.Anything).Return(false).Once()
+		So(gas.Ready(), ShouldEqual, errNotSynced)
+	})
+
+	Convey("When the cache is synced", t, func() {
+		mockCache.On("IsSynced", mock.Anything).Return(true).Once()
+		So(gas.Ready(), ShouldBeNil)
+	})
+
+	iCache = origCacheAPI
+}
+
 type testWriter struct {
 	headerStatus int
 }
@@ -309,12 +335,19 @@ func TestReadNodeResources(t *testing.T) {
 	Convey("When cache is nil", t, func() {
 		mockCache.On("NewCache", mock.Anything).Return(nil)
 		mockCache.On("GetNodeResourceStatus", mock.Anything, mock.Anything).Return(nodeResources{})
+		mockCache.On("GetNodeTileStatus", mock.Anything, mock.Anything).Return(nodeTiles{})
 		gas := getEmptyExtender()
-		resources, err := gas.readNodeResources("mocknode")
+		resources, err := gas.cachedNodeState("mocknode").resourceStatus()
 		So(err, ShouldBeNil)
 		So(len(resources), ShouldEqual, 0)
 	})
 
+	Convey("When the node isn't known", t, func() {
+		state := &nodeState{nodeName: "mocknode"}
+		_, err := state.resourceStatus()
+		So(err, ShouldEqual, errNotFound)
+	})
+
 	iCache = origCacheAPI
 }
 
@@ -552,26 +585,17 @@ func TestGPUDisabling(t *testing.T) {
 	}
 }
 
-func TestWriteResponse(t *testing.T) {
-	gas := getEmptyExtender()
-
-	Convey("When writeResponse is called with nil response", t, func() {
-		w := testWriter{}
-		gas.writeResponse(&w, nil)
-		So(w.headerStatus, ShouldEqual, http.StatusBadRequest)
-	})
-}
-
 func TestDecodeRequest(t *testing.T) {
 	gas := getEmptyExtender()
 
 	Convey("When decoding something not really JSON", t, func() {
+		writer := testWriter{}
 		request, err := http.NewRequestWithContext(context.Background(),
 			http.MethodPost, "http://foo/bar", bytes.NewBufferString("foo"))
 		So(err, ShouldBeNil)
 		request.Header.Set("Content-Type", "application/json")
-		err = gas.decodeRequest("foo", request)
-		So(err, ShouldNotBeNil)
+		extender.Handle(gas.Filter)(&writer, request)
+		So(writer.headerStatus, ShouldEqual, http.StatusBadRequest)
 	})
 }
 
@@ -632,7 +656,8 @@ func TestFilter(t *testing.T) {
 			request.ContentLength = 100
 			request.Header = http.Header{}
 			request.Header.Set("Content-Type", "application/json")
-			gas.Filter(&writer, &request)
+			extender.Handle(gas.Filter)(&writer, &request)
+			So(writer.headerStatus, ShouldEqual, http.StatusBadRequest)
 		})
 		Convey("when args are fine but request body is ok", func() {
 			content, err := json.Marshal(map[string]string{"foo": "bar"})
@@ -641,7 +666,8 @@ func TestFilter(t *testing.T) {
 				http.MethodPost, "http://foo/bar", bytes.NewBuffer(content))
 			So(err, ShouldBeNil)
 			request.Header.Set("Content-Type", "application/json")
-			gas.Filter(&writer, request)
+			extender.Handle(gas.Filter)(&writer, request)
+			So(writer.headerStatus, ShouldEqual, http.StatusNotFound)
 		})
 	})
 }
@@ -661,7 +687,8 @@ func TestBind(t *testing.T) {
 			request.ContentLength = 100
 			request.Header = http.Header{}
 			request.Header.Set("Content-Type", "application/json")
-			gas.Bind(&writer, &request)
+			extender.Handle(gas.Bind)(&writer, &request)
+			So(writer.headerStatus, ShouldEqual, http.StatusBadRequest)
 		})
 		Convey("when args are fine but request body is ok", func() {
 			content, err := json.Marshal(map[string]string{"foo": "bar"})
@@ -671,7 +698,8 @@ func TestBind(t *testing.T) {
 			So(err, ShouldBeNil)
 			request.Header.Set("Content-Type", "application/json")
 			mockCache.On("FetchPod", mock.Anything, mock.Anything, mock.Anything).Return(nil, errMock).Once()
-			gas.Bind(&writer, request)
+			extender.Handle(gas.Bind)(&writer, request)
+			So(writer.headerStatus, ShouldEqual, http.StatusNotFound)
 		})
 	})
 
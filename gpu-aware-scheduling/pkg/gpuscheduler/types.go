@@ -16,9 +16,11 @@ type CacheAPI interface {
 	NewCache(client kubernetes.Interface) *Cache
 	FetchNode(cache *Cache, nodeName string) (*v1.Node, error)
 	FetchPod(cache *Cache, podNS, podName string) (*v1.Pod, error)
+	FetchNodePods(cache *Cache, nodeName string) ([]*v1.Pod, error)
 	GetNodeResourceStatus(cache *Cache, nodeName string) nodeResources
 	GetNodeTileStatus(cache *Cache, nodeName string) nodeTiles
 	AdjustPodResourcesL(cache *Cache, pod *v1.Pod, adj bool, annotation, tileAnnotation, nodeName string) error
+	IsSynced(cache *Cache) bool
 }
 
 // InternalCacheAPI has the mocked interface of Cache internals.
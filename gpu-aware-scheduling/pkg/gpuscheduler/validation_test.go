@@ -13,6 +13,7 @@
 package gpuscheduler
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
@@ -49,11 +50,13 @@ func TestValidation(t *testing.T) {
 		panic(err)
 	}
 
-	gasscheduler := NewGASExtender(kubeClient, true, true)
+	gasscheduler := NewGASExtender(kubeClient, true, true, "")
 	sch := extender.Server{Scheduler: gasscheduler}
 	c := make(chan bool)
 	go preStopServer(c)
-	go sch.StartServer(*port, *certFile, *keyFile, *caFile, *unsafe)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = sch.StartServer(ctx, *port, *certFile, *keyFile, *caFile, *unsafe) }()
 	<-c
 }
 
@@ -4,9 +4,13 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"flag"
 	"os"
 
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/homedir"
 
 	"os/signal"
@@ -18,12 +22,17 @@ import (
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/controller"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
 	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
-
-	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/deschedule"
-	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/dontschedule"
-	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/labeling"
-	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/scheduleonmetric"
-	telemetrypolicyclient "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/client/v1alpha1"
+	// Strategy packages register their strategy type with the core registry when imported.
+	_ "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/deschedule"
+	_ "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/dontschedule"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/evict"
+	_ "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/labeling"
+	_ "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/nodecondition"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/notify"
+	_ "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/scheduleonmetric"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	telemetrypolicyclient "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/client/v1beta1"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/validation"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetryscheduler"
 	"k8s.io/klog/v2"
 
@@ -35,29 +44,140 @@ import (
 const l2 = 2
 
 func main() {
-	var kubeConfig, port, certFile, keyFile, caFile, syncPeriod string
+	var kubeConfig, port, certFile, keyFile, caFile, syncPeriod, notifySecretFile string
+
+	var webhookPort, webhookCertFile, webhookKeyFile, healthPort string
+
+	var tlsOptions extender.TLSOptions
+
+	evictLimits := evict.DefaultLimits()
+	notifyConfig := notify.DefaultConfig()
+
+	var policyResync, metricHistory time.Duration
 
 	klog.InitFlags(nil)
 	flag.StringVar(&kubeConfig, "kubeConfig", filepath.Join(homedir.HomeDir(), ".kube", "config"), "location of kubernetes config file")
 	flag.StringVar(&port, "port", "9001", "port on which the scheduler extender will listen")
-	flag.StringVar(&certFile, "cert", "/etc/kubernetes/pki/ca.crt", "cert file extender will use for authentication")
-	flag.StringVar(&keyFile, "key", "/etc/kubernetes/pki/ca.key", "key file extender will use for authentication")
-	flag.StringVar(&caFile, "cacert", "/etc/kubernetes/pki/ca.crt", "ca file extender will use for authentication")
+	flag.StringVar(&healthPort, "healthPort", "", "HTTP port of the /healthz and /readyz endpoints, disabled if empty")
+	flag.StringVar(&certFile, "cert", "/etc/kubernetes/pki/ca.crt", "cert file extender will use for authentication, polled every 10s for rotation")
+	flag.StringVar(&keyFile, "key", "/etc/kubernetes/pki/ca.key", "key file extender will use for authentication, polled every 10s for rotation")
+	flag.StringVar(&caFile, "cacert", "/etc/kubernetes/pki/ca.crt", "ca file extender will use for authentication, polled every 10s for rotation")
+	tlsOptions.AddFlags(flag.CommandLine)
 	flag.StringVar(&syncPeriod, "syncPeriod", "5s", "length of time in seconds between metrics updates")
+	flag.DurationVar(&policyResync, "policyResyncPeriod", 5*time.Minute, "interval at which all policies are reconciled again, 0 to disable")
+	flag.DurationVar(&metricHistory, "metricHistory", tascache.DefaultHistoryWindow, "length of the metric history used to predict the trend of rules")
+	flag.IntVar(&evictLimits.PerTick, "evictPerTick", evictLimits.PerTick, "maximum number of pods evicted by the evict strategy per sync period")
+	flag.IntVar(&evictLimits.PerNode, "evictPerNode", evictLimits.PerNode, "maximum number of pods evicted from a single node per sync period")
+	flag.Float64Var(&evictLimits.MaxClusterFraction, "evictMaxFraction", evictLimits.MaxClusterFraction,
+		"maximum fraction of cluster pods terminating at once due to evictions, rounded down but at least one pod, 0 disables evictions")
+	flag.StringVar(&notifyConfig.URL, "notifyURL", "", "URL every notify strategy posts violation events to")
+	flag.StringVar(&notifySecretFile, "notifySecret", "", "file holding the key used to sign notify requests with HMAC-SHA256")
+	flag.StringVar(&notifyConfig.CertFile, "notifyCert", "", "client cert file used for the notify webhook")
+	flag.StringVar(&notifyConfig.KeyFile, "notifyKey", "", "client key file used for the notify webhook")
+	flag.StringVar(&notifyConfig.CAFile, "notifyCACert", "", "ca file used to verify the notify webhook server")
+	flag.IntVar(&notifyConfig.Retries, "notifyRetries", notifyConfig.Retries, "number of retries for a failed notify request")
+	flag.IntVar(&notifyConfig.BatchSize, "notifyBatchSize", notifyConfig.BatchSize, "maximum number of events in a notify request")
+	flag.DurationVar(&notifyConfig.BatchInterval, "notifyBatchInterval", notifyConfig.BatchInterval, "time events are buffered before a notify request")
+	flag.StringVar(&webhookPort, "webhookPort", "", "port on which the policy admission webhook will listen, disabled if empty")
+	flag.StringVar(&webhookCertFile, "webhookCert", "/etc/kubernetes/pki/ca.crt", "cert file the policy admission webhook will use")
+	flag.StringVar(&webhookKeyFile, "webhookKey", "/etc/kubernetes/pki/ca.key", "key file the policy admission webhook will use")
 	flag.Parse()
 
+	if notifySecretFile != "" {
+		secret, err := os.ReadFile(notifySecretFile)
+		if err != nil {
+			klog.Exit("cannot read notify secret: " + err.Error())
+		}
+
+		notifyConfig.Secret = bytes.TrimSpace(secret)
+	}
+
 	cache := tascache.NewAutoUpdatingCache()
+	cache.SetHistoryWindow(metricHistory)
+	kubeClient, clientConfig, err := extender.GetKubeClient(kubeConfig)
+	if err != nil {
+		klog.V(l2).InfoS("Issue in getting client config", "component", "controller")
+		klog.Exit(err.Error())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	tscheduler := telemetryscheduler.NewMetricsExtender(cache)
+	tscheduler.KubeClient = kubeClient
+	tscheduler.Nodes = telemetryscheduler.WatchNodes(ctx, kubeClient)
+
+	webhookErr := make(chan error, 1)
+
+	if webhookPort != "" {
+		go func() {
+			err := validation.StartServer(ctx, webhookPort, webhookCertFile, webhookKeyFile)
+			// a failed webhook stops the extender too
+			stop()
+			webhookErr <- err
+		}()
+	} else {
+		webhookErr <- nil
+	}
+
+	cont := tasController(ctx, kubeClient, clientConfig, syncPeriod, policyResync, cache, evictLimits, notifyConfig)
+
+	sch := extender.Server{
+		Scheduler:  tscheduler,
+		HealthPort: healthPort,
+		Readiness:  readiness{controller: cont, cache: cache},
+		TLSOptions: tlsOptions,
+	}
 
-	sch := extender.Server{Scheduler: tscheduler}
-	go sch.StartServer(port, certFile, keyFile, caFile, false)
-	tasController(kubeConfig, syncPeriod, cache)
+	err = sch.StartServer(ctx, port, certFile, keyFile, caFile, false)
+	stop()
+	notify.Wait()
+
+	err = errors.Join(err, <-webhookErr)
+	if err != nil {
+		klog.V(l2).InfoS("Server failed", "component", "extender")
+		klog.Exit(err.Error())
+	}
+
+	klog.V(l2).InfoS("Extender stopped", "component", "extender")
 	klog.Flush()
 }
 
+// readiness makes TAS ready once the policies found at start are registered and the metrics cache has been updated
+// since, so that scheduling decisions are based on the metrics of every policy.
+type readiness struct {
+	controller *controller.TelemetryPolicyController
+	cache      *tascache.AutoUpdatingCache
+}
+
+var (
+	errPoliciesNotSynced = errors.New("telemetry policies not synced")
+	errMetricsNotSynced  = errors.New("metrics cache not updated since the policies synced")
+)
+
+// Ready returns why TAS isn't ready, or nil if it is.
+func (r readiness) Ready() error {
+	if r.controller == nil {
+		return errPoliciesNotSynced
+	}
+
+	syncedAt := r.controller.SyncedAt()
+	if syncedAt.IsZero() {
+		return errPoliciesNotSynced
+	}
+
+	if !r.cache.UpdatedAt().After(syncedAt) {
+		return errMetricsNotSynced
+	}
+
+	return nil
+}
+
 // tasController The controller load the TAS policy/strategies and places them into a local cache that is available
-// to all TAS components. It also monitors the current state of policies.
-func tasController(kubeConfig string, syncPeriod string, cache *tascache.AutoUpdatingCache) {
+// to all TAS components. It also monitors the current state of policies until the context is done.
+func tasController(ctx context.Context, kubeClient kubernetes.Interface, clientConfig *rest.Config, syncPeriod string,
+	policyResync time.Duration, cache *tascache.AutoUpdatingCache, evictLimits evict.Limits, notifyConfig notify.Config,
+) (cont *controller.TelemetryPolicyController) {
 	defer func() {
 		err := recover()
 		if err != nil {
@@ -65,19 +185,17 @@ func tasController(kubeConfig string, syncPeriod string, cache *tascache.AutoUpd
 		}
 	}()
 
-	kubeClient, clientConfig, err := extender.GetKubeClient(kubeConfig)
-	if err != nil {
-		klog.V(l2).InfoS("Issue in getting client config", "component", "controller")
-		klog.Exit(err.Error())
-	}
-
 	syncDuration, err := time.ParseDuration(syncPeriod)
 	if err != nil {
 		klog.V(l2).InfoS("Sync problems in Parsing", "component", "controller")
 		klog.Exit(err.Error())
 	}
 
+	evictLimits.Interval = syncDuration
+	evict.SetLimits(evictLimits)
+
 	metricsClient := metrics.NewClient(clientConfig)
+	metricsClient.Pods = metrics.WatchPods(ctx, kubeClient)
 
 	telpolicyClient, _, err := telemetrypolicyclient.NewRest(*clientConfig)
 	if err != nil {
@@ -92,30 +210,36 @@ func tasController(kubeConfig string, syncPeriod string, cache *tascache.AutoUpd
 
 	enforcerTicker := time.NewTicker(syncDuration)
 
-	ctx, cancelFunc := context.WithCancel(context.Background())
-	defer cancelFunc()
+	if notifyConfig.URL != "" {
+		err = notify.Start(ctx, notifyConfig)
+		if err != nil {
+			klog.V(l2).InfoS("Notify webhook configuration problem", "component", "controller")
+			klog.Exit(err.Error())
+		}
+	}
 
 	enfrcr := strategy.NewEnforcer(kubeClient)
-	cont := controller.TelemetryPolicyController{
-		Interface: telpolicyClient,
-		Writer:    cache,
-		Enforcer:  enfrcr,
+	cont = &controller.TelemetryPolicyController{
+		Interface:    telpolicyClient,
+		Writer:       cache,
+		Enforcer:     enfrcr,
+		ResyncPeriod: policyResync,
+		KubeClient:   kubeClient,
 	}
 
-	enfrcr.RegisterStrategyType(&deschedule.Strategy{})
-	enfrcr.RegisterStrategyType(&scheduleonmetric.Strategy{})
-	enfrcr.RegisterStrategyType(&dontschedule.Strategy{})
-	enfrcr.RegisterStrategyType(&labeling.Strategy{})
+	for _, strategyType := range strategy.RegisteredTypes() {
+		str, err := strategy.New(strategyType, telempol.TASPolicyStrategy{})
+		if err != nil {
+			klog.V(l2).InfoS(err.Error(), "component", "controller")
+
+			continue
+		}
+
+		enfrcr.RegisterStrategyType(str)
+	}
 
 	go cont.Run(ctx)
 	go enfrcr.EnforceRegisteredStrategies(cache, *enforcerTicker)
 
-	done := make(chan os.Signal, 1)
-	catchInterrupt(done)
-}
-
-func catchInterrupt(done chan os.Signal) {
-	signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
-	<-done
-	klog.V(l2).InfoS("Policy controller closed ", "component", "controller")
+	return cont
 }
@@ -7,10 +7,12 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
-	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
 )
 
@@ -18,6 +20,8 @@ const (
 	policyPath string = "policies/%v/%v"
 	metricPath string = "metrics/%v"
 	l2                = 2
+	// DefaultHistoryWindow is how long metric samples are kept in the metric history by default.
+	DefaultHistoryWindow = 10 * time.Minute
 )
 
 var (
@@ -27,10 +31,24 @@ var (
 )
 
 // AutoUpdatingCache holds a map of metrics of interest with their associated NodeMetricsInfo object.
+// The samples written within the history window are kept as the history of the metrics with history references,
+// counted in historyRefs, as only the rules predicting a trend read them.
+// Node target overrides are kept apart from the metrics, indexed by their <namespace>.<policy>.<rule> key and then by
+// node.
+// Metrics with a registered transform are computed from other cached metrics rather than read from the metrics client.
+// updatedAt is the start time, in Unix nanoseconds, of the last completed update of all metrics.
 type AutoUpdatingCache struct {
 	concurrentCache
-	mtx       sync.RWMutex
-	metricMap map[string]int
+	mtx           sync.RWMutex
+	metricMap     map[string]int
+	transforms    map[string]*registeredTransform
+	historyMtx    sync.RWMutex
+	history       map[string]metrics.NodeMetricsHistory
+	historyRefs   map[string]int
+	historyWindow time.Duration
+	targetsMtx    sync.RWMutex
+	targets       map[string]map[string]resource.Quantity
+	updatedAt     atomic.Int64
 }
 
 // NewAutoUpdatingCache returns an empty metrics cache.
@@ -39,7 +57,12 @@ func NewAutoUpdatingCache() *AutoUpdatingCache {
 		concurrentCache: concurrentCache{
 			cache: make(chan request),
 		},
-		metricMap: make(map[string]int),
+		metricMap:     make(map[string]int),
+		transforms:    make(map[string]*registeredTransform),
+		history:       make(map[string]metrics.NodeMetricsHistory),
+		historyRefs:   make(map[string]int),
+		historyWindow: DefaultHistoryWindow,
+		targets:       make(map[string]map[string]resource.Quantity),
 	}
 }
 
@@ -53,12 +76,19 @@ func (n *AutoUpdatingCache) PeriodicUpdate(period time.Ticker, client metrics.Cl
 	}
 }
 
-// updateAllMetrics performs an updateAllMetrics to every metric in the cache.
+// updateAllMetrics performs an updateAllMetrics to every metric in the cache, then computes the metrics of the
+// registered transforms from the updated values.
 func (n *AutoUpdatingCache) updateAllMetrics(client metrics.Client) {
+	start := time.Now()
+
 	n.mtx.Lock()
 	defer n.mtx.Unlock()
 
 	for name := range n.metricMap {
+		if _, ok := n.transforms[name]; ok {
+			continue
+		}
+
 		if len(name) > 0 {
 			err := n.updateMetric(client, name)
 			if err != nil {
@@ -68,6 +98,20 @@ func (n *AutoUpdatingCache) updateAllMetrics(client metrics.Client) {
 			delete(n.metricMap, name)
 		}
 	}
+
+	n.applyTransforms()
+	n.updatedAt.Store(start.UnixNano())
+}
+
+// UpdatedAt returns when the last completed update of all metrics started, or the zero time before the first one.
+// Metrics written to the cache before that time hold values read by that update.
+func (n *AutoUpdatingCache) UpdatedAt() time.Time {
+	updatedAt := n.updatedAt.Load()
+	if updatedAt == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, updatedAt)
 }
 
 // updateMetric updates the NodeMetricInfo object in the AutoUpdatingCache for a metric with a given name.
@@ -138,6 +182,10 @@ func (n *AutoUpdatingCache) WriteMetric(metricName string, data metrics.NodeMetr
 	payload := nilPayloadCheck(data)
 	n.add(fmt.Sprintf(metricPath, metricName), payload)
 
+	if payload != nil {
+		n.recordHistory(metricName, data)
+	}
+
 	if payload == nil {
 		n.mtx.Lock()
 		defer n.mtx.Unlock()
@@ -167,6 +215,9 @@ func (n *AutoUpdatingCache) DeleteMetric(metricName string) error {
 	if total, ok := n.metricMap[metricName]; ok && total == 1 {
 		delete(n.metricMap, metricName)
 		n.delete(fmt.Sprintf(metricPath, metricName))
+		n.historyMtx.Lock()
+		delete(n.history, metricName)
+		n.historyMtx.Unlock()
 	} else {
 		n.metricMap[metricName] = total - 1
 	}
@@ -175,6 +226,165 @@ func (n *AutoUpdatingCache) DeleteMetric(metricName string) error {
 	return nil
 }
 
+// SetHistoryWindow sets how long metric samples are kept in the metric history. Older samples are dropped on the next
+// write of their metric.
+func (n *AutoUpdatingCache) SetHistoryWindow(window time.Duration) {
+	n.historyMtx.Lock()
+	defer n.historyMtx.Unlock()
+
+	n.historyWindow = window
+}
+
+// WriteHistory adds a reference to the history of the named metric. The samples of a metric are only recorded in its
+// history while it has references.
+func (n *AutoUpdatingCache) WriteHistory(metricName string) error {
+	if len(metricName) == 0 {
+		return errInvalidMetricName
+	}
+
+	n.historyMtx.Lock()
+	defer n.historyMtx.Unlock()
+
+	n.historyRefs[metricName]++
+
+	return nil
+}
+
+// DeleteHistory drops a reference to the history of the named metric. The history is removed with its last reference.
+func (n *AutoUpdatingCache) DeleteHistory(metricName string) error {
+	n.historyMtx.Lock()
+	defer n.historyMtx.Unlock()
+
+	if n.historyRefs[metricName] > 1 {
+		n.historyRefs[metricName]--
+
+		return nil
+	}
+
+	delete(n.historyRefs, metricName)
+	delete(n.history, metricName)
+
+	return nil
+}
+
+// ReadMetricHistory returns the samples of the named metric on each node within the history window, oldest first.
+// If no metric of that name is found it returns an error.
+func (n *AutoUpdatingCache) ReadMetricHistory(metricName string) (metrics.NodeMetricsHistory, error) {
+	n.historyMtx.RLock()
+	defer n.historyMtx.RUnlock()
+
+	history, ok := n.history[metricName]
+	if !ok {
+		return metrics.NodeMetricsHistory{}, fmt.Errorf("no history for metric %v found %w", metricName, errNull)
+	}
+
+	output := make(metrics.NodeMetricsHistory, len(history))
+	for nodeName, samples := range history {
+		output[nodeName] = append([]metrics.NodeMetric(nil), samples...)
+	}
+
+	return output, nil
+}
+
+// recordHistory appends the samples newer than the latest recorded one for each node to the metric history and drops
+// the samples which left the history window. Nodes missing from the data keep their history until it's too old.
+// Metrics without history references aren't recorded.
+func (n *AutoUpdatingCache) recordHistory(metricName string, data metrics.NodeMetricsInfo) {
+	n.historyMtx.Lock()
+	defer n.historyMtx.Unlock()
+
+	if n.historyRefs[metricName] == 0 {
+		return
+	}
+
+	history, ok := n.history[metricName]
+	if !ok {
+		history = metrics.NodeMetricsHistory{}
+		n.history[metricName] = history
+	}
+
+	latest := time.Time{}
+
+	for nodeName, sample := range data {
+		samples := history[nodeName]
+		if len(samples) == 0 || sample.Timestamp.After(samples[len(samples)-1].Timestamp) {
+			history[nodeName] = append(samples, sample)
+		}
+
+		if sample.Timestamp.After(latest) {
+			latest = sample.Timestamp
+		}
+	}
+
+	cutoff := latest.Add(-n.historyWindow)
+
+	for nodeName, samples := range history {
+		first := 0
+		for first < len(samples) && samples[first].Timestamp.Before(cutoff) {
+			first++
+		}
+
+		if first == len(samples) {
+			delete(history, nodeName)
+		} else if first > 0 {
+			history[nodeName] = append([]metrics.NodeMetric(nil), samples[first:]...)
+		}
+	}
+}
+
+// ReadTargetOverrides returns the target each node overrides for the <namespace>.<policy>.<rule> key, indexed by node
+// name.
+func (n *AutoUpdatingCache) ReadTargetOverrides(key string) map[string]resource.Quantity {
+	n.targetsMtx.RLock()
+	defer n.targetsMtx.RUnlock()
+
+	output := make(map[string]resource.Quantity, len(n.targets[key]))
+	for nodeName, target := range n.targets[key] {
+		output[nodeName] = target.DeepCopy()
+	}
+
+	return output
+}
+
+// WriteNodeTargets replaces the target overrides of the node with the passed ones, indexed by
+// <namespace>.<policy>.<rule> key.
+func (n *AutoUpdatingCache) WriteNodeTargets(nodeName string, targets map[string]resource.Quantity) error {
+	n.targetsMtx.Lock()
+	defer n.targetsMtx.Unlock()
+
+	n.deleteNodeTargets(nodeName)
+
+	for key, target := range targets {
+		if _, ok := n.targets[key]; !ok {
+			n.targets[key] = map[string]resource.Quantity{}
+		}
+
+		n.targets[key][nodeName] = target.DeepCopy()
+	}
+
+	return nil
+}
+
+// DeleteNodeTargets removes all the target overrides of the node.
+func (n *AutoUpdatingCache) DeleteNodeTargets(nodeName string) error {
+	n.targetsMtx.Lock()
+	defer n.targetsMtx.Unlock()
+
+	n.deleteNodeTargets(nodeName)
+
+	return nil
+}
+
+func (n *AutoUpdatingCache) deleteNodeTargets(nodeName string) {
+	for key, nodes := range n.targets {
+		delete(nodes, nodeName)
+
+		if len(nodes) == 0 {
+			delete(n.targets, key)
+		}
+	}
+}
+
 // nilPayloadCheck replaces the payload with a nil value if there's no metrics attached.
 // This prevents metrics from being overwritten with empty data on new additions.
 func nilPayloadCheck(data metrics.NodeMetricsInfo) interface{} {
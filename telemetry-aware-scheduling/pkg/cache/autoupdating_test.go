@@ -10,7 +10,8 @@ import (
 	"time"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
-	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
 )
 
@@ -253,3 +254,159 @@ func TestNodeMetricsCache_DeleteMetric(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeMetricsCache_ReadMetricHistory(t *testing.T) {
+	n := NewAutoUpdatingCache()
+	n.SetHistoryWindow(time.Minute)
+
+	go n.run(n.cache, map[string]interface{}{})
+
+	if err := n.WriteMetric("humidity", metrics.NodeMetricsInfo{"node A": metrics.NodeMetric{Timestamp: time.Unix(timeSec, timeNs)}}); err != nil {
+		t.Fatalf("Cannot write metric: %v", err)
+	}
+
+	if _, err := n.ReadMetricHistory("humidity"); err == nil {
+		t.Errorf("History of metric without history references recorded")
+	}
+
+	if err := n.WriteHistory("temperature"); err != nil {
+		t.Fatalf("Cannot add history reference: %v", err)
+	}
+
+	start := time.Unix(timeSec, timeNs)
+	sample := func(seconds int, value int64) metrics.NodeMetric {
+		return metrics.NodeMetric{Timestamp: start.Add(time.Duration(seconds) * time.Second), Value: *resource.NewQuantity(value, resource.DecimalSI)}
+	}
+	writes := []metrics.NodeMetricsInfo{
+		{"node A": sample(0, 10), "node B": sample(0, 50)},
+		{"node A": sample(30, 20), "node B": sample(0, 50)},
+		{"node A": sample(60, 30)},
+		{"node A": sample(90, 40)},
+	}
+
+	for _, data := range writes {
+		if err := n.WriteMetric("temperature", data); err != nil {
+			t.Fatalf("Cannot write metric: %v", err)
+		}
+	}
+
+	got, err := n.ReadMetricHistory("temperature")
+	if err != nil {
+		t.Fatalf("ReadMetricHistory() error = %v", err)
+	}
+
+	want := metrics.NodeMetricsHistory{"node A": {sample(30, 20), sample(60, 30), sample(90, 40)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadMetricHistory() = %v, want %v", got, want)
+	}
+
+	if err := n.WriteMetric("temperature", nil); err != nil {
+		t.Fatalf("Cannot add metric reference: %v", err)
+	}
+
+	if err := n.DeleteMetric("temperature"); err != nil {
+		t.Fatalf("Cannot delete metric: %v", err)
+	}
+
+	if _, err := n.ReadMetricHistory("temperature"); err == nil {
+		t.Errorf("History of deleted metric still present")
+	}
+
+	if err := n.WriteMetric("temperature", writes[len(writes)-1]); err != nil {
+		t.Fatalf("Cannot write metric: %v", err)
+	}
+
+	if err := n.DeleteHistory("temperature"); err != nil {
+		t.Fatalf("Cannot delete history reference: %v", err)
+	}
+
+	if _, err := n.ReadMetricHistory("temperature"); err == nil {
+		t.Errorf("History without references still present")
+	}
+}
+
+func TestNodeMetricsCache_WriteTransform(t *testing.T) {
+	n := NewAutoUpdatingCache()
+
+	go n.run(n.cache, map[string]interface{}{})
+
+	client := metrics.NewDummyMetricsClient(map[string]metrics.NodeMetricsInfo{
+		"power_milliwatts": metrics.TestNodeMetricCustomInfo([]string{"node A", "node B"}, []int64{150000, 450000}),
+		"power_budget":     metrics.TestNodeMetricCustomInfo([]string{"node A", "node B"}, []int64{300, 0}),
+	})
+	watts := metrics.Transform{Source: "power_milliwatts", Scale: 0.001}
+	ratio := metrics.Transform{Inputs: []string{"power_budget", "power_watts"}, Expression: "power_watts / power_budget",
+		Derive: func(values map[string]float64) (float64, error) {
+			return values["power_watts"] / values["power_budget"], nil
+		}, Scale: 1}
+
+	for _, name := range []string{"power_milliwatts", "power_budget", "power_watts"} {
+		if err := n.WriteMetric(name, nil); err != nil {
+			t.Fatalf("Cannot add metric reference: %v", err)
+		}
+	}
+
+	if err := n.WriteTransform("power_watts", watts); err != nil {
+		t.Fatalf("WriteTransform() error = %v", err)
+	}
+
+	if err := n.WriteTransform("power_ratio", ratio); err != nil {
+		t.Fatalf("WriteTransform() error = %v", err)
+	}
+
+	if err := n.WriteTransform("power_watts", metrics.Transform{Source: "power_milliwatts", Scale: 0.01}); err == nil {
+		t.Errorf("WriteTransform() of a different transform under the same name succeeded")
+	}
+
+	n.updateAllMetrics(client)
+
+	want := map[string]map[string]string{
+		"power_watts": {"node A": "150", "node B": "450"},
+		"power_ratio": {"node A": "0.5"},
+	}
+
+	for name, values := range want {
+		got, err := n.ReadMetric(name)
+		if err != nil {
+			t.Fatalf("ReadMetric(%v) error = %v", name, err)
+		}
+
+		if len(got) != len(values) {
+			t.Errorf("ReadMetric(%v) = %v, want %v", name, got, values)
+		}
+
+		for nodeName, value := range values {
+			if gotValue := got[nodeName].Value; gotValue.Cmp(resource.MustParse(value)) != 0 {
+				t.Errorf("ReadMetric(%v) value of %v = %v, want %v", name, nodeName, gotValue.String(), value)
+			}
+		}
+	}
+
+	if err := n.DeleteTransform("power_ratio"); err != nil {
+		t.Fatalf("DeleteTransform() error = %v", err)
+	}
+
+	if _, err := n.ReadMetric("power_ratio"); err == nil {
+		t.Errorf("Values of deleted transform still present")
+	}
+}
+
+func TestNodeMetricsCache_UpdatedAt(t *testing.T) {
+	n := NewAutoUpdatingCache()
+	if !n.UpdatedAt().IsZero() {
+		t.Errorf("Got updated at %v before any update, want zero time", n.UpdatedAt())
+	}
+
+	start := time.Now()
+	go n.PeriodicUpdate(*time.NewTicker(time.Hour), metrics.NewDummyMetricsClient(metrics.InstanceOfMockMetricClientMap),
+		map[string]interface{}{})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for n.UpdatedAt().IsZero() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if updatedAt := n.UpdatedAt(); updatedAt.Before(start) {
+		t.Errorf("Got updated at %v, want the start of the first update after %v", updatedAt, start)
+	}
+}
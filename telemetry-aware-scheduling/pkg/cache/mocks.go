@@ -11,7 +11,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
-	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -85,11 +85,51 @@ func (n MockCache) ReadMetric(string) (metrics.NodeMetricsInfo, error) {
 	return metrics.NodeMetricsInfo{}, nil
 }
 
+// ReadMetricHistory is a method implemented for Mock cache.
+func (n MockCache) ReadMetricHistory(string) (metrics.NodeMetricsHistory, error) {
+	return metrics.NodeMetricsHistory{}, nil
+}
+
 // ReadPolicy is a method implemented for Mock cache.
 func (n MockCache) ReadPolicy(string, string) (telemetrypolicy.TASPolicy, error) {
 	return telemetrypolicy.TASPolicy{}, nil
 }
 
+// ReadTargetOverrides is a method implemented for Mock cache.
+func (n MockCache) ReadTargetOverrides(string) map[string]resource.Quantity {
+	return map[string]resource.Quantity{}
+}
+
+// WriteNodeTargets is a method implemented for Mock cache.
+func (n MockCache) WriteNodeTargets(string, map[string]resource.Quantity) error {
+	return nil
+}
+
+// DeleteNodeTargets is a method implemented for Mock cache.
+func (n MockCache) DeleteNodeTargets(string) error {
+	return nil
+}
+
+// WriteTransform is a method implemented for Mock cache.
+func (n MockCache) WriteTransform(string, metrics.Transform) error {
+	return nil
+}
+
+// DeleteTransform is a method implemented for Mock cache.
+func (n MockCache) DeleteTransform(string) error {
+	return nil
+}
+
+// WriteHistory is a method implemented for Mock cache.
+func (n MockCache) WriteHistory(string) error {
+	return nil
+}
+
+// DeleteHistory is a method implemented for Mock cache.
+func (n MockCache) DeleteHistory(string) error {
+	return nil
+}
+
 // WriteMetric is a method implemented for Mock cache.
 func (n MockCache) WriteMetric(metricName string, _ metrics.NodeMetricsInfo) error {
 	if metricName != "" {
@@ -0,0 +1,129 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	"k8s.io/klog/v2"
+)
+
+var (
+	errTransformConflict = errors.New("metric is already defined differently")
+	errNoTransformValues = errors.New("no node has a value for every metric read")
+)
+
+// registeredTransform is a transform together with the number of policies defining it.
+type registeredTransform struct {
+	transform  metrics.Transform
+	references int
+}
+
+// WriteTransform registers the transform computing the named metric, which is then computed on every update of the
+// cache once the metrics it reads are updated. Policies defining the same metric need to define it the same way, so a
+// transform different from the one registered for the metric is rejected.
+func (n *AutoUpdatingCache) WriteTransform(metricName string, transform metrics.Transform) error {
+	if len(metricName) == 0 {
+		return errInvalidMetricName
+	}
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	registered, ok := n.transforms[metricName]
+	if ok && !registered.transform.Equal(transform) {
+		return fmt.Errorf("%w: %v", errTransformConflict, metricName)
+	}
+
+	if !ok {
+		registered = &registeredTransform{transform: transform}
+		n.transforms[metricName] = registered
+	}
+
+	registered.references++
+
+	return nil
+}
+
+// DeleteTransform drops a reference to the transform of the named metric. The transform is removed with its last
+// reference, together with the values it computed unless a rule still reads the metric.
+func (n *AutoUpdatingCache) DeleteTransform(metricName string) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	registered, ok := n.transforms[metricName]
+	if !ok {
+		return nil
+	}
+
+	registered.references--
+	if registered.references > 0 {
+		return nil
+	}
+
+	delete(n.transforms, metricName)
+
+	if _, ok := n.metricMap[metricName]; !ok {
+		n.delete(fmt.Sprintf(metricPath, metricName))
+		n.historyMtx.Lock()
+		delete(n.history, metricName)
+		n.historyMtx.Unlock()
+	}
+
+	return nil
+}
+
+// applyTransforms computes the metrics of the registered transforms from the cached values of the metrics they read.
+// Transforms of a single metric are applied first, so derived metrics can read transformed ones.
+// The caller must hold the metric lock.
+func (n *AutoUpdatingCache) applyTransforms() {
+	for _, derived := range []bool{false, true} {
+		for name, registered := range n.transforms {
+			if registered.transform.Derived() != derived {
+				continue
+			}
+
+			if err := n.applyTransform(name, registered.transform); err != nil {
+				klog.V(l2).ErrorS(err, "failed to compute metric "+name, "component", "controller")
+			}
+		}
+	}
+}
+
+func (n *AutoUpdatingCache) applyTransform(metricName string, transform metrics.Transform) error {
+	names := transform.Inputs
+	if !transform.Derived() {
+		names = []string{transform.Source}
+	}
+
+	inputs := make(map[string]metrics.NodeMetricsInfo, len(names))
+
+	for _, name := range names {
+		values, err := n.ReadMetric(name)
+		if err != nil {
+			n.delete(fmt.Sprintf(metricPath, metricName))
+
+			return err
+		}
+
+		inputs[name] = values
+	}
+
+	output, err := transform.Apply(inputs)
+	if err != nil {
+		return fmt.Errorf("transform failed: %w", err)
+	}
+
+	// Writing no values would add a reference to the metric rather than replace its values, so the stale values are
+	// dropped instead, as they are when an input is missing.
+	if len(output) == 0 {
+		n.delete(fmt.Sprintf(metricPath, metricName))
+
+		return errNoTransformValues
+	}
+
+	return n.WriteMetric(metricName, output)
+}
@@ -7,21 +7,37 @@ import (
 	"time"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
-	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
-// Reader is the functionality to read metrics and policies from the cache.
+// Reader is the functionality to read metrics, policies and node target overrides from the cache.
+// ReadMetricHistory returns the recent samples of a metric on each node, oldest first.
+// ReadTargetOverrides returns the target each node overrides for the given <namespace>.<policy>.<rule> key, indexed
+// by node name.
 type Reader interface {
 	ReadMetric(metricName string) (metrics.NodeMetricsInfo, error)
+	ReadMetricHistory(metricName string) (metrics.NodeMetricsHistory, error)
 	ReadPolicy(podNamespace string, policyName string) (telemetrypolicy.TASPolicy, error)
+	ReadTargetOverrides(key string) map[string]resource.Quantity
 }
 
-// Writer is the functionality to edit metrics (write and delete) and Policies in the cache.
+// Writer is the functionality to edit metrics (write and delete), Policies, node target overrides, metric
+// transforms and metric history references in the cache.
+// WriteNodeTargets replaces all the target overrides of a node, indexed by their <namespace>.<policy>.<rule> key.
+// WriteTransform registers the transform computing a metric, every write needs a matching DeleteTransform.
+// WriteHistory keeps the history of a metric, every write needs a matching DeleteHistory.
 type Writer interface {
 	WriteMetric(metricName string, metricInfo metrics.NodeMetricsInfo) error
 	WritePolicy(policyNamespace string, policyName string, policy telemetrypolicy.TASPolicy) error
+	WriteNodeTargets(nodeName string, targets map[string]resource.Quantity) error
+	WriteTransform(metricName string, transform metrics.Transform) error
+	WriteHistory(metricName string) error
 	DeleteMetric(metricName string) error
 	DeletePolicy(policyNamespace string, policyName string) error
+	DeleteNodeTargets(nodeName string) error
+	DeleteTransform(metricName string) error
+	DeleteHistory(metricName string) error
 }
 
 // ReaderWriter holds the functionality to both read and write metrics and policies.
@@ -7,28 +7,41 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
 	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
-	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/deschedule"
-	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/dontschedule"
-	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/labeling"
-	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/scheduleonmetric"
-	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 	core "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/fields"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 )
 
 const (
-	l2 = 2
-	l4 = 4
+	l2                = 2
+	l4                = 4
+	invalidCompliance = "Invalid"
+	queueName         = "taspolicies"
+	// maxRetries is the number of times a policy is requeued after failing to reconcile before it's dropped.
+	// The policy is reconciled again on its next change or resync.
+	maxRetries = 5
 )
 
-var errStrategyType = errors.New("provided strategy type is invalid")
+var (
+	errNull      = errors.New("")
+	errNotSynced = errors.New("timed out waiting for the policy and node informers to sync")
+)
 
 // Run starts the controller watching on the Informer queue and doesnt' stop it,
 // until the Done signal is received from context.
@@ -42,16 +55,32 @@ func (controller *TelemetryPolicyController) Run(context context.Context) {
 		}
 	}()
 
-	_, err := controller.watch(context)
+	controller.queue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), queueName)
+	defer controller.queue.ShutDown()
 
+	policyController, err := controller.watch(context)
 	if err != nil {
 		log.Panic(err.Error())
 	}
 
+	synced := []cache.InformerSynced{policyController.HasSynced}
+	if controller.KubeClient != nil {
+		synced = append(synced, controller.watchNodes(context).HasSynced)
+	}
+
+	if !cache.WaitForCacheSync(context.Done(), synced...) {
+		log.Panic(errNotSynced.Error())
+	}
+
+	controller.trackSync(controller.store.ListKeys())
+
+	go wait.UntilWithContext(context, controller.runWorker, time.Second)
+
 	<-context.Done()
 }
 
 // Watch sets up the watcher on the kubernetes api server and adds event handlers for add, update and delete.
+// Every handler only queues the key of the policy. The informer also queues all policies every ResyncPeriod.
 func (controller *TelemetryPolicyController) watch(context context.Context) (cache.Controller, error) {
 	source := cache.NewListWatchFromClient(
 		controller,
@@ -59,175 +88,525 @@ func (controller *TelemetryPolicyController) watch(context context.Context) (cac
 		core.NamespaceAll,
 		fields.Everything(),
 	)
-	_, policyController := cache.NewInformer(
+	store, policyController := cache.NewInformer(
 		source,
 		&telemetrypolicy.TASPolicy{},
-		0,
+		controller.ResyncPeriod,
 		cache.ResourceEventHandlerFuncs{
-			AddFunc:    controller.onAdd,
-			UpdateFunc: controller.onUpdate,
-			DeleteFunc: controller.onDelete,
+			AddFunc:    controller.enqueue,
+			UpdateFunc: func(_, newer interface{}) { controller.enqueue(newer) },
+			DeleteFunc: controller.enqueue,
 		},
 	)
+	controller.store = store
 
 	go policyController.Run(context.Done())
 
 	return policyController, nil
 }
 
-// onAdd fires when the controller sees a new policy in the apiserver.
-// It adds the policy to the cache and adds each of its metrics to the cache.
-// It also adds the strategies contained in the policy to the strategy enforcer.
-func (controller *TelemetryPolicyController) onAdd(obj interface{}) {
-	pol, ok := obj.(*telemetrypolicy.TASPolicy)
+// enqueue adds the namespace/name key of the policy to the work queue.
+func (controller *TelemetryPolicyController) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.V(l4).InfoS("cannot queue policy: "+err.Error(), "component", "controller")
+
+		return
+	}
+
+	controller.queue.Add(key)
+}
+
+// runWorker reconciles policies from the queue until it's shut down.
+func (controller *TelemetryPolicyController) runWorker(_ context.Context) {
+	for controller.processNextItem() {
+	}
+}
+
+// processNextItem reconciles a single policy from the queue. It returns false once the queue is shut down.
+func (controller *TelemetryPolicyController) processNextItem() bool {
+	item, quit := controller.queue.Get()
+	if quit {
+		return false
+	}
+
+	defer controller.queue.Done(item)
+
+	key, ok := item.(string)
 	if !ok {
-		klog.V(l4).InfoS("cannot add policy: not recognized as a telemetry policy", "component", "controller")
+		controller.queue.Forget(item)
+
+		return true
+	}
+
+	err := controller.reconcile(key)
+	controller.handleErr(err, key)
+	controller.markSynced(key)
 
+	return true
+}
+
+// trackSync starts waiting for the policies listed at start to be reconciled. Without policies the controller is
+// synced at once.
+func (controller *TelemetryPolicyController) trackSync(keys []string) {
+	controller.unsynced = make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		controller.unsynced[key] = struct{}{}
+	}
+
+	controller.markSynced("")
+}
+
+// markSynced records that the policy of the key was processed and, once every policy listed at start was, the time
+// they were synced. A policy waiting to be retried after a failure isn't synced until it's reconciled or dropped, so
+// it's only done once the queue forgot it.
+func (controller *TelemetryPolicyController) markSynced(key string) {
+	if controller.syncedAt.Load() != 0 {
+		return
+	}
+
+	if controller.queue.NumRequeues(key) == 0 {
+		delete(controller.unsynced, key)
+	}
+
+	if len(controller.unsynced) > 0 {
 		return
 	}
 
-	polCopy := pol.DeepCopy()
+	controller.syncedAt.CompareAndSwap(0, time.Now().UnixNano())
+}
 
-	err := controller.WritePolicy(polCopy.Namespace, polCopy.Name, *polCopy)
-	if err != nil {
-		klog.V(l2).InfoS("Policy not added to cache: "+err.Error(), "component", "controller")
+// SyncedAt returns when the policies found at start were first all reconciled, or the zero time until then.
+func (controller *TelemetryPolicyController) SyncedAt() time.Time {
+	syncedAt := controller.syncedAt.Load()
+	if syncedAt == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, syncedAt)
+}
+
+// handleErr requeues a policy which failed to reconcile with a rate limited backoff, up to maxRetries times.
+func (controller *TelemetryPolicyController) handleErr(err error, key string) {
+	if err == nil {
+		controller.queue.Forget(key)
 
 		return
 	}
 
-	for name := range polCopy.Spec.Strategies {
-		klog.V(l4).InfoS("registering "+name+" from "+pol.Name, "component", "controller")
-		strt, err := castStrategy(name, polCopy.Spec.Strategies[name])
+	if controller.queue.NumRequeues(key) < maxRetries {
+		klog.V(l2).InfoS("Retrying policy "+key+": "+err.Error(), "component", "controller")
+		controller.queue.AddRateLimited(key)
+
+		return
+	}
+
+	controller.queue.Forget(key)
+	utilruntime.HandleError(fmt.Errorf("dropping policy %v from the queue: %w", key, err))
+}
 
+// reconcile brings the strategies in the enforcer and the metric references in the cache in line with the current
+// version of the policy. A policy which no longer exists has everything registered for it removed.
+func (controller *TelemetryPolicyController) reconcile(key string) error {
+	obj, exists, err := controller.store.GetByKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to get policy %v: %w", key, err)
+	}
+
+	if !exists {
+		return controller.removePolicy(key)
+	}
+
+	pol, ok := obj.(*telemetrypolicy.TASPolicy)
+	if !ok {
+		klog.V(l4).InfoS("cannot reconcile "+key+": not recognized as a telemetry policy", "component", "controller")
+
+		return nil
+	}
+
+	return controller.syncPolicy(key, pol.DeepCopy())
+}
+
+// state returns what the controller registered for the policy under the given key.
+func (controller *TelemetryPolicyController) state(key string) *policyState {
+	if controller.policies == nil {
+		controller.policies = map[string]*policyState{}
+	}
+
+	state, ok := controller.policies[key]
+	if !ok {
+		state = &policyState{
+			strategies: map[string]registeredStrategy{},
+			metrics:    map[string]int{},
+			histories:  map[string]int{},
+			transforms: map[string]registeredTransform{},
+		}
+		controller.policies[key] = state
+	}
+
+	return state
+}
+
+// syncPolicy writes the policy to the cache and registers the strategies and metrics it needs.
+// Strategies which were removed from the policy or changed are removed from the enforcer, which cleans up after them.
+// Strategy types without a registered implementation and strategies whose rule expressions don't compile are skipped
+// and listed in the policy status, as are metrics the policy computes which are invalid or defined differently by
+// another policy.
+func (controller *TelemetryPolicyController) syncPolicy(key string, pol *telemetrypolicy.TASPolicy) error {
+	err := controller.WritePolicy(pol.Namespace, pol.Name, *ownedStrategies(pol))
+	if err != nil {
+		return fmt.Errorf("policy not added to cache: %w", err)
+	}
+
+	state := controller.state(key)
+	desired := map[string]registeredStrategy{}
+	desiredMetrics := map[string]int{}
+	desiredHistories := map[string]int{}
+
+	var unknown, invalid []string
+
+	for name, spec := range pol.Spec.Strategies {
+		spec.PolicyName, spec.PolicyNamespace = pol.Name, pol.Namespace
+
+		strt, err := strategy.New(name, spec)
 		if err != nil {
-			klog.V(l2).InfoS(err.Error(), "component", "controller")
+			klog.V(l2).InfoS(err.Error(), "policy", pol.Name, "component", "controller")
+
+			unknown = append(unknown, name)
+
+			continue
+		}
+
+		if err := strategy.CompileRules(spec.Rules); err != nil {
+			klog.V(l2).InfoS(err.Error(), "policy", pol.Name, "strategy", name, "component", "controller")
+
+			invalid = append(invalid, name+" "+err.Error())
 
-			return
+			continue
 		}
 
-		strt.SetPolicyName(polCopy.ObjectMeta.Name)
-		controller.Enforcer.AddStrategy(strt, name)
+		strt.SetPolicyName(pol.Name)
+		desired[name] = registeredStrategy{strategy: strt, spec: spec}
 
-		ruleset := polCopy.Spec.Strategies
+		for _, rule := range spec.Rules {
+			for _, name := range strategy.RuleMetricKeys(rule) {
+				desiredMetrics[name]++
 
-		for _, rule := range ruleset[name].Rules {
-			err := controller.WriteMetric(rule.Metricname, nil)
-			if err == nil {
-				klog.V(l2).InfoS("Added "+rule.Metricname, "component", "controller")
+				if rule.Trend != nil {
+					desiredHistories[name]++
+				}
 			}
 		}
 	}
 
-	klog.V(l2).InfoS("Added policy, "+polCopy.Name, "component", "controller")
-}
+	for name, current := range state.strategies {
+		if wanted, ok := desired[name]; ok && apiequality.Semantic.DeepEqual(wanted.spec, current.spec) {
+			continue
+		}
 
-// castStrategy takes in a TASpolicy and returns its specific type based on the structure of the policy file.
-func castStrategy(strategyType string, policy telemetrypolicy.TASPolicyStrategy) (strategy.Interface, error) {
-	switch strategyType {
-	case scheduleonmetric.StrategyType:
-		str := (scheduleonmetric.Strategy)(policy)
+		klog.V(l4).InfoS("removing "+name+" from "+pol.Name, "component", "controller")
+		controller.Enforcer.RemoveStrategy(current.strategy, name)
+		delete(state.strategies, name)
+	}
+
+	for name, wanted := range desired {
+		if _, ok := state.strategies[name]; ok {
+			continue
+		}
 
-		return &str, nil
-	case deschedule.StrategyType:
-		str := (deschedule.Strategy)(policy)
+		klog.V(l4).InfoS("registering "+name+" from "+pol.Name, "component", "controller")
+		controller.Enforcer.AddStrategy(wanted.strategy, name)
+		state.strategies[name] = wanted
+	}
 
-		return &str, nil
-	case dontschedule.StrategyType:
-		str := (dontschedule.Strategy)(policy)
+	invalidMetrics := controller.syncTransforms(state, pol)
 
-		return &str, nil
-	case labeling.StrategyType:
-		str := (labeling.Strategy)(policy)
+	for _, registered := range state.transforms {
+		for _, name := range transformInputs(registered.transform) {
+			desiredMetrics[name]++
+		}
+	}
 
-		return &str, nil
-	default:
-		return nil, fmt.Errorf("cast strategy failed: %w", errStrategyType)
+	err = errors.Join(controller.syncMetrics(state, desiredMetrics), controller.syncHistories(state, desiredHistories),
+		controller.updateStatus(pol, unknown, invalid, invalidMetrics))
+	if err != nil {
+		return fmt.Errorf("policy %v partially reconciled: %w", key, err)
 	}
+
+	controller.requeueAtTransition(key, pol)
+
+	klog.V(l2).InfoS("Reconciled policy, "+pol.Name, "component", "controller")
+
+	return nil
 }
 
-// onUpdate deletes the old policy and unregisters strategies and metrics.
-func (controller *TelemetryPolicyController) onUpdate(older, newer interface{}) {
-	oldPol := older.(*telemetrypolicy.TASPolicy)
-	newPol := newer.(*telemetrypolicy.TASPolicy)
-	polCopy := newPol.DeepCopy()
+// ownedStrategies returns a copy of the policy whose strategies name the policy they belong to, so the scheduler reads
+// the target overrides of the policy for the strategies it gets from the cache.
+func ownedStrategies(pol *telemetrypolicy.TASPolicy) *telemetrypolicy.TASPolicy {
+	owned := pol.DeepCopy()
+
+	for name, spec := range owned.Spec.Strategies {
+		spec.PolicyName, spec.PolicyNamespace = pol.Name, pol.Namespace
+		owned.Spec.Strategies[name] = spec
+	}
+
+	return owned
+}
 
-	err := controller.WritePolicy(polCopy.Namespace, polCopy.Name, *polCopy)
+// removePolicy removes every strategy and metric reference registered for a deleted policy and drops it from the cache.
+func (controller *TelemetryPolicyController) removePolicy(key string) error {
+	state := controller.state(key)
+
+	for name, current := range state.strategies {
+		controller.Enforcer.RemoveStrategy(current.strategy, name)
+		delete(state.strategies, name)
+	}
+
+	for name := range state.transforms {
+		if err := controller.DeleteTransform(name); err != nil {
+			klog.V(l4).InfoS(err.Error(), "component", "controller")
+		}
+
+		delete(state.transforms, name)
+	}
+
+	err := errors.Join(controller.syncMetrics(state, map[string]int{}), controller.syncHistories(state, map[string]int{}))
 	if err != nil {
-		msg := fmt.Sprintf("cached policy not updated %v", err)
-		klog.V(l2).InfoS(msg, "component", "controller")
+		return err
+	}
 
-		return
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return fmt.Errorf("invalid policy key %v: %w", key, err)
 	}
 
-	klog.V(l2).InfoS("Policy: "+polCopy.Name+" updated", "component", "controller")
+	err = controller.DeletePolicy(namespace, name)
+	if err != nil {
+		klog.V(l4).InfoS(err.Error(), "component", "controller")
+	}
 
-	for name := range polCopy.Spec.Strategies {
-		oldStrat, err := castStrategy(name, oldPol.Spec.Strategies[name])
-		if err != nil {
-			klog.V(l2).InfoS(err.Error(), "component", "controller")
+	delete(controller.policies, key)
+	klog.V(l2).InfoS("Policy: "+key+" deleted", "component", "controller")
 
-			return
-		}
+	return nil
+}
 
-		oldStrat.SetPolicyName(polCopy.ObjectMeta.Name)
-		controller.Enforcer.RemoveStrategy(oldStrat, oldStrat.StrategyType())
+// syncTransforms writes the metrics computed by the policy to the cache. Metrics removed from the policy or changed are
+// deleted from the cache first. It returns the metrics which can't be computed, with the reason.
+func (controller *TelemetryPolicyController) syncTransforms(state *policyState, pol *telemetrypolicy.TASPolicy) []string {
+	desired := map[string]telemetrypolicy.TASPolicyMetric{}
+	for _, metric := range pol.Spec.Metrics {
+		desired[metric.Name] = metric
+	}
 
-		for _, rule := range oldPol.Spec.Strategies[oldStrat.StrategyType()].Rules {
-			errm := controller.DeleteMetric(rule.Metricname)
-			if errm != nil {
-				klog.V(l2).InfoS(errm.Error(), "component", "controller")
-			}
+	for name, current := range state.transforms {
+		if wanted, ok := desired[name]; ok && apiequality.Semantic.DeepEqual(wanted, current.spec) {
+			continue
 		}
 
-		strt, err := castStrategy(name, polCopy.Spec.Strategies[name])
-		if err != nil {
+		if err := controller.DeleteTransform(name); err != nil {
 			klog.V(l2).InfoS(err.Error(), "component", "controller")
+		}
+
+		delete(state.transforms, name)
+	}
+
+	var invalid []string
 
-			return
+	for name, wanted := range desired {
+		if _, ok := state.transforms[name]; ok {
+			continue
 		}
 
-		strt.SetPolicyName(polCopy.ObjectMeta.Name)
-		controller.Enforcer.AddStrategy(strt, name)
+		transform, err := strategy.NewTransform(wanted)
+		if err == nil {
+			err = controller.WriteTransform(name, transform)
+		}
 
-		for _, rule := range polCopy.Spec.Strategies[name].Rules {
-			err := controller.WriteMetric(rule.Metricname, nil)
-			if err != nil {
-				klog.V(l2).InfoS(err.Error(), "component", "controller")
-			}
+		if err != nil {
+			klog.V(l2).InfoS(err.Error(), "policy", pol.Name, "metric", name, "component", "controller")
+
+			invalid = append(invalid, name+" "+err.Error())
+
+			continue
 		}
+
+		state.transforms[name] = registeredTransform{transform: transform, spec: wanted}
 	}
+
+	return invalid
 }
 
-// onDelete gets rid of the policy along with its associated registered strategies and the metrics associated with them.
-func (controller *TelemetryPolicyController) onDelete(obj interface{}) {
-	pol := obj.(*telemetrypolicy.TASPolicy)
-	polCopy := pol.DeepCopy()
+// transformInputs returns the names of the metrics a transform reads.
+func transformInputs(transform metrics.Transform) []string {
+	if transform.Derived() {
+		return transform.Inputs
+	}
 
-	for name := range polCopy.Spec.Strategies {
-		strt, err := castStrategy(name, polCopy.Spec.Strategies[name])
-		if err != nil {
-			klog.V(l2).InfoS(err.Error(), "component", "controller")
+	return []string{transform.Source}
+}
 
-			return
-		}
+// syncMetrics writes or deletes metric references in the cache until the references held by the policy match the
+// desired count for each metric. References are only recorded in the state once the cache accepted them.
+func (controller *TelemetryPolicyController) syncMetrics(state *policyState, desired map[string]int) error {
+	writeMetric := func(name string) error { return controller.WriteMetric(name, nil) }
 
-		strt.SetPolicyName(pol.Name)
-		controller.Enforcer.RemoveStrategy(strt, strt.StrategyType())
+	failed := syncReferences(state.metrics, desired, writeMetric, controller.DeleteMetric)
+	if len(failed) > 0 {
+		return fmt.Errorf("could not update metrics %v %w", strings.Join(failed, ", "), errNull)
+	}
+
+	return nil
+}
+
+// syncHistories writes or deletes history references in the cache until the references held by the policy match the
+// desired count for each metric, so that the cache only records the history of the metrics read by trend rules.
+func (controller *TelemetryPolicyController) syncHistories(state *policyState, desired map[string]int) error {
+	failed := syncReferences(state.histories, desired, controller.WriteHistory, controller.DeleteHistory)
+	if len(failed) > 0 {
+		return fmt.Errorf("could not update metric histories %v %w", strings.Join(failed, ", "), errNull)
+	}
+
+	return nil
+}
+
+// syncReferences writes or deletes references until the current count of each name matches the desired one, updating
+// the current counts as the writes and deletes succeed. It returns the sorted names which couldn't be updated.
+func syncReferences(current, desired map[string]int, write, remove func(name string) error) []string {
+	failed := []string{}
+	names := map[string]struct{}{}
+
+	for name := range current {
+		names[name] = struct{}{}
+	}
 
-		for _, rule := range polCopy.Spec.Strategies[strt.StrategyType()].Rules {
-			err := controller.DeleteMetric(rule.Metricname)
-			if err != nil {
+	for name := range desired {
+		names[name] = struct{}{}
+	}
+
+	for name := range names {
+		for current[name] < desired[name] {
+			if err := write(name); err != nil {
+				klog.V(l2).InfoS(err.Error(), "component", "controller")
+				failed = append(failed, name)
+
+				break
+			}
+
+			current[name]++
+		}
+
+		for current[name] > desired[name] {
+			if err := remove(name); err != nil {
 				klog.V(l2).InfoS(err.Error(), "component", "controller")
+				failed = append(failed, name)
+
+				break
 			}
+
+			current[name]--
+		}
+
+		if current[name] == 0 {
+			delete(current, name)
 		}
 	}
 
-	err := controller.DeletePolicy(polCopy.Namespace, polCopy.Name)
+	sort.Strings(failed)
+
+	return failed
+}
+
+// updateStatus writes the unknown strategy types, invalid strategies and metrics, active strategies and node target
+// overrides of the policy to its status subresource. The API server is only called when the status changes, so the resulting update event doesn't
+// trigger another write.
+func (controller *TelemetryPolicyController) updateStatus(pol *telemetrypolicy.TASPolicy, unknown, invalid, invalidMetrics []string) error {
+	status := telemetrypolicy.TASPolicyStatus{
+		Strategies:      strategyWindows(pol, strategy.Now()),
+		TargetOverrides: controller.targetOverrides(pol),
+	}
+	messages := []string{}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		messages = append(messages, "unknown strategy types: "+strings.Join(unknown, ", "))
+		status.UnknownStrategies = unknown
+	}
+
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		messages = append(messages, "invalid strategies: "+strings.Join(invalid, "; "))
+	}
+
+	if len(invalidMetrics) > 0 {
+		sort.Strings(invalidMetrics)
+		messages = append(messages, "invalid metrics: "+strings.Join(invalidMetrics, "; "))
+	}
+
+	if len(messages) > 0 {
+		status.Compliance = invalidCompliance
+		status.Message = strings.Join(messages, "; ")
+	}
+
+	if apiequality.Semantic.DeepEqual(pol.Status, status) {
+		return nil
+	}
+
+	pol.Status = status
+
+	body, err := json.Marshal(pol)
 	if err != nil {
-		klog.V(l4).InfoS(err.Error(), "component", "controller")
+		return fmt.Errorf("cannot encode policy status: %w", err)
+	}
+
+	err = controller.Put().Namespace(pol.Namespace).Resource(telemetrypolicy.Plural).Name(pol.Name).
+		SubResource("status").SetHeader("Content-Type", "application/json").Body(body).Do(context.TODO()).Error()
+	if err != nil {
+		return fmt.Errorf("policy status not updated: %w", err)
+	}
+
+	return nil
+}
+
+// strategyWindows returns whether each strategy of the policy with active windows applies at the given time, sorted
+// by strategy type.
+func strategyWindows(pol *telemetrypolicy.TASPolicy, now time.Time) []telemetrypolicy.TASPolicyStrategyStatus {
+	var statuses []telemetrypolicy.TASPolicyStrategyStatus
 
+	for name, spec := range pol.Spec.Strategies {
+		if len(spec.ActiveWindows) == 0 {
+			continue
+		}
+
+		statuses = append(statuses, telemetrypolicy.TASPolicyStrategyStatus{
+			Type:   name,
+			Active: strategy.InWindows(spec.ActiveWindows, now),
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Type < statuses[j].Type })
+
+	return statuses
+}
+
+// requeueAtTransition queues the policy again when one of its strategies enters or leaves an active window, so its
+// status follows the windows without waiting for the next resync.
+func (controller *TelemetryPolicyController) requeueAtTransition(key string, pol *telemetrypolicy.TASPolicy) {
+	if controller.queue == nil {
 		return
 	}
 
-	klog.V(l2).InfoS("Policy: "+polCopy.Name+" deleted", "component", "controller")
+	now := strategy.Now()
+	next := time.Time{}
+
+	for _, spec := range pol.Spec.Strategies {
+		transition := strategy.NextTransition(spec.ActiveWindows, now)
+		if !transition.IsZero() && (next.IsZero() || transition.Before(next)) {
+			next = transition
+		}
+	}
+
+	if !next.IsZero() {
+		controller.queue.AddAfter(key, next.Sub(now))
+	}
 }
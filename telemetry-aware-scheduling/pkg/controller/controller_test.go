@@ -6,37 +6,52 @@
 package controller
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
 	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/deschedule"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/dontschedule"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/labeling"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/scheduleonmetric"
-	api "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	api "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/rest/fake"
+	clientcache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
 )
 
 var (
 	policy1 = getTASPolicy("policy1", "default", dontschedule.StrategyType, []api.TASPolicyRule{
-		{Metricname: "filter1_metric", Operator: "LessThan", Target: 20, Labels: []string{}}})
+		{Metricname: "filter1_metric", Operator: "LessThan", Target: resource.MustParse("20"), Labels: []string{}}})
 	policy2 = getTASPolicy("policy2", "default", deschedule.StrategyType, []api.TASPolicyRule{
-		{Metricname: "filter2_metric", Operator: "GreatThan", Target: 20, Labels: []string{}}})
+		{Metricname: "filter2_metric", Operator: "GreatThan", Target: resource.MustParse("20"), Labels: []string{}}})
 	policy3 = getTASPolicy("policy3", "default", scheduleonmetric.StrategyType, []api.TASPolicyRule{
-		{Metricname: "filter3_metric", Operator: "GreatThan", Target: 20, Labels: []string{}}})
+		{Metricname: "filter3_metric", Operator: "GreatThan", Target: resource.MustParse("20"), Labels: []string{}}})
 	policy4 = getTASPolicy("policy4", "default", labeling.StrategyType, []api.TASPolicyRule{
-		{Metricname: "filter4_metric", Operator: "Equals", Target: 20, Labels: []string{}}})
+		{Metricname: "filter4_metric", Operator: "Equals", Target: resource.MustParse("20"), Labels: []string{}}})
 	policy5 = getTASPolicy("policy5", "default", "strategy_unavailable", []api.TASPolicyRule{
-		{Metricname: "filter5_metric", Operator: "LessThan", Target: 20, Labels: []string{}}})
+		{Metricname: "filter5_metric", Operator: "LessThan", Target: resource.MustParse("20"), Labels: []string{}}})
 	policy6 = getTASPolicy("policy6", "default", dontschedule.StrategyType, []api.TASPolicyRule{
-		{Metricname: "", Operator: "LessThan", Target: 20, Labels: []string{}}})
+		{Metricname: "", Operator: "LessThan", Target: resource.MustParse("20"), Labels: []string{}}})
 	policy7 = getTASPolicy("", "", dontschedule.StrategyType, []api.TASPolicyRule{
-		{Metricname: "", Operator: "", Target: 0, Labels: []string{}}})
+		{Metricname: "", Operator: "", Target: resource.MustParse("0"), Labels: []string{}}})
 	policy8 = getTASPolicy("policy8", "not default", scheduleonmetric.StrategyType, []api.TASPolicyRule{
-		{Metricname: "filter8_metric", Operator: "GreatThan", Target: 20, Labels: []string{}}})
+		{Metricname: "filter8_metric", Operator: "GreatThan", Target: resource.MustParse("20"), Labels: []string{}}})
 )
 
 func getTASPolicy(name, namespace string, str string, metricRule []api.TASPolicyRule) *api.TASPolicy {
@@ -56,7 +71,7 @@ func getTASPolicy(name, namespace string, str string, metricRule []api.TASPolicy
 	return pol
 }
 
-func TestTelemetryPolicyController_onAdd(t *testing.T) {
+func TestTelemetryPolicyController_reconcile(t *testing.T) {
 	type interfaceMock struct {
 		rest.Interface
 	}
@@ -68,7 +83,7 @@ func TestTelemetryPolicyController_onAdd(t *testing.T) {
 	}
 
 	type args struct {
-		obj interface{}
+		obj *api.TASPolicy
 	}
 
 	tests := []struct {
@@ -82,8 +97,8 @@ func TestTelemetryPolicyController_onAdd(t *testing.T) {
 			name:   "policy with dontschedule strategy",
 			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
 			args:   args{policy1},
-			expect: &dontschedule.Strategy{PolicyName: "policy1", LogicalOperator: "",
-				Rules: []api.TASPolicyRule{{Metricname: "filter1_metric", Operator: "LessThan", Target: 20,
+			expect: &dontschedule.Strategy{PolicyName: "policy1", PolicyNamespace: "default", LogicalOperator: "",
+				Rules: []api.TASPolicyRule{{Metricname: "filter1_metric", Operator: "LessThan", Target: resource.MustParse("20"),
 					Labels: []string{}}}},
 			want: true,
 		},
@@ -91,32 +106,48 @@ func TestTelemetryPolicyController_onAdd(t *testing.T) {
 			name:   "policy with  deschedule strategy",
 			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
 			args:   args{policy2},
-			expect: &deschedule.Strategy{PolicyName: policy2.Spec.Strategies["deschedule"].PolicyName, LogicalOperator: "",
-				Rules: []api.TASPolicyRule{{Metricname: "filter2_metric", Operator: "GreatThan", Target: 20,
+			expect: &deschedule.Strategy{PolicyName: policy2.Spec.Strategies["deschedule"].PolicyName, PolicyNamespace: "default", LogicalOperator: "",
+				Rules: []api.TASPolicyRule{{Metricname: "filter2_metric", Operator: "GreatThan", Target: resource.MustParse("20"),
 					Labels: []string{}}}},
 			want: true,
 		},
 		{
-			name:   "policy with wrong strategy",
+			name:   "policy with labeling strategy",
 			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
+			args:   args{policy4},
+			expect: &labeling.Strategy{PolicyName: "policy4", PolicyNamespace: "default", LogicalOperator: "",
+				Rules: []api.TASPolicyRule{{Metricname: "filter4_metric", Operator: "Equals", Target: resource.MustParse("20"),
+					Labels: []string{}}}},
+			want: true,
+		},
+		{
+			name:   "policy with wrong strategy",
+			fields: fields{interfaceMock{statusClient()}, cache.MockCache{}, strategy.MockStrategy{}},
 			args:   args{policy5},
 			expect: nil,
 			want:   false,
 		},
 		{
-			name:   "No policy",
+			name:   "policy with wrong metric rule",
 			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
-			args:   args{},
-			expect: nil,
-			want:   false,
+			args:   args{policy6},
+			expect: &dontschedule.Strategy{PolicyName: "policy6", PolicyNamespace: "default", LogicalOperator: "",
+				Rules: []api.TASPolicyRule{{Metricname: "", Operator: "LessThan", Target: resource.MustParse("20"), Labels: []string{}}}},
+			want: true,
 		},
 		{
 			name:   "policy without name, namespace and metric rules",
 			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
 			args:   args{policy7},
-			expect: &dontschedule.Strategy{PolicyName: "", LogicalOperator: "",
-				Rules: []api.TASPolicyRule{{Metricname: "", Operator: "", Target: 0, Labels: []string{}}}},
-			want: false,
+			expect: nil,
+			want:   false,
+		},
+		{
+			name:   "policy in wrong namespace",
+			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
+			args:   args{policy8},
+			expect: nil,
+			want:   false,
 		},
 	}
 	for _, tt := range tests {
@@ -126,8 +157,11 @@ func TestTelemetryPolicyController_onAdd(t *testing.T) {
 				Interface: tt.fields.Interface,
 				Writer:    tt.fields.Writer,
 				Enforcer:  &tt.fields.Enforcer,
+				store:     clientcache.NewStore(clientcache.MetaNamespaceKeyFunc),
 			}
-			controller.onAdd(tt.args.obj)
+			_ = controller.store.Add(tt.args.obj)
+			key, _ := clientcache.MetaNamespaceKeyFunc(tt.args.obj)
+			_ = controller.reconcile(key)
 			enforced := tt.fields.Enforcer
 			gotStrMetricRule := enforced.AddedStrategies.I
 			if gotStrMetricRule == nil {
@@ -142,163 +176,576 @@ func TestTelemetryPolicyController_onAdd(t *testing.T) {
 	}
 }
 
-func TestTelemetryPolicyController_onDelete(t *testing.T) {
-	type interfaceMock struct {
-		rest.Interface
+// recordingEnforcer keeps the strategies added to it by type.
+type recordingEnforcer struct {
+	strategy.MockStrategy
+	strategies map[string]strategy.Interface
+	calls      int
+}
+
+func (e *recordingEnforcer) AddStrategy(str strategy.Interface, strategyType string) {
+	e.strategies[strategyType] = str
+	e.calls++
+}
+
+func (e *recordingEnforcer) RemoveStrategy(_ strategy.Interface, strategyType string) {
+	delete(e.strategies, strategyType)
+	e.calls++
+}
+
+// recordingCache counts the references held on each metric, transform and metric history and the policies written to
+// it.
+type recordingCache struct {
+	metrics    map[string]int
+	transforms map[string]int
+	histories  map[string]int
+	policies   map[string]bool
+}
+
+func (c *recordingCache) WriteMetric(metricName string, _ metrics.NodeMetricsInfo) error {
+	if metricName == "" {
+		return errNull
 	}
 
-	type fields struct {
-		Interface interfaceMock
-		Writer    cache.MockCache
-		Enforcer  strategy.MockStrategy
+	c.metrics[metricName]++
+
+	return nil
+}
+
+func (c *recordingCache) DeleteMetric(metricName string) error {
+	c.metrics[metricName]--
+	if c.metrics[metricName] == 0 {
+		delete(c.metrics, metricName)
 	}
 
-	type args struct {
-		obj interface{}
+	return nil
+}
+
+func (c *recordingCache) WriteTransform(metricName string, _ metrics.Transform) error {
+	if c.transforms == nil {
+		c.transforms = map[string]int{}
 	}
 
-	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		expect interface{}
-		want   bool
+	c.transforms[metricName]++
+
+	return nil
+}
+
+func (c *recordingCache) DeleteTransform(metricName string) error {
+	c.transforms[metricName]--
+	if c.transforms[metricName] == 0 {
+		delete(c.transforms, metricName)
+	}
+
+	return nil
+}
+
+func (c *recordingCache) WriteHistory(metricName string) error {
+	if c.histories == nil {
+		c.histories = map[string]int{}
+	}
+
+	c.histories[metricName]++
+
+	return nil
+}
+
+func (c *recordingCache) DeleteHistory(metricName string) error {
+	c.histories[metricName]--
+	if c.histories[metricName] == 0 {
+		delete(c.histories, metricName)
+	}
+
+	return nil
+}
+
+func (c *recordingCache) WritePolicy(namespace string, policyName string, _ api.TASPolicy) error {
+	c.policies[namespace+"/"+policyName] = true
+
+	return nil
+}
+
+func (c *recordingCache) WriteNodeTargets(string, map[string]resource.Quantity) error {
+	return nil
+}
+
+func (c *recordingCache) DeleteNodeTargets(string) error {
+	return nil
+}
+
+func (c *recordingCache) DeletePolicy(namespace string, policyName string) error {
+	delete(c.policies, namespace+"/"+policyName)
+
+	return nil
+}
+
+func TestTelemetryPolicyController_reconcileChanges(t *testing.T) {
+	rules := func(metricNames ...string) []api.TASPolicyRule {
+		out := []api.TASPolicyRule{}
+		for _, name := range metricNames {
+			out = append(out, api.TASPolicyRule{Metricname: name, Operator: "GreaterThan", Target: resource.MustParse("10")})
+		}
+
+		return out
+	}
+	policy := func(strategies map[string]api.TASPolicyStrategy) *api.TASPolicy {
+		return &api.TASPolicy{ObjectMeta: metav1.ObjectMeta{Name: "changing", Namespace: "default"},
+			Spec: api.TASPolicySpec{Strategies: strategies}}
+	}
+
+	steps := []struct {
+		name        string
+		policy      *api.TASPolicy
+		wantTypes   []string
+		wantMetrics map[string]int
+		wantCalls   int
+		wantErr     bool
 	}{
-		{
-			name:   "polycy with schedulonmetric strategy",
-			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
-			args:   args{policy3},
-			expect: nil,
-			want:   true,
-		},
-		{
-			name:   "policy with wrong strategy",
-			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
-			args:   args{policy5},
-			expect: nil,
-			want:   true,
-		},
-		{
-			name:   "policy in wrong namespace",
-			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
-			args:   args{policy8},
-			expect: &scheduleonmetric.Strategy{PolicyName: "policy8", LogicalOperator: "",
-				Rules: []api.TASPolicyRule{{Metricname: "filter8_metric", Operator: "GreatThan", Target: 20,
-					Labels: []string{}}}},
-			want: false,
-		},
+		{name: "policy added",
+			policy: policy(map[string]api.TASPolicyStrategy{
+				dontschedule.StrategyType: {Rules: rules("m1")},
+				deschedule.StrategyType:   {Rules: rules("m1", "m2")},
+			}),
+			wantTypes: []string{deschedule.StrategyType, dontschedule.StrategyType}, wantMetrics: map[string]int{"m1": 2, "m2": 1},
+			wantCalls: 2},
+		{name: "resync without changes",
+			policy: policy(map[string]api.TASPolicyStrategy{
+				dontschedule.StrategyType: {Rules: rules("m1")},
+				deschedule.StrategyType:   {Rules: rules("m1", "m2")},
+			}),
+			wantTypes: []string{deschedule.StrategyType, dontschedule.StrategyType}, wantMetrics: map[string]int{"m1": 2, "m2": 1}},
+		{name: "strategy removed and strategy changed",
+			policy: policy(map[string]api.TASPolicyStrategy{
+				dontschedule.StrategyType: {Rules: rules("m3")},
+			}),
+			wantTypes: []string{dontschedule.StrategyType}, wantMetrics: map[string]int{"m3": 1}, wantCalls: 3},
+		{name: "invalid metric is retried",
+			policy: policy(map[string]api.TASPolicyStrategy{
+				dontschedule.StrategyType: {Rules: rules("m3")},
+				labeling.StrategyType:     {Rules: rules("")},
+			}),
+			wantTypes: []string{dontschedule.StrategyType, labeling.StrategyType}, wantMetrics: map[string]int{"m3": 1},
+			wantCalls: 1, wantErr: true},
+		{name: "policy deleted",
+			wantMetrics: map[string]int{}, wantCalls: 2},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			var got bool
-			controller := &TelemetryPolicyController{
-				tt.fields.Interface,
-				tt.fields.Writer,
-				&tt.fields.Enforcer,
-			}
-			controller.onDelete(tt.args.obj)
-			enforced := tt.fields.Enforcer
-			gotStrMetricRule := enforced.RemovedStrategies
-			got = reflect.DeepEqual(tt.expect, gotStrMetricRule)
-			if got != tt.want {
-				t.Errorf("Got %v, want %v", gotStrMetricRule, tt.expect)
-			}
-		})
+
+	enforcer := &recordingEnforcer{strategies: map[string]strategy.Interface{}}
+	writer := &recordingCache{metrics: map[string]int{}, policies: map[string]bool{}}
+	controller := &TelemetryPolicyController{Writer: writer, Enforcer: enforcer,
+		store: clientcache.NewStore(clientcache.MetaNamespaceKeyFunc)}
+
+	for _, step := range steps {
+		enforcer.calls = 0
+
+		if step.policy != nil {
+			_ = controller.store.Update(step.policy)
+		} else {
+			_ = controller.store.Delete(policy(nil))
+		}
+
+		err := controller.reconcile("default/changing")
+		if (err != nil) != step.wantErr {
+			t.Errorf("%v: got error %v, want error %v", step.name, err, step.wantErr)
+		}
+
+		gotTypes := []string{}
+		for name := range enforcer.strategies {
+			gotTypes = append(gotTypes, name)
+		}
+
+		sort.Strings(gotTypes)
+
+		if len(gotTypes) != len(step.wantTypes) || (len(gotTypes) > 0 && !reflect.DeepEqual(gotTypes, step.wantTypes)) {
+			t.Errorf("%v: got strategies %v, want %v", step.name, gotTypes, step.wantTypes)
+		}
+
+		if !reflect.DeepEqual(writer.metrics, step.wantMetrics) {
+			t.Errorf("%v: got metric references %v, want %v", step.name, writer.metrics, step.wantMetrics)
+		}
+
+		if enforcer.calls != step.wantCalls {
+			t.Errorf("%v: got %d enforcer calls, want %d", step.name, enforcer.calls, step.wantCalls)
+		}
+
+		if writer.policies["default/changing"] != (step.policy != nil) {
+			t.Errorf("%v: policy in cache %v", step.name, writer.policies)
+		}
 	}
 }
 
-func TestTelemetryPolicyController_onUpdate(t *testing.T) {
-	type interfaceMock struct {
-		rest.Interface
+// statusClient returns a fake rest client accepting every request.
+func statusClient() *fake.RESTClient {
+	return &fake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Resp: &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body: io.NopCloser(strings.NewReader("{}"))},
 	}
+}
 
-	type fields struct {
-		Interface interfaceMock
-		Writer    cache.MockCache
-		Enforcer  strategy.MockStrategy
+func TestTelemetryPolicyController_reconcileUnknownStrategy(t *testing.T) {
+	pol := getTASPolicy("policy9", "default", "strategy_unavailable", []api.TASPolicyRule{
+		{Metricname: "filter9_metric", Operator: "LessThan", Target: resource.MustParse("20")}})
+	pol.Spec.Strategies[dontschedule.StrategyType] = api.TASPolicyStrategy{PolicyName: "policy9",
+		Rules: []api.TASPolicyRule{{Metricname: "filter9_metric", Operator: "GreaterThan", Target: resource.MustParse("10")}}}
+
+	client := statusClient()
+	enforcer := strategy.MockStrategy{}
+	controller := &TelemetryPolicyController{Interface: client, Writer: cache.MockCache{}, Enforcer: &enforcer,
+		store: clientcache.NewStore(clientcache.MetaNamespaceKeyFunc)}
+
+	_ = controller.store.Add(pol)
+	if err := controller.reconcile("default/policy9"); err != nil {
+		t.Errorf("Unexpected error from reconcile: %v", err)
 	}
 
-	type args struct {
-		old interface{}
-		new interface{}
+	if _, ok := enforcer.AddedStrategies.I.(*dontschedule.Strategy); !ok {
+		t.Errorf("Known strategy not added, got %v", enforcer.AddedStrategies.I)
 	}
 
-	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		expect interface{}
-		want   bool
-	}{
-		{
-			name:   "policy with donschedule replaced by labeling strategy",
-			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
-			args:   args{policy1, policy4},
-			expect: &labeling.Strategy{PolicyName: "policy4", LogicalOperator: "",
-				Rules: []api.TASPolicyRule{{Metricname: "filter4_metric", Operator: "Equals", Target: 20,
-					Labels: []string{}}}},
-			want: true,
-		},
-		{
-			name:   "policy with labeling replaced by deschedule strategy",
-			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
-			args:   args{policy4, policy2},
-			expect: &deschedule.Strategy{PolicyName: policy2.Spec.Strategies["deschedule"].PolicyName, LogicalOperator: "",
-				Rules: []api.TASPolicyRule{{Metricname: "filter2_metric", Operator: "GreatThan", Target: 20,
-					Labels: []string{}}}},
-			want: true,
-		},
-		{
-			name:   "replace a policy with a policy with wrong strategy",
-			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
-			args:   args{policy1, policy5},
-			expect: nil,
-			want:   false,
-		},
-		{
-			name:   "replace the same policy",
-			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
-			args:   args{policy1, policy1},
-			expect: &dontschedule.Strategy{PolicyName: "policy1", LogicalOperator: "",
-				Rules: []api.TASPolicyRule{{Metricname: "filter1_metric", Operator: "LessThan", Target: 20,
-					Labels: []string{}}}},
-			want: true,
-		},
-		{
-			name:   "replace policy by policy with wrong metric rule",
-			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
-			args:   args{policy1, policy6},
-			expect: nil,
-			want:   false,
-		},
-		{
-			name:   "replace policy by an empty policy",
-			fields: fields{interfaceMock{}, cache.MockCache{}, strategy.MockStrategy{}},
-			args:   args{policy1, policy7},
-			expect: nil,
-			want:   false,
-		},
+	if client.Req == nil || client.Req.Method != http.MethodPut ||
+		client.Req.URL.Path != "/namespaces/default/taspolicies/policy9/status" {
+		t.Errorf("Expected a status update, got %v", client.Req)
+
+		return
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			var got bool
-			controller := &TelemetryPolicyController{
-				Interface: tt.fields.Interface,
-				Writer:    tt.fields.Writer,
-				Enforcer:  &tt.fields.Enforcer,
-			}
-			controller.onUpdate(tt.args.old, tt.args.new)
-			enforced := tt.fields.Enforcer
-			gotStrMetricRule := enforced.AddedStrategies.I
-			if gotStrMetricRule == nil {
-				got = false
-			} else {
-				got = reflect.DeepEqual(tt.expect, gotStrMetricRule)
-			}
-			if got != tt.want {
-				t.Errorf("Got %v, want %v", gotStrMetricRule, tt.expect)
-			}
-		})
+
+	updated := api.TASPolicy{}
+	if err := json.NewDecoder(client.Req.Body).Decode(&updated); err != nil {
+		t.Errorf("Cannot decode status update: %v", err)
+	}
+
+	want := api.TASPolicyStatus{Compliance: "Invalid", Message: "unknown strategy types: strategy_unavailable",
+		UnknownStrategies: []string{"strategy_unavailable"}}
+	if !reflect.DeepEqual(updated.Status, want) {
+		t.Errorf("Got status %v, want %v", updated.Status, want)
+	}
+
+	client.Req = nil
+	_ = controller.store.Update(&updated)
+	_ = controller.reconcile("default/policy9")
+
+	if client.Req != nil {
+		t.Errorf("Unchanged status written again")
+	}
+}
+
+func TestTelemetryPolicyController_reconcileInvalidExpression(t *testing.T) {
+	pol := getTASPolicy("policy10", "default", deschedule.StrategyType, []api.TASPolicyRule{
+		{Expression: "metrics.power >", Operator: "LessThan", Target: resource.MustParse("20")}})
+	pol.Spec.Strategies[dontschedule.StrategyType] = api.TASPolicyStrategy{PolicyName: "policy10",
+		Rules: []api.TASPolicyRule{{Expression: "metrics.power / metrics.power_limit > 0.9"}}}
+
+	client := statusClient()
+	enforcer := strategy.MockStrategy{}
+	writer := &recordingCache{metrics: map[string]int{}, policies: map[string]bool{}}
+	controller := &TelemetryPolicyController{Interface: client, Writer: writer, Enforcer: &enforcer,
+		store: clientcache.NewStore(clientcache.MetaNamespaceKeyFunc)}
+
+	_ = controller.store.Add(pol)
+	if err := controller.reconcile("default/policy10"); err != nil {
+		t.Errorf("Unexpected error from reconcile: %v", err)
+	}
+
+	if _, ok := enforcer.AddedStrategies.I.(*dontschedule.Strategy); !ok {
+		t.Errorf("Valid strategy not added, got %v", enforcer.AddedStrategies.I)
+	}
+
+	if want := map[string]int{"power": 1, "power_limit": 1}; !reflect.DeepEqual(controller.policies["default/policy10"].metrics, want) {
+		t.Errorf("Got metric references %v, want %v", controller.policies["default/policy10"].metrics, want)
+	}
+
+	if client.Req == nil {
+		t.Fatalf("Expected a status update")
+	}
+
+	updated := api.TASPolicy{}
+	if err := json.NewDecoder(client.Req.Body).Decode(&updated); err != nil {
+		t.Errorf("Cannot decode status update: %v", err)
+	}
+
+	if updated.Status.Compliance != "Invalid" || !strings.HasPrefix(updated.Status.Message, "invalid strategies: deschedule rule 0") {
+		t.Errorf("Got status %v, want the deschedule strategy reported invalid", updated.Status)
+	}
+}
+
+func TestTelemetryPolicyController_reconcilePolicyMetrics(t *testing.T) {
+	milli := resource.MustParse("0.001")
+	pol := getTASPolicy("power", "default", dontschedule.StrategyType, []api.TASPolicyRule{
+		{Metricname: "power_ratio", Operator: "GreaterThan", Target: resource.MustParse("0.9")}})
+	pol.Spec.Metrics = []api.TASPolicyMetric{
+		{Name: "power_watts", Source: "power_milliwatts", Scale: &milli},
+		{Name: "power_ratio", Expression: "metrics.power_watts / metrics.power_budget"},
+		{Name: "power_excess", Expression: "metrics.power_ratio > 1"},
+	}
+
+	client := statusClient()
+	writer := &recordingCache{metrics: map[string]int{}, policies: map[string]bool{}}
+	controller := &TelemetryPolicyController{Interface: client, Writer: writer, Enforcer: &strategy.MockStrategy{},
+		store: clientcache.NewStore(clientcache.MetaNamespaceKeyFunc)}
+
+	_ = controller.store.Add(pol)
+	if err := controller.reconcile("default/power"); err != nil {
+		t.Errorf("Unexpected error from reconcile: %v", err)
+	}
+
+	if want := map[string]int{"power_ratio": 1, "power_watts": 1}; !reflect.DeepEqual(writer.transforms, want) {
+		t.Errorf("Got transforms %v, want %v", writer.transforms, want)
+	}
+
+	want := map[string]int{"power_ratio": 1, "power_milliwatts": 1, "power_watts": 1, "power_budget": 1}
+	if !reflect.DeepEqual(writer.metrics, want) {
+		t.Errorf("Got metric references %v, want %v", writer.metrics, want)
+	}
+
+	if client.Req == nil {
+		t.Fatalf("Expected a status update")
+	}
+
+	updated := api.TASPolicy{}
+	if err := json.NewDecoder(client.Req.Body).Decode(&updated); err != nil {
+		t.Errorf("Cannot decode status update: %v", err)
+	}
+
+	if updated.Status.Compliance != "Invalid" || !strings.HasPrefix(updated.Status.Message, "invalid metrics: power_excess") {
+		t.Errorf("Got status %v, want the power_excess metric reported invalid", updated.Status)
+	}
+
+	pol = pol.DeepCopy()
+	pol.Spec.Metrics = pol.Spec.Metrics[:1]
+	_ = controller.store.Update(pol)
+	_ = controller.reconcile("default/power")
+
+	if want := map[string]int{"power_watts": 1}; !reflect.DeepEqual(writer.transforms, want) {
+		t.Errorf("Got transforms %v after removing metrics, want %v", writer.transforms, want)
+	}
+
+	_ = controller.store.Delete(pol)
+	_ = controller.reconcile("default/power")
+
+	if len(writer.transforms) > 0 || len(writer.metrics) > 0 {
+		t.Errorf("Got transforms %v and metric references %v after deleting the policy", writer.transforms, writer.metrics)
+	}
+}
+
+func TestTelemetryPolicyController_reconcileHistories(t *testing.T) {
+	trend := &api.TASPolicyRuleTrend{Model: "Linear", Horizon: metav1.Duration{Duration: time.Minute}}
+	pol := getTASPolicy("thermal", "default", deschedule.StrategyType, []api.TASPolicyRule{
+		{Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("80"), Trend: trend},
+		{Metricname: "power", Operator: "GreaterThan", Target: resource.MustParse("300")}})
+	pol.Spec.Strategies[dontschedule.StrategyType] = api.TASPolicyStrategy{
+		Rules: []api.TASPolicyRule{{Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("90"), Trend: trend}}}
+
+	writer := &recordingCache{metrics: map[string]int{}, policies: map[string]bool{}}
+	controller := &TelemetryPolicyController{Interface: statusClient(), Writer: writer, Enforcer: &strategy.MockStrategy{},
+		store: clientcache.NewStore(clientcache.MetaNamespaceKeyFunc)}
+
+	_ = controller.store.Add(pol)
+	if err := controller.reconcile("default/thermal"); err != nil {
+		t.Errorf("Unexpected error from reconcile: %v", err)
+	}
+
+	if want := map[string]int{"temperature": 2}; !reflect.DeepEqual(writer.histories, want) {
+		t.Errorf("Got history references %v, want %v", writer.histories, want)
+	}
+
+	pol = pol.DeepCopy()
+	delete(pol.Spec.Strategies, dontschedule.StrategyType)
+	pol.Spec.Strategies[deschedule.StrategyType].Rules[0].Trend = nil
+	_ = controller.store.Update(pol)
+	_ = controller.reconcile("default/thermal")
+
+	if len(writer.histories) > 0 {
+		t.Errorf("Got history references %v after removing the trends", writer.histories)
+	}
+
+	pol = pol.DeepCopy()
+	pol.Spec.Strategies[deschedule.StrategyType].Rules[1].Trend = trend
+	_ = controller.store.Update(pol)
+	_ = controller.reconcile("default/thermal")
+
+	if want := map[string]int{"power": 1}; !reflect.DeepEqual(writer.histories, want) {
+		t.Errorf("Got history references %v after adding a trend, want %v", writer.histories, want)
+	}
+
+	_ = controller.store.Delete(pol)
+	_ = controller.reconcile("default/thermal")
+
+	if len(writer.histories) > 0 || len(writer.metrics) > 0 {
+		t.Errorf("Got history references %v and metric references %v after deleting the policy", writer.histories, writer.metrics)
+	}
+}
+
+func TestTelemetryPolicyController_reconcileTargetOverrides(t *testing.T) {
+	pol := getTASPolicy("thermal", "default", deschedule.StrategyType, []api.TASPolicyRule{
+		{Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("80")},
+		{Name: "hot", Metricname: "power", Operator: "GreaterThan", Target: resource.MustParse("300")},
+		{Metricname: "fan", Operator: "InRange", Range: &api.TASPolicyRuleRange{Lower: resource.MustParse("0"), Upper: resource.MustParse("10")}}})
+
+	client := statusClient()
+	writer := &recordingCache{metrics: map[string]int{}, policies: map[string]bool{}}
+	controller := &TelemetryPolicyController{Interface: client, Writer: writer, Enforcer: &strategy.MockStrategy{},
+		store: clientcache.NewStore(clientcache.MetaNamespaceKeyFunc)}
+
+	controller.syncNodeTargets(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Annotations: map[string]string{
+		"telemetry.intel.com/default.thermal.temperature.target": "95",
+		"telemetry.intel.com/default.thermal.hot.target":         "350",
+		"telemetry.intel.com/default.thermal.fan.target":         "5",
+		"telemetry.intel.com/default.other.temperature.target":   "70",
+		"telemetry.intel.com/staging.thermal.temperature.target": "85",
+	}}})
+	controller.syncNodeTargets(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Annotations: map[string]string{
+		"telemetry.intel.com/default.thermal.temperature.target": "90",
+	}}})
+	controller.removeNodeTargets(clientcache.DeletedFinalStateUnknown{Obj: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-c"}}})
+
+	_ = controller.store.Add(pol)
+	if err := controller.reconcile("default/thermal"); err != nil {
+		t.Errorf("Unexpected error from reconcile: %v", err)
+	}
+
+	if client.Req == nil {
+		t.Fatalf("Expected a status update")
+	}
+
+	updated := api.TASPolicy{}
+	if err := json.NewDecoder(client.Req.Body).Decode(&updated); err != nil {
+		t.Errorf("Cannot decode status update: %v", err)
+	}
+
+	want := []api.TASPolicyTargetOverride{{Node: "node-b", Rule: "hot", Target: resource.MustParse("350")},
+		{Node: "node-a", Rule: "temperature", Target: resource.MustParse("90")},
+		{Node: "node-b", Rule: "temperature", Target: resource.MustParse("95")}}
+	if !apiequality.Semantic.DeepEqual(updated.Status.TargetOverrides, want) || updated.Status.Compliance != "" {
+		t.Errorf("Got status %v, want overrides %v", updated.Status, want)
+	}
+}
+
+// delayRecordingQueue records the delays of the keys added with AddAfter.
+type delayRecordingQueue struct {
+	workqueue.RateLimitingInterface
+	delays map[string]time.Duration
+}
+
+func (q *delayRecordingQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.delays[fmt.Sprint(item)] = duration
+}
+
+func TestTelemetryPolicyController_reconcileActiveWindows(t *testing.T) {
+	defer func() { strategy.Now = time.Now }()
+
+	strategy.Now = func() time.Time { return time.Date(2024, time.January, 8, 21, 30, 0, 0, time.UTC) }
+
+	pol := getTASPolicy("nightly", "default", deschedule.StrategyType, []api.TASPolicyRule{
+		{Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("80")}})
+	pol.Spec.Strategies[dontschedule.StrategyType] = api.TASPolicyStrategy{PolicyName: "nightly",
+		Rules:         []api.TASPolicyRule{{Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("90")}},
+		ActiveWindows: []api.TASPolicyActiveWindow{{Start: "09:00", End: "22:00"}}}
+	descheduleStrategy := pol.Spec.Strategies[deschedule.StrategyType]
+	descheduleStrategy.ActiveWindows = []api.TASPolicyActiveWindow{{Start: "22:00", End: "06:00"}}
+	pol.Spec.Strategies[deschedule.StrategyType] = descheduleStrategy
+
+	client := statusClient()
+	queue := &delayRecordingQueue{RateLimitingInterface: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		delays: map[string]time.Duration{}}
+	defer queue.ShutDown()
+
+	controller := &TelemetryPolicyController{Interface: client, Writer: cache.MockCache{}, Enforcer: &strategy.MockStrategy{},
+		store: clientcache.NewStore(clientcache.MetaNamespaceKeyFunc), queue: queue}
+
+	_ = controller.store.Add(pol)
+	if err := controller.reconcile("default/nightly"); err != nil {
+		t.Errorf("Unexpected error from reconcile: %v", err)
+	}
+
+	if client.Req == nil {
+		t.Fatalf("Expected a status update")
+	}
+
+	updated := api.TASPolicy{}
+	if err := json.NewDecoder(client.Req.Body).Decode(&updated); err != nil {
+		t.Errorf("Cannot decode status update: %v", err)
+	}
+
+	want := []api.TASPolicyStrategyStatus{{Type: deschedule.StrategyType, Active: false}, {Type: dontschedule.StrategyType, Active: true}}
+	if !reflect.DeepEqual(updated.Status.Strategies, want) {
+		t.Errorf("Got strategies status %v, want %v", updated.Status.Strategies, want)
+	}
+
+	if got := queue.delays["default/nightly"]; got != 30*time.Minute {
+		t.Errorf("Got policy requeued after %v, want %v", got, 30*time.Minute)
+	}
+}
+
+func TestTelemetryPolicyController_handleErr(t *testing.T) {
+	controller := &TelemetryPolicyController{
+		queue: workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(0, 0)),
+	}
+	defer controller.queue.ShutDown()
+
+	for i := 0; i < maxRetries; i++ {
+		controller.handleErr(errNull, "default/policy1")
+	}
+
+	if got := controller.queue.NumRequeues("default/policy1"); got != maxRetries {
+		t.Errorf("Got %d requeues, want %d", got, maxRetries)
+	}
+
+	controller.handleErr(errNull, "default/policy1")
+
+	if got := controller.queue.NumRequeues("default/policy1"); got != 0 {
+		t.Errorf("Policy not dropped after %d retries, got %d requeues", maxRetries, got)
+	}
+
+	controller.handleErr(errNull, "default/policy2")
+	controller.handleErr(nil, "default/policy2")
+
+	if got := controller.queue.NumRequeues("default/policy2"); got != 0 {
+		t.Errorf("Policy not forgotten after success, got %d requeues", got)
+	}
+}
+
+func TestTelemetryPolicyController_SyncedAt(t *testing.T) {
+	controller := &TelemetryPolicyController{
+		queue: workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Hour, time.Hour)),
+	}
+	defer controller.queue.ShutDown()
+
+	controller.trackSync([]string{"default/policy1", "default/policy2"})
+	controller.markSynced("default/policy1")
+
+	if got := controller.SyncedAt(); !got.IsZero() {
+		t.Errorf("Got synced at %v with a policy left to reconcile, want zero time", got)
+	}
+
+	// a failed policy waits in the rate limited queue, which is empty meanwhile
+	controller.queue.AddRateLimited("default/policy2")
+	controller.markSynced("default/policy2")
+
+	if got := controller.SyncedAt(); !got.IsZero() || controller.queue.Len() != 0 {
+		t.Errorf("Got synced at %v with a policy waiting to be retried, want zero time", got)
+	}
+
+	controller.queue.Forget("default/policy2")
+	controller.markSynced("default/policy2")
+
+	syncedAt := controller.SyncedAt()
+	if syncedAt.IsZero() {
+		t.Fatal("Got zero synced at once every policy was reconciled")
+	}
+
+	controller.queue.AddRateLimited("default/policy3")
+	controller.markSynced("default/policy3")
+
+	if got := controller.SyncedAt(); !got.Equal(syncedAt) {
+		t.Errorf("Got synced at %v after a later change, want %v", got, syncedAt)
+	}
+
+	empty := &TelemetryPolicyController{queue: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())}
+	defer empty.queue.ShutDown()
+
+	if empty.trackSync(nil); empty.SyncedAt().IsZero() {
+		t.Errorf("Got zero synced at without policies, want synced at once")
 	}
 }
 
@@ -0,0 +1,175 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	core "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// watchNodes follows the target override annotations of the nodes. Each change is written to the cache and queues the
+// policies the changed overrides belong to, so their status shows the new targets.
+func (controller *TelemetryPolicyController) watchNodes(context context.Context) cache.Controller {
+	source := cache.NewListWatchFromClient(
+		controller.KubeClient.CoreV1().RESTClient(),
+		"nodes",
+		core.NamespaceAll,
+		fields.Everything(),
+	)
+	_, nodeController := cache.NewInformer(
+		source,
+		&core.Node{},
+		0,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.syncNodeTargets,
+			UpdateFunc: func(_, newer interface{}) { controller.syncNodeTargets(newer) },
+			DeleteFunc: controller.removeNodeTargets,
+		},
+	)
+
+	go nodeController.Run(context.Done())
+
+	return nodeController
+}
+
+// syncNodeTargets reads the target overrides from the annotations of the node.
+func (controller *TelemetryPolicyController) syncNodeTargets(obj interface{}) {
+	node, ok := obj.(*core.Node)
+	if !ok {
+		return
+	}
+
+	targets := strategy.TargetOverrides(node.Name, node.Annotations)
+
+	controller.nodesMtx.Lock()
+	previous := controller.nodeTargets[node.Name]
+
+	if apiequality.Semantic.DeepEqual(previous, targets) || (len(previous) == 0 && len(targets) == 0) {
+		controller.nodesMtx.Unlock()
+
+		return
+	}
+
+	if controller.nodeTargets == nil {
+		controller.nodeTargets = map[string]map[string]resource.Quantity{}
+	}
+
+	if len(targets) == 0 {
+		delete(controller.nodeTargets, node.Name)
+	} else {
+		controller.nodeTargets[node.Name] = targets
+	}
+	controller.nodesMtx.Unlock()
+
+	if err := controller.WriteNodeTargets(node.Name, targets); err != nil {
+		klog.V(l2).InfoS(err.Error(), "node", node.Name, "component", "controller")
+	}
+
+	controller.enqueueOverridden(previous, targets)
+}
+
+// removeNodeTargets drops the target overrides of a deleted node.
+func (controller *TelemetryPolicyController) removeNodeTargets(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	node, ok := obj.(*core.Node)
+	if !ok {
+		return
+	}
+
+	controller.nodesMtx.Lock()
+	previous := controller.nodeTargets[node.Name]
+	delete(controller.nodeTargets, node.Name)
+	controller.nodesMtx.Unlock()
+
+	if err := controller.DeleteNodeTargets(node.Name); err != nil {
+		klog.V(l2).InfoS(err.Error(), "node", node.Name, "component", "controller")
+	}
+
+	controller.enqueueOverridden(previous, nil)
+}
+
+// enqueueOverridden queues the policies named in the <namespace>.<policy>.<rule> keys of the passed overrides. Policy
+// names can contain dots, so every policy of the namespace whose name prefixes a key is queued.
+func (controller *TelemetryPolicyController) enqueueOverridden(overrides ...map[string]resource.Quantity) {
+	if controller.store == nil || controller.queue == nil {
+		return
+	}
+
+	for _, key := range controller.store.ListKeys() {
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			continue
+		}
+
+		if overridesPolicy(strategy.TargetOverridePrefix(namespace, name), overrides...) {
+			controller.queue.Add(key)
+		}
+	}
+}
+
+func overridesPolicy(prefix string, overrides ...map[string]resource.Quantity) bool {
+	for _, targets := range overrides {
+		for key := range targets {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// targetOverrides lists the nodes overriding the target of a rule in the policy, sorted by rule and node.
+func (controller *TelemetryPolicyController) targetOverrides(pol *telemetrypolicy.TASPolicy) []telemetrypolicy.TASPolicyTargetOverride {
+	rules := map[string]string{}
+	prefix := strategy.TargetOverridePrefix(pol.Namespace, pol.Name)
+
+	for _, spec := range pol.Spec.Strategies {
+		for _, rule := range spec.Rules {
+			if key := strategy.TargetOverrideKey(pol.Namespace, pol.Name, rule); key != "" && !strategy.IsRangeOperator(rule.Operator) {
+				rules[key] = strings.TrimPrefix(key, prefix)
+			}
+		}
+	}
+
+	controller.nodesMtx.RLock()
+	defer controller.nodesMtx.RUnlock()
+
+	output := []telemetrypolicy.TASPolicyTargetOverride{}
+
+	for nodeName, targets := range controller.nodeTargets {
+		for key, target := range targets {
+			if rule, ok := rules[key]; ok {
+				output = append(output, telemetrypolicy.TASPolicyTargetOverride{Node: nodeName, Rule: rule, Target: target.DeepCopy()})
+			}
+		}
+	}
+
+	sort.Slice(output, func(i, j int) bool {
+		if output[i].Rule != output[j].Rule {
+			return output[i].Rule < output[j].Rule
+		}
+
+		return output[i].Node < output[j].Node
+	})
+
+	if len(output) == 0 {
+		return nil
+	}
+
+	return output
+}
@@ -4,16 +4,64 @@
 package controller
 
 import (
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
 	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	clientcache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
 )
 
 // TelemetryPolicyController instruments the necessary functions for to Register policies to a metrics cache and a Interface registry.
 // Controller embeds a rest interface to Kubernetes which allows it to be passed as a client.
 // It also embeds a cache editor which allows it to write to and delete from a shared cache.
+// Policy events only queue the policy key. A single worker reconciles each queued policy against what the controller
+// registered for it, so the state converges whatever the order and number of events.
 type TelemetryPolicyController struct {
 	rest.Interface
 	cache.Writer
 	Enforcer strategy.Enforcer
+	// ResyncPeriod is how often every policy is queued for reconciliation without a change. Zero disables resync.
+	ResyncPeriod time.Duration
+	// KubeClient is used to watch the node annotations overriding rule targets. Overrides are ignored without it.
+	KubeClient kubernetes.Interface
+	queue      workqueue.RateLimitingInterface
+	store      clientcache.Store
+	policies   map[string]*policyState
+	// nodeTargets holds the target overrides of each node, indexed by their <namespace>.<policy>.<rule> key.
+	nodeTargets map[string]map[string]resource.Quantity
+	nodesMtx    sync.RWMutex
+	// unsynced holds the keys of the policies listed at start which weren't reconciled yet. It's only used by the worker.
+	unsynced map[string]struct{}
+	// syncedAt is when, in Unix nanoseconds, the policies listed at start were all reconciled once.
+	syncedAt atomic.Int64
+}
+
+// policyState is what the controller registered for a single policy.
+// Strategies are indexed by type, metrics and histories hold the number of references the policy has on each metric
+// and on each metric history in the cache and transforms the metrics the policy computes, indexed by name.
+type policyState struct {
+	strategies map[string]registeredStrategy
+	metrics    map[string]int
+	histories  map[string]int
+	transforms map[string]registeredTransform
+}
+
+// registeredStrategy is a strategy added to the enforcer together with the policy section it was built from.
+type registeredStrategy struct {
+	strategy strategy.Interface
+	spec     telemetrypolicy.TASPolicyStrategy
+}
+
+// registeredTransform is a transform written to the cache together with the policy metric it was built from.
+type registeredTransform struct {
+	transform metrics.Transform
+	spec      telemetrypolicy.TASPolicyMetric
 }
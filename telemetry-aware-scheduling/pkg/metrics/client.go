@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	cacheddiscovery "k8s.io/client-go/discovery/cached"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/metrics/pkg/apis/custom_metrics/v1beta2"
@@ -37,9 +38,15 @@ type NodeMetric struct {
 // NodeMetricsInfo holds a map of metric information related to a single named metric. The key for the map is the name of the node.
 type NodeMetricsInfo map[string]NodeMetric
 
+// NodeMetricsHistory holds the recent samples of a single named metric for each node, oldest first. The key for the map is
+// the name of the node.
+type NodeMetricsHistory map[string][]NodeMetric
+
 // CustomMetricsClient embeds a client for the custom Metrics API.
+// Pods maps pods to the node they run on for queries reading pod metrics, which fail without it.
 type CustomMetricsClient struct {
 	customclient.CustomMetricsClient
+	Pods corelisters.PodLister
 }
 
 // NewClient creates a new Metrics Client including discovering and mapping the available APIs, and pulling the API version.
@@ -50,14 +57,33 @@ func NewClient(config *restclient.Config) CustomMetricsClient {
 	restMapper.Reset()
 
 	apiVersionsGetter := customclient.NewAvailableAPIsGetter(discoveryClient)
-	metricsClient := CustomMetricsClient{customclient.NewForConfig(config, restMapper, apiVersionsGetter)}
+	metricsClient := CustomMetricsClient{CustomMetricsClient: customclient.NewForConfig(config, restMapper, apiVersionsGetter)}
 
 	return metricsClient
 }
 
 // GetNodeMetric gets the given metric, time Window for Metric and timestamp for each node in the cluster.
+// The metric name can be the key of a Query, in which case only the selected series are read and the series a node has
+// are folded into one value by the aggregation of the query. The series of a node are those of the pods running on it
+// for queries reading pod metrics.
 func (c CustomMetricsClient) GetNodeMetric(metricName string) (NodeMetricsInfo, error) {
-	metrics, err := c.RootScopedMetrics().GetForObjects(schema.GroupKind{Kind: "Node"}, labels.NewSelector(), metricName, labels.NewSelector())
+	query := ParseQuery(metricName)
+
+	selector, err := labels.Parse(query.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of metric %v: %w", metricName, err)
+	}
+
+	if query.Scope == PodScope {
+		output, err := c.getPodMetric(query, selector)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get pod metric %v: %w", metricName, err)
+		}
+
+		return output, nil
+	}
+
+	metrics, err := c.RootScopedMetrics().GetForObjects(schema.GroupKind{Kind: "Node"}, labels.NewSelector(), query.Metric, selector)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get metric %v from custom metrics API: %w", metricName, err)
 	}
@@ -66,27 +92,48 @@ func (c CustomMetricsClient) GetNodeMetric(metricName string) (NodeMetricsInfo,
 		return nil, fmt.Errorf("metric %v not in custom metrics API %w", metricName, errNull)
 	}
 
-	output := wrapMetrics(metrics)
+	output, err := wrapMetrics(metrics, query.Aggregation)
+	if err != nil {
+		return nil, fmt.Errorf("unable to aggregate metric %v: %w", metricName, err)
+	}
 
 	return output, nil
 }
 
 // wrapMetrics parses the custom metrics API MetricValueList type into a NodeCustomMetricInfo.
-func wrapMetrics(metrics *v1beta2.MetricValueList) NodeMetricsInfo {
-	result := make(NodeMetricsInfo, len(metrics.Items))
+// Nodes with several series get the value folded by the aggregation.
+func wrapMetrics(metrics *v1beta2.MetricValueList, aggregation string) (NodeMetricsInfo, error) {
+	series := make(map[string][]NodeMetric, len(metrics.Items))
 
 	for _, m := range metrics.Items {
-		window := time.Minute
-		if m.WindowSeconds != nil {
-			window = time.Duration(*m.WindowSeconds) * time.Second
-		}
+		series[m.DescribedObject.Name] = append(series[m.DescribedObject.Name], nodeMetric(m))
+	}
+
+	return aggregateSeries(series, aggregation)
+}
+
+// nodeMetric returns the value of a metric series. Series without a window are read over a minute.
+func nodeMetric(m v1beta2.MetricValue) NodeMetric {
+	window := time.Minute
+	if m.WindowSeconds != nil {
+		window = time.Duration(*m.WindowSeconds) * time.Second
+	}
+
+	return NodeMetric{Timestamp: m.Timestamp.Time, Window: window, Value: m.Value}
+}
 
-		result[m.DescribedObject.Name] = NodeMetric{
-			Timestamp: m.Timestamp.Time,
-			Window:    window,
-			Value:     m.Value,
+// aggregateSeries folds the series of each node by the aggregation.
+func aggregateSeries(series map[string][]NodeMetric, aggregation string) (NodeMetricsInfo, error) {
+	result := make(NodeMetricsInfo, len(series))
+
+	for nodeName, nodeSeries := range series {
+		metric, err := aggregate(nodeSeries, aggregation)
+		if err != nil {
+			return nil, err
 		}
+
+		result[nodeName] = metric
 	}
 
-	return result
+	return result, nil
 }
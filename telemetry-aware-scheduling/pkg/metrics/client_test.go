@@ -125,7 +125,7 @@ func Test_customMetricsClient_GetNodeMetric(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			c := CustomMetricsClient{
-				tt.fields.client,
+				CustomMetricsClient: tt.fields.client,
 			}
 			got, err := c.GetNodeMetric(tt.args.metricName)
 
@@ -0,0 +1,157 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/metrics/pkg/apis/custom_metrics/v1beta2"
+)
+
+const l4 = 4
+
+var (
+	errNoPodLister   = fmt.Errorf("pod metrics need a pod lister %w", errNull)
+	errPodsNotSynced = fmt.Errorf("pods scheduled to nodes not listed yet %w", errNull)
+)
+
+// podWatcher lists the pods scheduled to a node from an informer started on its first use.
+type podWatcher struct {
+	ctx        context.Context
+	kubeClient kubernetes.Interface
+	once       sync.Once
+	lister     corelisters.PodLister
+	hasSynced  cache.InformerSynced
+}
+
+// WatchPods returns a lister of the pods scheduled to a node, kept up to date by an informer until the context is done.
+// The informer is only started the first time the pods are listed, which is when a rule reading pod metrics is
+// registered and its metric read, so clusters without pod metrics aren't watched. Listing fails until the informer
+// synced, rather than missing the pods it didn't list yet.
+func WatchPods(ctx context.Context, kubeClient kubernetes.Interface) corelisters.PodLister {
+	return &podWatcher{ctx: ctx, kubeClient: kubeClient}
+}
+
+func (w *podWatcher) start() {
+	w.once.Do(func() {
+		scheduled := fields.OneTermNotEqualSelector("spec.nodeName", "").String()
+		source := &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.FieldSelector = scheduled
+
+				return w.kubeClient.CoreV1().Pods(v1.NamespaceAll).List(w.ctx, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.FieldSelector = scheduled
+
+				return w.kubeClient.CoreV1().Pods(v1.NamespaceAll).Watch(w.ctx, options)
+			},
+		}
+		indexer, podController := cache.NewIndexerInformer(source, &v1.Pod{}, 0, cache.ResourceEventHandlerFuncs{},
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+
+		klog.V(l4).InfoS("Watching pods for pod metrics", "component", "controller")
+
+		go podController.Run(w.ctx.Done())
+
+		w.lister, w.hasSynced = corelisters.NewPodLister(indexer), podController.HasSynced
+	})
+}
+
+// List starts the informer if it isn't running and lists the pods matching the selector once it synced.
+func (w *podWatcher) List(selector labels.Selector) ([]*v1.Pod, error) {
+	w.start()
+
+	if !w.hasSynced() {
+		return nil, errPodsNotSynced
+	}
+
+	pods, err := w.lister.List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list pods: %w", err)
+	}
+
+	return pods, nil
+}
+
+// Pods starts the informer if it isn't running and returns a lister of the pods in the namespace.
+func (w *podWatcher) Pods(namespace string) corelisters.PodNamespaceLister {
+	w.start()
+
+	return w.lister.Pods(namespace)
+}
+
+// getPodMetric reads the metric of the pods in every namespace with pods scheduled to a node, as the custom metrics API
+// serves pod metrics by namespace. Namespaces without the metric are skipped.
+func (c CustomMetricsClient) getPodMetric(query Query, selector labels.Selector) (NodeMetricsInfo, error) {
+	if c.Pods == nil {
+		return nil, errNoPodLister
+	}
+
+	pods, err := c.Pods.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("unable to list pods: %w", err)
+	}
+
+	namespaces := map[string]bool{}
+	for _, pod := range pods {
+		namespaces[pod.Namespace] = true
+	}
+
+	items := []v1beta2.MetricValue{}
+
+	for namespace := range namespaces {
+		metrics, err := c.NamespacedMetrics(namespace).GetForObjects(schema.GroupKind{Kind: "Pod"}, labels.Everything(),
+			query.Metric, selector)
+		if err != nil {
+			klog.V(l4).InfoS("no metric "+query.Metric+" for pods in "+namespace+": "+err.Error(), "component", "controller")
+
+			continue
+		}
+
+		for _, item := range metrics.Items {
+			if item.DescribedObject.Namespace == "" {
+				item.DescribedObject.Namespace = namespace
+			}
+
+			items = append(items, item)
+		}
+	}
+
+	return wrapPodMetrics(items, c.Pods, query.Aggregation)
+}
+
+// wrapPodMetrics attributes the metric of each pod to the node the pod runs on and folds the values of the pods of a
+// node by the aggregation. Pods which aren't known to the lister or not scheduled are skipped.
+func wrapPodMetrics(items []v1beta2.MetricValue, pods corelisters.PodLister, aggregation string) (NodeMetricsInfo, error) {
+	series := map[string][]NodeMetric{}
+
+	for _, item := range items {
+		pod, err := pods.Pods(item.DescribedObject.Namespace).Get(item.DescribedObject.Name)
+		if err != nil || pod.Spec.NodeName == "" {
+			continue
+		}
+
+		series[pod.Spec.NodeName] = append(series[pod.Spec.NodeName], nodeMetric(item))
+	}
+
+	if len(series) == 0 {
+		return nil, fmt.Errorf("no pod with the metric runs on a node %w", errNull)
+	}
+
+	return aggregateSeries(series, aggregation)
+}
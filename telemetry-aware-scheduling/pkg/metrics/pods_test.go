@@ -0,0 +1,126 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	custommetricsapi "k8s.io/metrics/pkg/apis/custom_metrics/v1beta2"
+	cmfake "k8s.io/metrics/pkg/client/custom_metrics/fake"
+)
+
+func podMetric(level int64, namespace, podName string) custommetricsapi.MetricValue {
+	return custommetricsapi.MetricValue{
+		DescribedObject: v1.ObjectReference{Kind: "Pod", Namespace: namespace, Name: podName},
+		Value:           *resource.NewQuantity(level, resource.DecimalSI),
+		Timestamp:       metav1.Time{Time: baseTimeStamp},
+		Metric:          custommetricsapi.MetricIdentifier{Name: "gpu_power"},
+	}
+}
+
+func TestCustomMetricsClient_GetNodeMetricOfPods(t *testing.T) {
+	podMetrics := map[string][]custommetricsapi.MetricValue{
+		"team-a": {podMetric(100, "team-a", "train-1"), podMetric(150, "team-a", "train-2"), podMetric(70, "team-a", "gone")},
+		"team-b": {podMetric(40, "team-b", "infer-1")},
+	}
+	fakeCMClient := &cmfake.FakeCustomMetricsClient{}
+	fakeCMClient.AddReactor("get", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &custommetricsapi.MetricValueList{Items: podMetrics[action.GetNamespace()]}, nil
+	})
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	pods := []struct{ namespace, name, node string }{
+		{"team-a", "train-1", "node-1"}, {"team-a", "train-2", "node-1"}, {"team-b", "infer-1", "node-2"},
+		{"team-b", "pending", ""},
+	}
+
+	for _, pod := range pods {
+		_ = indexer.Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: pod.name, Namespace: pod.namespace},
+			Spec: v1.PodSpec{NodeName: pod.node}})
+	}
+
+	tests := []struct {
+		name    string
+		query   Query
+		want    map[string]string
+		wantErr bool
+	}{
+		{"sum", Query{Metric: "gpu_power", Aggregation: "Sum", Scope: PodScope}, map[string]string{"node-1": "250", "node-2": "40"}, false},
+		{"max", Query{Metric: "gpu_power", Scope: PodScope}, map[string]string{"node-1": "150", "node-2": "40"}, false},
+		{"count", Query{Metric: "gpu_power", Aggregation: "Count", Scope: PodScope}, map[string]string{"node-1": "2", "node-2": "1"}, false},
+		{"node metric", Query{Metric: "gpu_power"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := CustomMetricsClient{CustomMetricsClient: fakeCMClient, Pods: corelisters.NewPodLister(indexer)}
+
+			got, err := c.GetNodeMetric(tt.query.Key())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetNodeMetric() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetNodeMetric() = %v, want %v", got, tt.want)
+			}
+
+			for nodeName, value := range tt.want {
+				if gotValue := got[nodeName].Value; gotValue.Cmp(resource.MustParse(value)) != 0 {
+					t.Errorf("GetNodeMetric() value of %v = %v, want %v", nodeName, gotValue.String(), value)
+				}
+			}
+		})
+	}
+
+	if _, err := (CustomMetricsClient{CustomMetricsClient: fakeCMClient}).GetNodeMetric("pod:gpu_power"); err == nil {
+		t.Errorf("GetNodeMetric() of pod metric without a pod lister succeeded")
+	}
+}
+
+func TestWatchPods(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kubeClient := fake.NewSimpleClientset(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "train-1", Namespace: "team-a"},
+		Spec: v1.PodSpec{NodeName: "node-1"}})
+	pods := WatchPods(ctx, kubeClient)
+
+	if actions := kubeClient.Actions(); len(actions) > 0 {
+		t.Fatalf("Pods watched before they're listed: %v", actions)
+	}
+
+	var (
+		listed []*v1.Pod
+		err    error
+	)
+
+	for deadline := time.Now().Add(wait.ForeverTestTimeout); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		listed, err = pods.List(labels.Everything())
+		if !errors.Is(err, errPodsNotSynced) {
+			break
+		}
+	}
+
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("List() = %v, %v, want the scheduled pod", listed, err)
+	}
+
+	if _, err := pods.Pods("team-a").Get("train-1"); err != nil {
+		t.Errorf("Pods().Get() error = %v", err)
+	}
+}
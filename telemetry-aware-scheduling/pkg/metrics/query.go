@@ -0,0 +1,122 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// DefaultAggregation folds the series a metric has on a node when a query doesn't name an aggregation.
+	DefaultAggregation = "Max"
+	// PodScope is the scope of queries reading the metrics of pods, which are attributed to the node each pod runs on.
+	PodScope = "Pod"
+	// podKeyPrefix starts the keys of queries reading pod metrics.
+	podKeyPrefix = "pod:"
+	// significantDigits is the precision of values computed with floats, which drops the noise of float arithmetic.
+	significantDigits = 15
+)
+
+var errAggregation = fmt.Errorf("unknown aggregation %w", errNull)
+
+// Query selects the series of a metric read from the custom metrics API and how the series a node has are folded into
+// its value. Selector is a label selector in its string form, Aggregation one of Max, Min, Sum, Avg and Count.
+// Queries with the Pod Scope read the metric of every pod and fold the series of the pods running on a node.
+type Query struct {
+	Metric      string
+	Selector    string
+	Aggregation string
+	Scope       string
+}
+
+// Key returns the name under which the query is cached and fetched by a Client. Queries reading every series of a node
+// metric with the default aggregation are keyed by their metric name, others as metric{selector}aggregation, prefixed
+// by pod: for pod metrics. Metric names and label selectors can't contain braces or colons, so the key can be parsed
+// back by ParseQuery.
+func (q Query) Key() string {
+	aggregation := q.Aggregation
+	if aggregation == DefaultAggregation {
+		aggregation = ""
+	}
+
+	key := q.Metric
+	if q.Selector != "" || aggregation != "" {
+		key += "{" + q.Selector + "}" + aggregation
+	}
+
+	if q.Scope == PodScope {
+		key = podKeyPrefix + key
+	}
+
+	return key
+}
+
+// ParseQuery returns the query cached under the given key. Plain metric names read every series of the node metric.
+func ParseQuery(key string) Query {
+	query := Query{}
+
+	if strings.HasPrefix(key, podKeyPrefix) {
+		query.Scope = PodScope
+		key = strings.TrimPrefix(key, podKeyPrefix)
+	}
+
+	start := strings.Index(key, "{")
+	end := strings.LastIndex(key, "}")
+
+	if start < 0 || end < start {
+		query.Metric = key
+
+		return query
+	}
+
+	query.Metric, query.Selector, query.Aggregation = key[:start], key[start+1:end], key[end+1:]
+
+	return query
+}
+
+// aggregate folds the series of a node into a single metric with the oldest timestamp and widest window of the series.
+func aggregate(series []NodeMetric, aggregation string) (NodeMetric, error) {
+	output := series[0]
+	sum := series[0].Value.DeepCopy()
+
+	for _, metric := range series[1:] {
+		output = oldest(output, metric)
+
+		switch aggregation {
+		case "", DefaultAggregation:
+			if metric.Value.Cmp(output.Value) > 0 {
+				output.Value = metric.Value
+			}
+		case "Min":
+			if metric.Value.Cmp(output.Value) < 0 {
+				output.Value = metric.Value
+			}
+		}
+
+		sum.Add(metric.Value)
+	}
+
+	switch aggregation {
+	case "", DefaultAggregation, "Min":
+		return output, nil
+	case "Sum":
+		output.Value = sum
+	case "Count":
+		output.Value = *resource.NewQuantity(int64(len(series)), resource.DecimalSI)
+	case "Avg":
+		value, err := FloatQuantity(sum.AsApproximateFloat64() / float64(len(series)))
+		if err != nil {
+			return NodeMetric{}, fmt.Errorf("average: %w", err)
+		}
+
+		output.Value = value
+	default:
+		return NodeMetric{}, fmt.Errorf("%w: %v", errAggregation, aggregation)
+	}
+
+	return output, nil
+}
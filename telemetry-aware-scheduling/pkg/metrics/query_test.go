@@ -0,0 +1,91 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	custommetricsapi "k8s.io/metrics/pkg/apis/custom_metrics/v1beta2"
+)
+
+func TestQuery_Key(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+		want  string
+	}{
+		{"plain metric", Query{Metric: "temperature"}, "temperature"},
+		{"default aggregation", Query{Metric: "temperature", Aggregation: "Max"}, "temperature"},
+		{"aggregation", Query{Metric: "temperature", Aggregation: "Avg"}, "temperature{}Avg"},
+		{"selector", Query{Metric: "temperature", Selector: "card in (card0,card1)"}, "temperature{card in (card0,card1)}"},
+		{"selector and aggregation", Query{Metric: "temperature", Selector: "socket=0", Aggregation: "Sum"}, "temperature{socket=0}Sum"},
+		{"pod metric", Query{Metric: "gpu_power", Scope: PodScope}, "pod:gpu_power"},
+		{"pod metric aggregation", Query{Metric: "gpu_power", Aggregation: "Count", Scope: PodScope}, "pod:gpu_power{}Count"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.query.Key()
+			if got != tt.want {
+				t.Errorf("Key() = %v, want %v", got, tt.want)
+			}
+
+			parsed := ParseQuery(got)
+			if parsed.Key() != got || parsed.Metric != tt.query.Metric || parsed.Selector != tt.query.Selector || parsed.Scope != tt.query.Scope {
+				t.Errorf("ParseQuery(%v) = %+v, want %+v", got, parsed, tt.query)
+			}
+		})
+	}
+}
+
+func Test_wrapMetrics(t *testing.T) {
+	later := baseTimeStamp.Add(time.Minute)
+	metrics := &custommetricsapi.MetricValueList{Items: []custommetricsapi.MetricValue{
+		dummyMetric(51, "temperature", later, 1),
+		dummyMetric(60, "temperature", baseTimeStamp, 1),
+		dummyMetric(72, "temperature", later, 1),
+		dummyMetric(40, "temperature", baseTimeStamp, 2),
+		dummyMetric(45, "temperature", baseTimeStamp, 2),
+	}}
+
+	tests := []struct {
+		aggregation string
+		want        map[string]string
+		wantErr     bool
+	}{
+		{"", map[string]string{"node-1": "72", "node-2": "45"}, false},
+		{"Max", map[string]string{"node-1": "72", "node-2": "45"}, false},
+		{"Min", map[string]string{"node-1": "51", "node-2": "40"}, false},
+		{"Sum", map[string]string{"node-1": "183", "node-2": "85"}, false},
+		{"Avg", map[string]string{"node-1": "61", "node-2": "42.5"}, false},
+		{"Median", nil, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.aggregation, func(t *testing.T) {
+			got, err := wrapMetrics(metrics, tt.aggregation)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrapMetrics() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("wrapMetrics() = %v, want %v", got, tt.want)
+			}
+
+			for nodeName, value := range tt.want {
+				if gotValue := got[nodeName].Value; gotValue.Cmp(resource.MustParse(value)) != 0 {
+					t.Errorf("wrapMetrics() value of %v = %v, want %v", nodeName, gotValue.String(), value)
+				}
+			}
+
+			if tt.want != nil && !got["node-1"].Timestamp.Equal(baseTimeStamp) {
+				t.Errorf("wrapMetrics() timestamp = %v, want the oldest %v", got["node-1"].Timestamp, baseTimeStamp)
+			}
+		})
+	}
+}
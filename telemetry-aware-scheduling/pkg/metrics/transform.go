@@ -0,0 +1,138 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var errNoInput = fmt.Errorf("transform has no input %w", errNull)
+
+// Transform computes a metric from the values of other metrics. A transform reads either the Source metric or, for
+// derived metrics, the Inputs combined on each node by Derive. The value of each node is then multiplied by Scale,
+// Offset is added and the result is clamped to Min and Max.
+// Expression is the source of Derive, it identifies the derivation when transforms are compared.
+type Transform struct {
+	Source     string
+	Inputs     []string
+	Expression string
+	Derive     func(values map[string]float64) (float64, error)
+	Scale      float64
+	Offset     float64
+	Min        *float64
+	Max        *float64
+}
+
+// Derived checks if the transform combines several metrics rather than transforming a single one.
+func (t Transform) Derived() bool {
+	return t.Source == ""
+}
+
+// Equal checks if two transforms compute the same metric. Derive functions are compared by their expression.
+func (t Transform) Equal(other Transform) bool {
+	t.Derive, other.Derive = nil, nil
+
+	return reflect.DeepEqual(t, other)
+}
+
+// Apply computes the metric from the values of the metrics it reads, indexed by their name. Nodes missing one of the
+// input metrics, or for which the derivation fails, have no value. The timestamp and window of each node are the
+// oldest and widest of its inputs.
+func (t Transform) Apply(inputs map[string]NodeMetricsInfo) (NodeMetricsInfo, error) {
+	names := t.Inputs
+	if !t.Derived() {
+		names = []string{t.Source}
+	}
+
+	if len(names) == 0 || (t.Derived() && t.Derive == nil) {
+		return nil, errNoInput
+	}
+
+	output := NodeMetricsInfo{}
+
+	for nodeName, first := range inputs[names[0]] {
+		values := map[string]float64{}
+		metric := first
+
+		for _, name := range names {
+			nodeMetric, ok := inputs[name][nodeName]
+			if !ok {
+				break
+			}
+
+			values[name] = nodeMetric.Value.AsApproximateFloat64()
+			metric = oldest(metric, nodeMetric)
+		}
+
+		if len(values) != len(names) {
+			continue
+		}
+
+		value := values[t.Source]
+
+		if t.Derived() {
+			derived, err := t.Derive(values)
+			if err != nil {
+				continue
+			}
+
+			value = derived
+		}
+
+		quantity, err := FloatQuantity(t.clamp(value*t.Scale + t.Offset))
+		if err != nil {
+			continue
+		}
+
+		metric.Value = quantity
+		output[nodeName] = metric
+	}
+
+	return output, nil
+}
+
+func (t Transform) clamp(value float64) float64 {
+	if t.Min != nil && value < *t.Min {
+		return *t.Min
+	}
+
+	if t.Max != nil && value > *t.Max {
+		return *t.Max
+	}
+
+	return value
+}
+
+// FloatQuantity returns the quantity of a float rounded to 15 significant digits, which drops the noise of float
+// arithmetic. Infinite and NaN values have no quantity.
+func FloatQuantity(value float64) (resource.Quantity, error) {
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return resource.Quantity{}, fmt.Errorf("value %v is not finite %w", value, errNull)
+	}
+
+	quantity, err := resource.ParseQuantity(strconv.FormatFloat(value, 'g', significantDigits, 64))
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("value %v: %w", value, err)
+	}
+
+	return quantity, nil
+}
+
+// oldest returns a with the oldest timestamp and widest window of both metrics.
+func oldest(a, b NodeMetric) NodeMetric {
+	if b.Timestamp.Before(a.Timestamp) {
+		a.Timestamp = b.Timestamp
+	}
+
+	if b.Window > a.Window {
+		a.Window = b.Window
+	}
+
+	return a
+}
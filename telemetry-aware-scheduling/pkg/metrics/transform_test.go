@@ -0,0 +1,70 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestTransform_Apply(t *testing.T) {
+	later := baseTimeStamp.Add(time.Minute)
+	power := NodeMetricsInfo{
+		"node-1": {Timestamp: baseTimeStamp, Window: time.Second, Value: resource.MustParse("150000")},
+		"node-2": {Timestamp: later, Window: time.Second, Value: resource.MustParse("450000")},
+		"node-3": {Timestamp: later, Window: time.Second, Value: resource.MustParse("100000")},
+	}
+	budget := NodeMetricsInfo{
+		"node-1": {Timestamp: later, Window: 2 * time.Second, Value: resource.MustParse("300")},
+		"node-2": {Timestamp: later, Window: time.Second, Value: resource.MustParse("300")},
+	}
+	ratio := func(values map[string]float64) (float64, error) {
+		return values["power"] / 1000 / values["budget"], nil
+	}
+	zero, one := 0.0, 1.0
+
+	tests := []struct {
+		name      string
+		transform Transform
+		want      map[string]string
+		wantErr   bool
+	}{
+		{"scale", Transform{Source: "power", Scale: 0.001}, map[string]string{"node-1": "150", "node-2": "450", "node-3": "100"}, false},
+		{"scale and offset", Transform{Source: "power", Scale: 0.001, Offset: -100},
+			map[string]string{"node-1": "50", "node-2": "350", "node-3": "0"}, false},
+		{"clamp", Transform{Source: "power", Scale: 0.01, Offset: -1500, Min: &zero, Max: &one},
+			map[string]string{"node-1": "0", "node-2": "1", "node-3": "0"}, false},
+		{"derived", Transform{Inputs: []string{"budget", "power"}, Expression: "ratio", Derive: ratio, Scale: 1},
+			map[string]string{"node-1": "0.5", "node-2": "1.5"}, false},
+		{"missing source", Transform{Source: "temperature", Scale: 1}, map[string]string{}, false},
+		{"no derivation", Transform{Inputs: []string{"power"}, Scale: 1}, nil, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.transform.Apply(map[string]NodeMetricsInfo{"power": power, "budget": budget})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() = %v, want %v", got, tt.want)
+			}
+
+			for nodeName, value := range tt.want {
+				if gotValue := got[nodeName].Value; gotValue.Cmp(resource.MustParse(value)) != 0 {
+					t.Errorf("Apply() value of %v = %v, want %v", nodeName, gotValue.String(), value)
+				}
+			}
+		})
+	}
+
+	got, _ := tests[3].transform.Apply(map[string]NodeMetricsInfo{"power": power, "budget": budget})
+	if !got["node-1"].Timestamp.Equal(baseTimeStamp) || got["node-1"].Window != 2*time.Second {
+		t.Errorf("Apply() = %+v, want the oldest timestamp and widest window of the inputs", got["node-1"])
+	}
+}
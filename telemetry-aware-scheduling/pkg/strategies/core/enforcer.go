@@ -16,12 +16,15 @@ import (
 
 const (
 	l2 = 2
+	l4 = 4
 )
 
 // MetricEnforcer instruments behavior to register strategies and trigger their enforcement actions.
+// Strategies outside their active windows aren't enforced, they're cleaned up once when they become inactive.
 type MetricEnforcer struct {
 	RegisteredStrategies map[string]map[Interface]interface{}
 	KubeClient           kubernetes.Interface
+	inactive             map[Interface]bool
 	sync.RWMutex
 }
 
@@ -79,6 +82,7 @@ func (e *MetricEnforcer) RemoveStrategy(str Interface, strategyType string) {
 	for s := range e.RegisteredStrategies[strategyType] {
 		if s.Equals(str) {
 			delete(e.RegisteredStrategies[strategyType], s)
+			delete(e.inactive, s)
 			msg := fmt.Sprintf("Removed %v: %v from strategy register", s.GetPolicyName(), strategyType)
 			klog.V(l2).InfoS(msg, "component", "controller")
 		}
@@ -135,10 +139,20 @@ func (e *MetricEnforcer) enforceStrategy(strategyType string, cache cache.Reader
 	e.Lock()
 	defer e.Unlock()
 
+	now := Now()
+
 	strList, ok := e.RegisteredStrategies[strategyType]
 	if ok {
 		for str := range strList {
 			if enf, ok := str.(Enforceable); ok {
+				if !IsActive(str, now) {
+					e.deactivate(str, enf)
+
+					continue
+				}
+
+				delete(e.inactive, str)
+
 				_, err := enf.Enforce(e, cache)
 				if err != nil {
 					log.Print("Strategy was not enforceable.", err.Error(), "component", "controller")
@@ -147,3 +161,25 @@ func (e *MetricEnforcer) enforceStrategy(strategyType string, cache cache.Reader
 		}
 	}
 }
+
+// deactivate cleans up after a strategy which has left its active windows. It does nothing if the strategy is
+// already inactive.
+func (e *MetricEnforcer) deactivate(str Interface, enf Enforceable) {
+	if e.inactive[str] {
+		return
+	}
+
+	if e.inactive == nil {
+		e.inactive = map[Interface]bool{}
+	}
+
+	e.inactive[str] = true
+	msg := fmt.Sprintf("Strategy %v: %v is outside its active windows", str.GetPolicyName(), str.StrategyType())
+	klog.V(l2).InfoS(msg, "component", "controller")
+
+	err := enf.Cleanup(e, str.GetPolicyName())
+	if err != nil {
+		msg := fmt.Sprintf("Failed to clean up inactive strategy: %v", err)
+		klog.V(l2).InfoS(msg, "component", "controller")
+	}
+}
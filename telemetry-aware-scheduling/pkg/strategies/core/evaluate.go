@@ -0,0 +1,245 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"strconv"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+)
+
+// RuleResult is a rule violated on a node together with the value violating it.
+type RuleResult struct {
+	Rule  telempol.TASPolicyRule
+	Value resource.Quantity
+}
+
+// RuleEvaluation is a rule evaluated on a node. Metric is the value the node has for the rule, nil without a value,
+// and Value the value the rule was violated by or, if it isn't violated, compared against. For rules with a trend it's
+// the predicted value when the prediction violates the rule.
+type RuleEvaluation struct {
+	Rule     telempol.TASPolicyRule
+	Metric   *metrics.NodeMetric
+	Value    resource.Quantity
+	Violated bool
+}
+
+// NodeEvaluation holds the evaluation of every rule of a strategy on a node, in the order of the strategy rules, and
+// whether the rules combined violate the strategy.
+type NodeEvaluation struct {
+	Rules    []RuleEvaluation
+	Violated bool
+}
+
+// Evaluate applies the rules of a strategy to every node with a value for at least one of them and returns the nodes
+// on which the strategy is violated. Each node is mapped to the rules it violates, in the order of the strategy rules,
+// with relative targets resolved and the targets overridden by node annotations applied. Rules with a trend are also
+// violated by the value predicted from the node metric history, which is then the value of the result.
+// The rules are combined by the strategy group or, without a group, by its logical operator: allOf needs every rule
+// violated, anyOf (the default) a single one.
+func Evaluate(spec telempol.TASPolicyStrategy, reader cache.Reader) map[string][]RuleResult {
+	nodeResults := map[string][]RuleResult{}
+
+	for nodeName, evaluation := range EvaluateNodes(spec, reader) {
+		if !evaluation.Violated {
+			continue
+		}
+
+		results := []RuleResult{}
+
+		for _, rule := range evaluation.Rules {
+			if rule.Violated {
+				results = append(results, RuleResult{Rule: rule.Rule, Value: rule.Value})
+			}
+		}
+
+		nodeResults[nodeName] = results
+	}
+
+	return nodeResults
+}
+
+// EvaluateNodes evaluates the rules of a strategy like Evaluate, but returns every node with a value for at least one
+// of the rules together with the outcome of each rule, violated or not.
+func EvaluateNodes(spec telempol.TASPolicyStrategy, reader cache.Reader) map[string]NodeEvaluation {
+	nodeNames := map[string]bool{}
+	rules := append([]telempol.TASPolicyRule{}, spec.Rules...)
+	nodeMetrics := make([]metrics.NodeMetricsInfo, len(spec.Rules))
+
+	for i, rule := range spec.Rules {
+		values, err := RuleMetrics(rule, reader)
+		if err != nil {
+			klog.V(l4).InfoS(err.Error(), "component", "controller")
+
+			continue
+		}
+
+		rules[i], err = ResolveTarget(rule, values)
+		if err != nil {
+			klog.V(l4).InfoS(err.Error(), "component", "controller")
+
+			continue
+		}
+
+		nodeMetrics[i] = values
+
+		for nodeName := range values {
+			nodeNames[nodeName] = true
+		}
+	}
+
+	evaluations := make(map[string]NodeEvaluation, len(nodeNames))
+	for nodeName := range nodeNames {
+		evaluations[nodeName] = NodeEvaluation{Rules: make([]RuleEvaluation, len(rules))}
+	}
+
+	for i, rule := range rules {
+		overrides := reader.ReadTargetOverrides(TargetOverrideKey(spec.PolicyNamespace, spec.PolicyName, rule))
+		history := ruleHistory(rule, reader)
+
+		for nodeName, evaluation := range evaluations {
+			nodeRule := NodeRule(rule, overrides, nodeName)
+			evaluation.Rules[i] = RuleEvaluation{Rule: nodeRule}
+
+			nodeMetric, ok := nodeMetrics[i][nodeName]
+			if !ok {
+				continue
+			}
+
+			value, violated := evaluateNode(nodeRule, nodeMetric.Value, history[nodeName])
+			evaluation.Rules[i] = RuleEvaluation{Rule: nodeRule, Metric: &nodeMetric, Value: value, Violated: violated}
+		}
+	}
+
+	group := strategyGroup(spec)
+	names := ruleIndexes(spec.Rules)
+
+	for nodeName, evaluation := range evaluations {
+		violated := make([]bool, len(evaluation.Rules))
+		for i, rule := range evaluation.Rules {
+			violated[i] = rule.Violated
+		}
+
+		evaluation.Violated = evaluateGroup(group, names, violated)
+		evaluations[nodeName] = evaluation
+	}
+
+	return evaluations
+}
+
+// ruleHistory returns the metric history used to predict the trend of the rule, if it has one.
+func ruleHistory(rule telempol.TASPolicyRule, reader cache.Reader) metrics.NodeMetricsHistory {
+	if rule.Trend == nil {
+		return nil
+	}
+
+	key, err := MetricKey(rule, rule.Metricname)
+	if err != nil {
+		klog.V(l4).InfoS(err.Error(), "component", "controller")
+
+		return nil
+	}
+
+	history, err := reader.ReadMetricHistory(key)
+	if err != nil {
+		klog.V(l4).InfoS(err.Error(), "component", "controller")
+	}
+
+	return history
+}
+
+// evaluateNode checks the current value of a node against the rule and, for rules with a trend, the value predicted
+// from its samples. It returns the violating value.
+func evaluateNode(rule telempol.TASPolicyRule, value resource.Quantity, samples []metrics.NodeMetric) (resource.Quantity, bool) {
+	if EvaluateRule(value, rule) {
+		return value, true
+	}
+
+	if rule.Trend == nil || len(samples) == 0 {
+		return value, false
+	}
+
+	predicted, err := Predict(samples, *rule.Trend)
+	if err != nil {
+		klog.V(l4).InfoS(err.Error(), "component", "controller")
+
+		return value, false
+	}
+
+	return predicted, EvaluateRule(predicted, rule)
+}
+
+// strategyGroup returns the group of the strategy, building a flat one from the logical operator if there's none.
+func strategyGroup(spec telempol.TASPolicyStrategy) telempol.TASPolicyRuleGroup {
+	if spec.Group != nil {
+		return *spec.Group
+	}
+
+	children := make([]telempol.TASPolicyRuleGroup, 0, len(spec.Rules))
+	for i := range spec.Rules {
+		children = append(children, telempol.TASPolicyRuleGroup{Rule: ruleKey(spec.Rules[i], i)})
+	}
+
+	if spec.LogicalOperator == telempol.AllOf {
+		return telempol.TASPolicyRuleGroup{AllOf: children}
+	}
+
+	return telempol.TASPolicyRuleGroup{AnyOf: children}
+}
+
+// ruleIndexes maps the key of each rule to its index. Unnamed rules can't be used in groups, but still need a key for
+// the group built from the logical operator.
+func ruleIndexes(rules []telempol.TASPolicyRule) map[string]int {
+	names := make(map[string]int, len(rules))
+	for i := range rules {
+		names[ruleKey(rules[i], i)] = i
+	}
+
+	return names
+}
+
+func ruleKey(rule telempol.TASPolicyRule, index int) string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+
+	// Rule names in groups come from the API and can't contain a NUL character, so this doesn't clash with them.
+	return "\x00" + strconv.Itoa(index)
+}
+
+// evaluateGroup checks if the group holds given the violated rules. A group naming an unknown rule never holds.
+func evaluateGroup(group telempol.TASPolicyRuleGroup, names map[string]int, violated []bool) bool {
+	count := func(children []telempol.TASPolicyRuleGroup) int {
+		holding := 0
+
+		for _, child := range children {
+			if evaluateGroup(child, names, violated) {
+				holding++
+			}
+		}
+
+		return holding
+	}
+
+	switch {
+	case group.Rule != "":
+		i, ok := names[group.Rule]
+
+		return ok && violated[i]
+	case len(group.AnyOf) > 0:
+		return count(group.AnyOf) > 0
+	case len(group.AllOf) > 0:
+		return count(group.AllOf) == len(group.AllOf)
+	case len(group.NoneOf) > 0:
+		return count(group.NoneOf) == 0
+	case group.AtLeast != nil:
+		return count(group.AtLeast.Of) >= int(group.AtLeast.Count)
+	}
+
+	return false
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestEvaluate(t *testing.T) {
+	mockCache := cache.MockEmptySelfUpdatingCache()
+	now := time.Now()
+
+	for name, values := range map[string]map[string]string{
+		"temperature": {"node A": "90", "node B": "90", "node C": "90", "node D": "40"},
+		"power":       {"node A": "400", "node B": "100", "node C": "100", "node D": "400"},
+		"fan":         {"node A": "3000", "node B": "3000", "node C": "100", "node D": "100"},
+	} {
+		info := metrics.NodeMetricsInfo{}
+		for node, value := range values {
+			info[node] = metrics.NodeMetric{Value: resource.MustParse(value), Timestamp: now, Window: time.Second}
+		}
+
+		if err := mockCache.WriteMetric(name, info); err != nil {
+			t.Fatalf("Cannot write metric %v to mock cache: %v", name, err)
+		}
+	}
+
+	rules := []telemetrypolicy.TASPolicyRule{
+		{Name: "temp", Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("80")},
+		{Name: "power", Metricname: "power", Operator: "GreaterThan", Target: resource.MustParse("300")},
+		{Name: "fan", Metricname: "fan", Operator: "LessThan", Target: resource.MustParse("500")},
+	}
+	ref := func(names ...string) []telemetrypolicy.TASPolicyRuleGroup {
+		groups := []telemetrypolicy.TASPolicyRuleGroup{}
+		for _, name := range names {
+			groups = append(groups, telemetrypolicy.TASPolicyRuleGroup{Rule: name})
+		}
+
+		return groups
+	}
+
+	tests := []struct {
+		name            string
+		logicalOperator telemetrypolicy.LogicalOperator
+		group           *telemetrypolicy.TASPolicyRuleGroup
+		want            map[string][]string
+	}{
+		{name: "anyOf logical operator",
+			want: map[string][]string{"node A": {"temp", "power"}, "node B": {"temp"}, "node C": {"temp", "fan"},
+				"node D": {"power", "fan"}}},
+		{name: "allOf logical operator", logicalOperator: telemetrypolicy.AllOf, want: map[string][]string{}},
+		{name: "nested group",
+			group: &telemetrypolicy.TASPolicyRuleGroup{AllOf: []telemetrypolicy.TASPolicyRuleGroup{
+				{Rule: "temp"}, {AnyOf: ref("power", "fan")}}},
+			want: map[string][]string{"node A": {"temp", "power"}, "node C": {"temp", "fan"}}},
+		{name: "noneOf",
+			group: &telemetrypolicy.TASPolicyRuleGroup{AllOf: []telemetrypolicy.TASPolicyRuleGroup{
+				{Rule: "temp"}, {NoneOf: ref("power", "fan")}}},
+			want: map[string][]string{"node B": {"temp"}}},
+		{name: "atLeast",
+			group: &telemetrypolicy.TASPolicyRuleGroup{AtLeast: &telemetrypolicy.TASPolicyAtLeast{Count: 2,
+				Of: ref("temp", "power", "fan")}},
+			want: map[string][]string{"node A": {"temp", "power"}, "node C": {"temp", "fan"}, "node D": {"power", "fan"}}},
+		{name: "group takes precedence over logical operator", logicalOperator: telemetrypolicy.AllOf,
+			group: &telemetrypolicy.TASPolicyRuleGroup{Rule: "fan"},
+			want:  map[string][]string{"node C": {"temp", "fan"}, "node D": {"power", "fan"}}},
+		{name: "unknown rule", group: &telemetrypolicy.TASPolicyRuleGroup{Rule: "memory"}, want: map[string][]string{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			spec := telemetrypolicy.TASPolicyStrategy{LogicalOperator: tt.logicalOperator, Rules: rules, Group: tt.group}
+			got := map[string][]string{}
+
+			for node, results := range Evaluate(spec, mockCache) {
+				for _, result := range results {
+					got[node] = append(got[node], result.Rule.Name)
+				}
+			}
+
+			for _, names := range got {
+				sort.Strings(names)
+			}
+
+			for _, names := range tt.want {
+				sort.Strings(names)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateNodes(t *testing.T) {
+	mockCache := cache.MockEmptySelfUpdatingCache()
+	now := time.Now()
+
+	for name, values := range map[string]map[string]string{
+		"temperature": {"node A": "90", "node B": "40"},
+		"power":       {"node A": "100", "node C": "400"},
+	} {
+		info := metrics.NodeMetricsInfo{}
+		for node, value := range values {
+			info[node] = metrics.NodeMetric{Value: resource.MustParse(value), Timestamp: now, Window: time.Second}
+		}
+
+		if err := mockCache.WriteMetric(name, info); err != nil {
+			t.Fatalf("Cannot write metric %v to mock cache: %v", name, err)
+		}
+	}
+
+	spec := telemetrypolicy.TASPolicyStrategy{LogicalOperator: telemetrypolicy.AllOf, Rules: []telemetrypolicy.TASPolicyRule{
+		{Name: "temp", Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("80")},
+		{Name: "power", Metricname: "power", Operator: "GreaterThan", Target: resource.MustParse("300")},
+	}}
+	want := map[string]string{"node A": "violated passed", "node B": "passed none", "node C": "none violated"}
+
+	got := map[string]string{}
+
+	for node, evaluation := range EvaluateNodes(spec, mockCache) {
+		if evaluation.Violated {
+			t.Errorf("EvaluateNodes() violates allOf on %v", node)
+		}
+
+		results := []string{}
+
+		for _, rule := range evaluation.Rules {
+			switch {
+			case rule.Metric == nil:
+				results = append(results, "none")
+			case rule.Violated:
+				results = append(results, "violated")
+			default:
+				results = append(results, "passed")
+			}
+		}
+
+		got[node] = results[0] + " " + results[1]
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EvaluateNodes() = %v, want %v", got, want)
+	}
+}
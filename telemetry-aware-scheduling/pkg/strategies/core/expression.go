@@ -0,0 +1,360 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	celops "github.com/google/cel-go/common/operators"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/lru"
+)
+
+const (
+	// metricsVariable is the name expressions use to reach the metric values of a node, e.g. metrics.power.
+	metricsVariable = "metrics"
+	// maxCachedExpressions bounds the compiled expressions kept, the least recently used are dropped first.
+	maxCachedExpressions = 1024
+	// expressionCostLimit bounds the CEL cost of a single evaluation, so expressions looping over the metrics can't
+	// stall enforcement.
+	expressionCostLimit = 10000
+)
+
+var (
+	errExpressionType    = errors.New("expression must evaluate to a bool or a number")
+	errExpressionMetrics = errors.New("expression must read at least one metric as metrics.<name> or metrics[\"<name>\"]")
+	errExpressionResult  = errors.New("unexpected expression result")
+)
+
+var (
+	expressionEnv     *cel.Env
+	expressionEnvErr  error
+	expressionEnvOnce sync.Once
+
+	expressions = lru.New(maxCachedExpressions)
+)
+
+// Expression is a compiled CEL rule expression.
+// It's evaluated once per node with the metrics it reads bound to the map variable metrics.
+type Expression struct {
+	program cel.Program
+	// Metrics holds the sorted names of the metrics the expression reads.
+	Metrics []string
+	// Bool is true when the expression evaluates to a bool rather than a number.
+	Bool bool
+}
+
+func environment() (*cel.Env, error) {
+	expressionEnvOnce.Do(func() {
+		expressionEnv, expressionEnvErr = cel.NewEnv(
+			cel.Variable(metricsVariable, cel.MapType(cel.StringType, cel.DoubleType)),
+			cel.CrossTypeNumericComparisons(true))
+	})
+
+	return expressionEnv, expressionEnvErr
+}
+
+// CompileExpression parses and type checks a rule expression. Compiled expressions are cached by their source, so
+// compiling when a policy is ingested makes later evaluations cheap.
+func CompileExpression(source string) (*Expression, error) {
+	if cached, ok := expressions.Get(source); ok {
+		if expr, ok := cached.(*Expression); ok {
+			return expr, nil
+		}
+	}
+
+	expr, err := ParseExpression(source)
+	if err != nil {
+		return nil, err
+	}
+
+	expressions.Add(source, expr)
+
+	return expr, nil
+}
+
+// ParseExpression parses and type checks a rule expression without caching it. It's meant for checks of expressions
+// which may never be enforced, like the admission of policies.
+func ParseExpression(source string) (*Expression, error) {
+	env, err := environment()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create expression environment: %w", err)
+	}
+
+	ast, issues := env.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression: %w", issues.Err())
+	}
+
+	expr := &Expression{}
+
+	switch ast.OutputType().String() {
+	case cel.BoolType.String():
+		expr.Bool = true
+	case cel.DoubleType.String(), cel.IntType.String(), cel.UintType.String():
+	default:
+		return nil, fmt.Errorf("%w, not %v", errExpressionType, ast.OutputType())
+	}
+
+	expr.Metrics = referencedMetrics(ast.Expr())
+	if len(expr.Metrics) == 0 {
+		return nil, errExpressionMetrics
+	}
+
+	expr.program, err = env.Program(ast, cel.CostLimit(expressionCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+
+	return expr, nil
+}
+
+// referencedMetrics returns the sorted names of the metrics read by the expression.
+func referencedMetrics(root *exprpb.Expr) []string {
+	names := map[string]struct{}{}
+
+	var walk func(e *exprpb.Expr)
+	walk = func(e *exprpb.Expr) {
+		switch kind := e.GetExprKind().(type) {
+		case *exprpb.Expr_SelectExpr:
+			if kind.SelectExpr.GetOperand().GetIdentExpr().GetName() == metricsVariable {
+				names[kind.SelectExpr.GetField()] = struct{}{}
+			}
+
+			walk(kind.SelectExpr.GetOperand())
+		case *exprpb.Expr_CallExpr:
+			args := kind.CallExpr.GetArgs()
+			if kind.CallExpr.GetFunction() == celops.Index && len(args) == 2 &&
+				args[0].GetIdentExpr().GetName() == metricsVariable && args[1].GetConstExpr() != nil {
+				names[args[1].GetConstExpr().GetStringValue()] = struct{}{}
+			}
+
+			if kind.CallExpr.GetTarget() != nil {
+				walk(kind.CallExpr.GetTarget())
+			}
+
+			for _, arg := range args {
+				walk(arg)
+			}
+		case *exprpb.Expr_ListExpr:
+			for _, element := range kind.ListExpr.GetElements() {
+				walk(element)
+			}
+		case *exprpb.Expr_StructExpr:
+			for _, entry := range kind.StructExpr.GetEntries() {
+				walk(entry.GetMapKey())
+				walk(entry.GetValue())
+			}
+		case *exprpb.Expr_ComprehensionExpr:
+			for _, sub := range []*exprpb.Expr{kind.ComprehensionExpr.GetIterRange(), kind.ComprehensionExpr.GetAccuInit(),
+				kind.ComprehensionExpr.GetLoopCondition(), kind.ComprehensionExpr.GetLoopStep(),
+				kind.ComprehensionExpr.GetResult()} {
+				walk(sub)
+			}
+		}
+	}
+
+	walk(root)
+	delete(names, "")
+
+	output := make([]string, 0, len(names))
+	for name := range names {
+		output = append(output, name)
+	}
+
+	sort.Strings(output)
+
+	return output
+}
+
+// Evaluate runs the expression against the metric values of a single node.
+// Bool results are returned as 1 for true and 0 for false.
+func (e *Expression) Evaluate(values map[string]float64) (resource.Quantity, error) {
+	out, _, err := e.program.Eval(map[string]interface{}{metricsVariable: values})
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("cannot evaluate expression: %w", err)
+	}
+
+	var result float64
+
+	switch value := out.Value().(type) {
+	case bool:
+		if value {
+			result = 1
+		}
+	case float64:
+		result = value
+	case int64:
+		result = float64(value)
+	case uint64:
+		result = float64(value)
+	default:
+		return resource.Quantity{}, fmt.Errorf("%w: %v", errExpressionResult, out)
+	}
+
+	quantity, err := metrics.FloatQuantity(result)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("%w: %v", errExpressionResult, err)
+	}
+
+	return quantity, nil
+}
+
+// CompileRules compiles the expressions and metric selectors of the rules, returning the first which fails.
+func CompileRules(rules []telempol.TASPolicyRule) error {
+	for i, rule := range rules {
+		if _, err := MetricKey(rule, rule.Metricname); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+
+		if rule.Expression == "" {
+			continue
+		}
+
+		if _, err := CompileExpression(rule.Expression); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// RuleMetricKeys returns the cache keys of the metrics a rule reads: its metric or the metrics used in its expression,
+// with the series selected and aggregated as set by the rule.
+func RuleMetricKeys(rule telempol.TASPolicyRule) []string {
+	names := []string{rule.Metricname}
+
+	if rule.Expression != "" {
+		expr, err := CompileExpression(rule.Expression)
+		if err != nil {
+			return nil
+		}
+
+		names = expr.Metrics
+	}
+
+	keys := make([]string, 0, len(names))
+
+	for _, name := range names {
+		key, err := MetricKey(rule, name)
+		if err != nil {
+			return nil
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// MetricKey returns the key of a metric read by the rule in the cache. Rules selecting series by their labels,
+// aggregating them or reading pod metrics read a metrics.Query, others the metric itself.
+func MetricKey(rule telempol.TASPolicyRule, metricName string) (string, error) {
+	query := metrics.Query{Metric: metricName, Aggregation: string(rule.Aggregation), Scope: string(rule.Scope)}
+
+	if rule.MetricSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rule.MetricSelector)
+		if err != nil {
+			return "", fmt.Errorf("invalid metric selector: %w", err)
+		}
+
+		query.Selector = selector.String()
+	}
+
+	return query.Key(), nil
+}
+
+// RuleName returns the metric name of a rule, or its expression for expression rules.
+func RuleName(rule telempol.TASPolicyRule) string {
+	if rule.Expression == "" {
+		return rule.Metricname
+	}
+
+	return rule.Expression
+}
+
+// RuleMetrics returns the value each node has for the rule: its metric for metric rules and the result of the
+// expression for expression rules. An expression is only evaluated on nodes with a value for every metric it reads.
+// The returned timestamp and window are the oldest and widest of the metrics used.
+func RuleMetrics(rule telempol.TASPolicyRule, reader cache.Reader) (metrics.NodeMetricsInfo, error) {
+	if rule.Expression == "" {
+		key, err := MetricKey(rule, rule.Metricname)
+		if err != nil {
+			return nil, err
+		}
+
+		return reader.ReadMetric(key)
+	}
+
+	expr, err := CompileExpression(rule.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]map[string]float64{}
+	output := metrics.NodeMetricsInfo{}
+
+	for _, name := range expr.Metrics {
+		key, err := MetricKey(rule, name)
+		if err != nil {
+			return nil, err
+		}
+
+		nodeMetrics, err := reader.ReadMetric(key)
+		if err != nil {
+			return nil, err
+		}
+
+		for nodeName, nodeMetric := range nodeMetrics {
+			if _, ok := values[nodeName]; !ok {
+				values[nodeName] = map[string]float64{}
+			}
+
+			values[nodeName][name] = nodeMetric.Value.AsApproximateFloat64()
+			output[nodeName] = oldestMetric(output[nodeName], nodeMetric)
+		}
+	}
+
+	for nodeName, nodeValues := range values {
+		if len(nodeValues) != len(expr.Metrics) {
+			delete(output, nodeName)
+
+			continue
+		}
+
+		value, err := expr.Evaluate(nodeValues)
+		if err != nil {
+			delete(output, nodeName)
+
+			continue
+		}
+
+		info := output[nodeName]
+		info.Value = value
+		output[nodeName] = info
+	}
+
+	return output, nil
+}
+
+func oldestMetric(current, other metrics.NodeMetric) metrics.NodeMetric {
+	if current.Timestamp.IsZero() || other.Timestamp.Before(current.Timestamp) {
+		current.Timestamp = other.Timestamp
+	}
+
+	if other.Window > current.Window {
+		current.Window = other.Window
+	}
+
+	return current
+}
@@ -0,0 +1,209 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCompileExpression(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		wantMetrics []string
+		wantBool    bool
+		wantErr     bool
+	}{
+		{name: "bool expression", source: "metrics.power / metrics.power_limit > 0.9 && metrics.temp > 70",
+			wantMetrics: []string{"power", "power_limit", "temp"}, wantBool: true},
+		{name: "numeric expression", source: `metrics.power / metrics["power_limit"]`,
+			wantMetrics: []string{"power", "power_limit"}},
+		{name: "string result", source: `"metrics.power"`, wantErr: true},
+		{name: "no metrics", source: "1.0 > 0.5", wantErr: true},
+		{name: "syntax error", source: "metrics.power >", wantErr: true},
+		{name: "unknown variable", source: "power > 10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompileExpression(tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CompileExpression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got.Metrics, tt.wantMetrics) || got.Bool != tt.wantBool {
+				t.Errorf("CompileExpression() = %v bool %v, want %v bool %v", got.Metrics, got.Bool, tt.wantMetrics, tt.wantBool)
+			}
+		})
+	}
+}
+
+func TestParseExpression(t *testing.T) {
+	source := "metrics.parsed_only > 1"
+
+	if _, err := ParseExpression(source); err != nil {
+		t.Fatalf("ParseExpression() error = %v", err)
+	}
+
+	if _, ok := expressions.Get(source); ok {
+		t.Errorf("ParseExpression() cached %q", source)
+	}
+
+	if _, err := CompileExpression(source); err != nil {
+		t.Fatalf("CompileExpression() error = %v", err)
+	}
+
+	if _, ok := expressions.Get(source); !ok {
+		t.Errorf("CompileExpression() didn't cache %q", source)
+	}
+
+	for i := 0; i <= maxCachedExpressions; i++ {
+		if _, err := CompileExpression("metrics.power > " + strconv.Itoa(i)); err != nil {
+			t.Fatalf("CompileExpression() error = %v", err)
+		}
+	}
+
+	if expressions.Len() > maxCachedExpressions {
+		t.Errorf("cached %v expressions, want at most %v", expressions.Len(), maxCachedExpressions)
+	}
+}
+
+func TestExpression_EvaluateCostLimit(t *testing.T) {
+	elements := make([]string, 200)
+	for i := range elements {
+		elements[i] = strconv.Itoa(i)
+	}
+
+	list := "[" + strings.Join(elements, ",") + "]"
+
+	expr, err := ParseExpression(list + ".all(x, " + list + ".all(y, x + y >= 0)) && metrics.power > 0.0")
+	if err != nil {
+		t.Fatalf("ParseExpression() error = %v", err)
+	}
+
+	if _, err := expr.Evaluate(map[string]float64{"power": 1}); err == nil {
+		t.Errorf("Evaluate() of an expression over the cost limit didn't fail")
+	}
+}
+
+func TestRuleMetrics(t *testing.T) {
+	mockCache := cache.MockEmptySelfUpdatingCache()
+	now := time.Now()
+
+	for name, values := range map[string]map[string]string{
+		"power":               {"node A": "270", "node B": "100", "node C": "300"},
+		"power_limit":         {"node A": "300", "node B": "400"},
+		"temp":                {"node A": "75", "node B": "80"},
+		"temp{card=card0}Avg": {"node A": "65"},
+	} {
+		info := metrics.NodeMetricsInfo{}
+		for node, value := range values {
+			info[node] = metrics.NodeMetric{Value: resource.MustParse(value), Timestamp: now, Window: time.Second}
+		}
+
+		if err := mockCache.WriteMetric(name, info); err != nil {
+			t.Fatalf("Cannot write metric %v to mock cache: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		rule         telemetrypolicy.TASPolicyRule
+		want         map[string]string
+		wantViolated []string
+	}{
+		{name: "bool expression",
+			rule: telemetrypolicy.TASPolicyRule{Expression: "metrics.power / metrics.power_limit > 0.8 && metrics.temp > 70"},
+			want: map[string]string{"node A": "1", "node B": "0"}, wantViolated: []string{"node A"}},
+		{name: "numeric expression compared to target",
+			rule: telemetrypolicy.TASPolicyRule{Expression: "metrics.power / metrics.power_limit", Operator: "GreaterThan",
+				Target: resource.MustParse("0.5")},
+			want: map[string]string{"node A": "0.9", "node B": "0.25"}, wantViolated: []string{"node A"}},
+		{name: "metric rule",
+			rule: telemetrypolicy.TASPolicyRule{Metricname: "power", Operator: "LessThan", Target: resource.MustParse("200")},
+			want: map[string]string{"node A": "270", "node B": "100", "node C": "300"}, wantViolated: []string{"node B"}},
+		{name: "metric rule selecting series",
+			rule: telemetrypolicy.TASPolicyRule{Metricname: "temp", Operator: "GreaterThan", Target: resource.MustParse("70"),
+				MetricSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"card": "card0"}}, Aggregation: "Avg"},
+			want: map[string]string{"node A": "65"}, wantViolated: []string{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RuleMetrics(tt.rule, mockCache)
+			if err != nil {
+				t.Fatalf("RuleMetrics() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("RuleMetrics() = %v, want %v", got, tt.want)
+			}
+
+			violated := []string{}
+
+			for node, value := range tt.want {
+				gotValue := got[node].Value
+				if gotValue.Cmp(resource.MustParse(value)) != 0 {
+					t.Errorf("RuleMetrics() %v = %v, want %v", node, gotValue.String(), value)
+				}
+
+				if EvaluateRule(got[node].Value, tt.rule) {
+					violated = append(violated, node)
+				}
+			}
+
+			if !reflect.DeepEqual(violated, tt.wantViolated) {
+				t.Errorf("EvaluateRule() violated on %v, want %v", violated, tt.wantViolated)
+			}
+		})
+	}
+}
+
+func TestRuleMetricKeys(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "card", Operator: metav1.LabelSelectorOpIn, Values: []string{"card1", "card0"}}}}
+
+	tests := []struct {
+		name string
+		rule telemetrypolicy.TASPolicyRule
+		want []string
+	}{
+		{"metric rule", telemetrypolicy.TASPolicyRule{Metricname: "temp"}, []string{"temp"}},
+		{"default aggregation", telemetrypolicy.TASPolicyRule{Metricname: "temp", Aggregation: "Max"}, []string{"temp"}},
+		{"selected series", telemetrypolicy.TASPolicyRule{Metricname: "temp", MetricSelector: selector, Aggregation: "Min"},
+			[]string{"temp{card in (card0,card1)}Min"}},
+		{"expression", telemetrypolicy.TASPolicyRule{Expression: "metrics.power / metrics.power_limit", Aggregation: "Sum"},
+			[]string{"power{}Sum", "power_limit{}Sum"}},
+		{"pod metric", telemetrypolicy.TASPolicyRule{Metricname: "gpu_power", Aggregation: "Sum", Scope: "Pod"},
+			[]string{"pod:gpu_power{}Sum"}},
+		{"node scope", telemetrypolicy.TASPolicyRule{Metricname: "temp", Scope: "Node"}, []string{"temp"}},
+		{"invalid selector", telemetrypolicy.TASPolicyRule{Metricname: "temp", MetricSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "card", Operator: "Near"}}}}, nil},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RuleMetricKeys(tt.rule); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RuleMetricKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,55 +4,271 @@
 package core
 
 import (
+	"slices"
 	"sort"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
-	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
 )
 
-// EvaluateRule returns a boolean after implementing the function described in the TASPolicyRule.
-// The rule is transformed into a function inside of the method.
-func EvaluateRule(value resource.Quantity, rule telempol.TASPolicyRule) bool {
-	operators := map[string]func(resource.Quantity, int64) bool{
-		"LessThan": func(value resource.Quantity, target int64) bool {
-			return value.CmpInt64(target) == -1
+// operator implements a rule operator.
+// evaluate reports whether a value meets the rule. score grows the further a value goes in the direction the
+// operator points, e.g. larger values score higher for GreaterThan and values closer to the target score higher for
+// Equals. Ordering and picking the strongest of several violated rules both use the score, so they agree with
+// evaluate.
+type operator struct {
+	evaluate func(value resource.Quantity, rule telempol.TASPolicyRule) bool
+	score    func(value resource.Quantity, rule telempol.TASPolicyRule) resource.Quantity
+}
+
+// operators holds the implementation of each rule operator.
+var operators = map[telempol.Operator]operator{
+	telempol.LessThan: {
+		evaluate: func(value resource.Quantity, rule telempol.TASPolicyRule) bool { return value.Cmp(rule.Target) < 0 },
+		score:    negativeValue,
+	},
+	telempol.LessOrEqual: {
+		evaluate: func(value resource.Quantity, rule telempol.TASPolicyRule) bool { return value.Cmp(rule.Target) <= 0 },
+		score:    negativeValue,
+	},
+	telempol.GreaterThan: {
+		evaluate: func(value resource.Quantity, rule telempol.TASPolicyRule) bool { return value.Cmp(rule.Target) > 0 },
+		score:    positiveValue,
+	},
+	telempol.GreaterOrEqual: {
+		evaluate: func(value resource.Quantity, rule telempol.TASPolicyRule) bool { return value.Cmp(rule.Target) >= 0 },
+		score:    positiveValue,
+	},
+	telempol.Equals: {
+		evaluate: func(value resource.Quantity, rule telempol.TASPolicyRule) bool { return value.Cmp(rule.Target) == 0 },
+		score: func(value resource.Quantity, rule telempol.TASPolicyRule) resource.Quantity {
+			return negate(distance(value, rule.Target, rule.Target))
+		},
+	},
+	telempol.NotEquals: {
+		evaluate: func(value resource.Quantity, rule telempol.TASPolicyRule) bool { return value.Cmp(rule.Target) != 0 },
+		score: func(value resource.Quantity, rule telempol.TASPolicyRule) resource.Quantity {
+			return distance(value, rule.Target, rule.Target)
 		},
-		"GreaterThan": func(value resource.Quantity, target int64) bool {
-			return value.CmpInt64(target) == 1
+	},
+	telempol.InRange: {
+		evaluate: func(value resource.Quantity, rule telempol.TASPolicyRule) bool {
+			return rule.Range != nil && value.Cmp(rule.Range.Lower) >= 0 && value.Cmp(rule.Range.Upper) <= 0
 		},
-		"Equals": func(value resource.Quantity, target int64) bool {
-			return value.CmpInt64(target) == 0
+		score: func(value resource.Quantity, rule telempol.TASPolicyRule) resource.Quantity {
+			return negate(rangeDistance(value, rule))
 		},
+	},
+	telempol.OutOfRange: {
+		evaluate: func(value resource.Quantity, rule telempol.TASPolicyRule) bool {
+			return rule.Range != nil && (value.Cmp(rule.Range.Lower) < 0 || value.Cmp(rule.Range.Upper) > 0)
+		},
+		score: rangeDistance,
+	},
+}
+
+// trueExpression is used by bool expression rules, which have no operator. Their value is 1 when the expression holds.
+var trueExpression = operator{
+	evaluate: func(value resource.Quantity, _ telempol.TASPolicyRule) bool { return value.Sign() != 0 },
+	score:    positiveValue,
+}
+
+// operatorFor returns the implementation of the rule operator.
+func operatorFor(rule telempol.TASPolicyRule) (operator, bool) {
+	if rule.Operator == "" && rule.Expression != "" {
+		return trueExpression, true
 	}
-	if _, ok := operators[rule.Operator]; !ok {
-		klog.InfoS("Invalid operator type:"+rule.Operator, "component", "controller")
 
+	op, ok := operators[rule.Operator]
+
+	return op, ok
+}
+
+func positiveValue(value resource.Quantity, _ telempol.TASPolicyRule) resource.Quantity {
+	return value.DeepCopy()
+}
+
+func negativeValue(value resource.Quantity, _ telempol.TASPolicyRule) resource.Quantity {
+	return negate(value)
+}
+
+func negate(value resource.Quantity) resource.Quantity {
+	out := value.DeepCopy()
+	out.Neg()
+
+	return out
+}
+
+// distance returns how far the value is outside of [lower, upper], zero if it's inside.
+func distance(value, lower, upper resource.Quantity) resource.Quantity {
+	out := resource.Quantity{Format: value.Format}
+
+	switch {
+	case value.Cmp(lower) < 0:
+		out = lower.DeepCopy()
+		out.Sub(value)
+	case value.Cmp(upper) > 0:
+		out = value.DeepCopy()
+		out.Sub(upper)
+	}
+
+	return out
+}
+
+func rangeDistance(value resource.Quantity, rule telempol.TASPolicyRule) resource.Quantity {
+	if rule.Range == nil {
+		return resource.Quantity{}
+	}
+
+	return distance(value, rule.Range.Lower, rule.Range.Upper)
+}
+
+// IsSupportedOperator checks if rules with the given operator can be evaluated.
+func IsSupportedOperator(operator telempol.Operator) bool {
+	_, ok := operators[operator]
+
+	return ok
+}
+
+// IsRangeOperator checks if the operator compares against the rule range rather than the target.
+func IsRangeOperator(operator telempol.Operator) bool {
+	return operator == telempol.InRange || operator == telempol.OutOfRange
+}
+
+// TargetString returns what the rule compares metric values against: its range for range operators and its target
+// otherwise.
+func TargetString(rule telempol.TASPolicyRule) string {
+	if rule.Operator == "" && rule.Expression != "" {
+		return "true"
+	}
+
+	if IsRangeOperator(rule.Operator) && rule.Range != nil {
+		return "[" + rule.Range.Lower.String() + ", " + rule.Range.Upper.String() + "]"
+	}
+
+	return rule.Target.String()
+}
+
+// EqualTargets checks if two rules compare metric values against the same target, relative target and range, and
+// predict them with the same trend.
+func EqualTargets(a, b telempol.TASPolicyRule) bool {
+	if a.Target.Cmp(b.Target) != 0 || (a.Range == nil) != (b.Range == nil) {
 		return false
 	}
 
-	return operators[rule.Operator](value, rule.Target)
+	if !equality.Semantic.DeepEqual(a.Relative, b.Relative) || !equality.Semantic.DeepEqual(a.Trend, b.Trend) {
+		return false
+	}
+
+	return a.Range == nil || (a.Range.Lower.Cmp(b.Range.Lower) == 0 && a.Range.Upper.Cmp(b.Range.Upper) == 0)
+}
+
+// EqualRules checks if two strategies have the same rules, comparing every field of their rules in order, and combine
+// them the same way with their logical operator and rule group.
+func EqualRules(a, b telempol.TASPolicyStrategy) bool {
+	if a.LogicalOperator != b.LogicalOperator || len(a.Rules) != len(b.Rules) {
+		return false
+	}
+
+	for i := range a.Rules {
+		if !equalRule(a.Rules[i], b.Rules[i]) {
+			return false
+		}
+	}
+
+	return equality.Semantic.DeepEqual(a.Group, b.Group)
+}
+
+func equalRule(a, b telempol.TASPolicyRule) bool {
+	return a.Name == b.Name && a.Metricname == b.Metricname && a.Expression == b.Expression && a.Operator == b.Operator &&
+		slices.Equal(a.Labels, b.Labels) && EqualTargets(a, b) && EqualSeries(a, b)
+}
+
+// EqualSeries checks if two rules read the same kind of metrics and select and aggregate their series in the same way.
+func EqualSeries(a, b telempol.TASPolicyRule) bool {
+	return a.Aggregation == b.Aggregation && a.Scope == b.Scope && equality.Semantic.DeepEqual(a.MetricSelector, b.MetricSelector)
+}
+
+// SupportedOperators returns the sorted names of all rule operators.
+func SupportedOperators() []string {
+	output := make([]string, 0, len(operators))
+	for name := range operators {
+		output = append(output, string(name))
+	}
+
+	sort.Strings(output)
+
+	return output
+}
+
+// EvaluateRule returns a boolean after implementing the function described in the TASPolicyRule.
+// The rule is transformed into a function inside of the method.
+func EvaluateRule(value resource.Quantity, rule telempol.TASPolicyRule) bool {
+	op, ok := operatorFor(rule)
+	if !ok {
+		klog.InfoS("Invalid operator type:"+string(rule.Operator), "component", "controller")
+
+		return false
+	}
+
+	return op.evaluate(value, rule)
+}
+
+// Score returns how strongly the value goes in the direction of the rule operator, so values can be compared
+// across rules using the same operator. A higher score means a larger value for GreaterThan, a smaller one for
+// LessThan, a value closer to the target or range for Equals and InRange and a value further from it for NotEquals
+// and OutOfRange. Unsupported operators score zero.
+func Score(value resource.Quantity, rule telempol.TASPolicyRule) resource.Quantity {
+	op, ok := operatorFor(rule)
+	if !ok {
+		return resource.Quantity{}
+	}
+
+	return op.score(value, rule)
 }
 
-// OrderedList will return a list of nodes ordered by their linked metric and operator.
+// OrderedList will return a list of nodes ordered by their linked metric and the rule operator.
+// Nodes scoring higher for the rule come first. Nodes with equal scores are ordered by name.
+// Nodes overriding the rule target are scored relative to their own target, so each node is ranked by its margin to the
+// target that applies to it. Without overrides this is the same order as ranking by the metric values.
 // TODO: Make this method more generic so it can use objects other than nodes.
-func OrderedList(metricsInfo metrics.NodeMetricsInfo, operator string) []NodeSortableMetric {
+func OrderedList(metricsInfo metrics.NodeMetricsInfo, rule telempol.TASPolicyRule, overrides map[string]resource.Quantity) []NodeSortableMetric {
 	mtrcs := []NodeSortableMetric{}
+	scores := map[string]resource.Quantity{}
+
 	for name, info := range metricsInfo {
 		mtrcs = append(mtrcs, NodeSortableMetric{name, info.Value})
+		scores[name] = margin(info.Value, NodeRule(rule, overrides, name))
 	}
 
-	switch operator {
-	case "GreaterThan":
-		sort.Slice(mtrcs, func(i, j int) bool { return mtrcs[i].MetricValue.Cmp(mtrcs[j].MetricValue) == 1 })
-	case "LessThan":
-		sort.Slice(mtrcs, func(i, j int) bool { return mtrcs[i].MetricValue.Cmp(mtrcs[j].MetricValue) == -1 })
-	}
+	sort.Slice(mtrcs, func(i, j int) bool {
+		left, right := scores[mtrcs[i].NodeName], scores[mtrcs[j].NodeName]
+		if cmp := left.Cmp(right); cmp != 0 {
+			return cmp > 0
+		}
+
+		return mtrcs[i].NodeName < mtrcs[j].NodeName
+	})
 
 	return mtrcs
 }
 
+// margin returns the score of the value less the score of the rule target. Range operators score against the range
+// and aren't shifted.
+func margin(value resource.Quantity, rule telempol.TASPolicyRule) resource.Quantity {
+	score := Score(value, rule)
+	if IsRangeOperator(rule.Operator) {
+		return score
+	}
+
+	score.Sub(Score(rule.Target, rule))
+
+	return score
+}
+
 // NodeSortableMetric type is necessary in order to call the sort.Slice method.
 // Note lack of usage of time windows or stamps.
 type NodeSortableMetric struct {
@@ -9,7 +9,7 @@ import (
 	"time"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
-	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
@@ -34,25 +34,56 @@ func TestOperator(t *testing.T) {
 		want bool
 	}{
 		{name: "LessThan true",
-			args: args{value: 100, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "LessThan", Target: 1000}},
+			args: args{value: 100, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "LessThan", Target: resource.MustParse("1000")}},
 			want: true},
 		{name: "GreaterThan true",
-			args: args{value: 100000, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "GreaterThan", Target: 1}},
+			args: args{value: 100000, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("1")}},
 			want: true},
 		{name: "Equals true",
-			args: args{value: 1, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "Equals", Target: 1}},
+			args: args{value: 1, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "Equals", Target: resource.MustParse("1")}},
 			want: true},
 		{name: "LessThan false",
-			args: args{value: 10000, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "LessThan", Target: 10}}},
+			args: args{value: 10000, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "LessThan", Target: resource.MustParse("10")}}},
 		{name: "GreaterThan false",
-			args: args{value: 1, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "GreaterThan", Target: 10000}}},
+			args: args{value: 1, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("10000")}}},
 		{name: "Equals false",
-			args: args{value: 1, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "Equals", Target: 100}}},
+			args: args{value: 1, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "Equals", Target: resource.MustParse("100")}}},
+		{name: "GreaterOrEqual at target",
+			args: args{value: 80, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "GreaterOrEqual", Target: resource.MustParse("80")}},
+			want: true},
+		{name: "GreaterOrEqual below fractional target",
+			args: args{value: 79, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "GreaterOrEqual", Target: resource.MustParse("79.5")}}},
+		{name: "LessOrEqual at target",
+			args: args{value: 80, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "LessOrEqual", Target: resource.MustParse("80")}},
+			want: true},
+		{name: "LessOrEqual above target",
+			args: args{value: 81, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "LessOrEqual", Target: resource.MustParse("80")}}},
+		{name: "NotEquals true",
+			args: args{value: 1, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "NotEquals", Target: resource.MustParse("100")}},
+			want: true},
+		{name: "NotEquals false",
+			args: args{value: 1, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "NotEquals", Target: resource.MustParse("1")}}},
+		{name: "InRange at bound",
+			args: args{value: 10, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "InRange",
+				Range: &telemetrypolicy.TASPolicyRuleRange{Lower: resource.MustParse("10"), Upper: resource.MustParse("20")}}},
+			want: true},
+		{name: "InRange above",
+			args: args{value: 21, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "InRange",
+				Range: &telemetrypolicy.TASPolicyRuleRange{Lower: resource.MustParse("10"), Upper: resource.MustParse("20")}}}},
+		{name: "OutOfRange below",
+			args: args{value: 9, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "OutOfRange",
+				Range: &telemetrypolicy.TASPolicyRuleRange{Lower: resource.MustParse("10"), Upper: resource.MustParse("20")}}},
+			want: true},
+		{name: "OutOfRange at bound",
+			args: args{value: 20, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "OutOfRange",
+				Range: &telemetrypolicy.TASPolicyRuleRange{Lower: resource.MustParse("10"), Upper: resource.MustParse("20")}}}},
+		{name: "Range operator without range",
+			args: args{value: 9, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "OutOfRange"}}},
 		{name: "Invalid Operator",
-			args: args{value: 100, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "ABCDE", Target: 1000}},
+			args: args{value: 100, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "ABCDE", Target: resource.MustParse("1000")}},
 			want: false},
 		{name: "Blank Operator",
-			args: args{value: 100, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "", Target: 1000}},
+			args: args{value: 100, rule: telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "", Target: resource.MustParse("1000")}},
 			want: false},
 	}
 	for _, tt := range tests {
@@ -69,7 +100,7 @@ func TestOperator(t *testing.T) {
 func TestOrderedList(t *testing.T) {
 	type args struct {
 		metricsInfo metrics.NodeMetricsInfo
-		operator    string
+		rule        telemetrypolicy.TASPolicyRule
 	}
 
 	tests := []struct {
@@ -78,22 +109,54 @@ func TestOrderedList(t *testing.T) {
 		want []NodeSortableMetric
 	}{
 		{"less than test",
-			args{testNodeMetricCustomInfo([]string{"node A", "node B", "node C"}, []int64{100, 200, 10}), "LessThan"},
+			args{testNodeMetricCustomInfo([]string{"node A", "node B", "node C"}, []int64{100, 200, 10}),
+				telemetrypolicy.TASPolicyRule{Operator: "LessThan"}},
 			[]NodeSortableMetric{
 				{"node C", *resource.NewQuantity(10, resource.DecimalSI)},
 				{"node A", *resource.NewQuantity(100, resource.DecimalSI)},
 				{"node B", *resource.NewQuantity(200, resource.DecimalSI)}}},
 		{"greater than test",
-			args{testNodeMetricCustomInfo([]string{"node A", "node B", "node C"}, []int64{100, 200, 10}), "GreaterThan"},
+			args{testNodeMetricCustomInfo([]string{"node A", "node B", "node C"}, []int64{100, 200, 10}),
+				telemetrypolicy.TASPolicyRule{Operator: "GreaterThan"}},
 			[]NodeSortableMetric{
 				{"node B", *resource.NewQuantity(200, resource.DecimalSI)},
 				{"node A", *resource.NewQuantity(100, resource.DecimalSI)},
 				{"node C", *resource.NewQuantity(10, resource.DecimalSI)}}},
+		{"greater or equal ties ordered by name",
+			args{testNodeMetricCustomInfo([]string{"node B", "node A", "node C"}, []int64{100, 100, 200}),
+				telemetrypolicy.TASPolicyRule{Operator: "GreaterOrEqual"}},
+			[]NodeSortableMetric{
+				{"node C", *resource.NewQuantity(200, resource.DecimalSI)},
+				{"node A", *resource.NewQuantity(100, resource.DecimalSI)},
+				{"node B", *resource.NewQuantity(100, resource.DecimalSI)}}},
+		{"equals closest to target first",
+			args{testNodeMetricCustomInfo([]string{"node A", "node B", "node C"}, []int64{100, 200, 10}),
+				telemetrypolicy.TASPolicyRule{Operator: "Equals", Target: resource.MustParse("180")}},
+			[]NodeSortableMetric{
+				{"node B", *resource.NewQuantity(200, resource.DecimalSI)},
+				{"node A", *resource.NewQuantity(100, resource.DecimalSI)},
+				{"node C", *resource.NewQuantity(10, resource.DecimalSI)}}},
+		{"out of range furthest first",
+			args{testNodeMetricCustomInfo([]string{"node A", "node B", "node C"}, []int64{100, 200, 10}),
+				telemetrypolicy.TASPolicyRule{Operator: "OutOfRange", Range: &telemetrypolicy.TASPolicyRuleRange{
+					Lower: resource.MustParse("50"), Upper: resource.MustParse("150")}}},
+			[]NodeSortableMetric{
+				{"node B", *resource.NewQuantity(200, resource.DecimalSI)},
+				{"node C", *resource.NewQuantity(10, resource.DecimalSI)},
+				{"node A", *resource.NewQuantity(100, resource.DecimalSI)}}},
+		{"in range inside first",
+			args{testNodeMetricCustomInfo([]string{"node A", "node B", "node C"}, []int64{100, 200, 10}),
+				telemetrypolicy.TASPolicyRule{Operator: "InRange", Range: &telemetrypolicy.TASPolicyRuleRange{
+					Lower: resource.MustParse("50"), Upper: resource.MustParse("150")}}},
+			[]NodeSortableMetric{
+				{"node A", *resource.NewQuantity(100, resource.DecimalSI)},
+				{"node C", *resource.NewQuantity(10, resource.DecimalSI)},
+				{"node B", *resource.NewQuantity(200, resource.DecimalSI)}}},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			got := OrderedList(tt.args.metricsInfo, tt.args.operator)
+			got := OrderedList(tt.args.metricsInfo, tt.args.rule, nil)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("OrderedList() = %v, want %v", got, tt.want)
 			}
@@ -101,3 +164,45 @@ func TestOrderedList(t *testing.T) {
 		)
 	}
 }
+
+func TestEqualRules(t *testing.T) {
+	base := func() telemetrypolicy.TASPolicyStrategy {
+		return telemetrypolicy.TASPolicyStrategy{
+			LogicalOperator: telemetrypolicy.AnyOf,
+			Rules: []telemetrypolicy.TASPolicyRule{{
+				Name: "hot", Metricname: "temperature", Operator: telemetrypolicy.GreaterThan,
+				Target: resource.MustParse("80"), Labels: []string{"hot=true"},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(*telemetrypolicy.TASPolicyStrategy)
+		want   bool
+	}{
+		{"same rules", func(*telemetrypolicy.TASPolicyStrategy) {}, true},
+		{"equal quantities", func(s *telemetrypolicy.TASPolicyStrategy) { s.Rules[0].Target = resource.MustParse("80.0") }, true},
+		{"logical operator", func(s *telemetrypolicy.TASPolicyStrategy) { s.LogicalOperator = telemetrypolicy.AllOf }, false},
+		{"rule name", func(s *telemetrypolicy.TASPolicyStrategy) { s.Rules[0].Name = "warm" }, false},
+		{"metric", func(s *telemetrypolicy.TASPolicyStrategy) { s.Rules[0].Metricname = "power" }, false},
+		{"operator", func(s *telemetrypolicy.TASPolicyStrategy) { s.Rules[0].Operator = telemetrypolicy.LessThan }, false},
+		{"target", func(s *telemetrypolicy.TASPolicyStrategy) { s.Rules[0].Target = resource.MustParse("90") }, false},
+		{"labels", func(s *telemetrypolicy.TASPolicyStrategy) { s.Rules[0].Labels = append(s.Rules[0].Labels, "warm=true") }, false},
+		{"aggregation", func(s *telemetrypolicy.TASPolicyStrategy) { s.Rules[0].Aggregation = telemetrypolicy.Max }, false},
+		{"group", func(s *telemetrypolicy.TASPolicyStrategy) { s.Group = &telemetrypolicy.TASPolicyRuleGroup{Rule: "hot"} }, false},
+		{"rule count", func(s *telemetrypolicy.TASPolicyStrategy) { s.Rules = append(s.Rules, s.Rules[0]) }, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			changed := base()
+			tt.change(&changed)
+
+			if got := EqualRules(base(), changed); got != tt.want {
+				t.Errorf("EqualRules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"strings"
+
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+)
+
+// Node annotations of the form telemetry.intel.com/<namespace>.<policy>.<rule>.target override the target of a rule
+// of the policy in that namespace on that node, where <rule> is the name of the rule or, for unnamed rules, its metric
+// name. Namespaces can't contain dots, so the first dot always ends the namespace.
+const (
+	TargetAnnotationPrefix = telempol.Group + "/"
+	TargetAnnotationSuffix = ".target"
+)
+
+// TargetOverrideKey returns the <namespace>.<policy>.<rule> key under which nodes override the target of the rule.
+// Unnamed expression rules can't be overridden and have an empty key.
+func TargetOverrideKey(namespace, policyName string, rule telempol.TASPolicyRule) string {
+	ruleName := rule.Name
+	if ruleName == "" {
+		ruleName = rule.Metricname
+	}
+
+	if ruleName == "" {
+		return ""
+	}
+
+	return TargetOverridePrefix(namespace, policyName) + ruleName
+}
+
+// TargetOverridePrefix returns the prefix of the keys overriding the targets of the policy.
+func TargetOverridePrefix(namespace, policyName string) string {
+	return namespace + "." + policyName + "."
+}
+
+// TargetOverrides returns the targets overridden by the annotations of a node, indexed by their
+// <namespace>.<policy>.<rule> key. Annotations holding an invalid quantity are skipped.
+func TargetOverrides(nodeName string, annotations map[string]string) map[string]resource.Quantity {
+	output := map[string]resource.Quantity{}
+
+	for annotation, value := range annotations {
+		if !strings.HasPrefix(annotation, TargetAnnotationPrefix) || !strings.HasSuffix(annotation, TargetAnnotationSuffix) {
+			continue
+		}
+
+		key := strings.TrimSuffix(strings.TrimPrefix(annotation, TargetAnnotationPrefix), TargetAnnotationSuffix)
+		if strings.Count(key, ".") < 2 {
+			continue
+		}
+
+		target, err := resource.ParseQuantity(value)
+		if err != nil {
+			klog.V(l2).InfoS("invalid target in annotation "+annotation+" of node "+nodeName+": "+err.Error(), "component", "controller")
+
+			continue
+		}
+
+		output[key] = target
+	}
+
+	return output
+}
+
+// NodeRule returns the rule with the target overridden for the node, if there is an override. Range operators don't
+// use the target and are returned unchanged.
+func NodeRule(rule telempol.TASPolicyRule, overrides map[string]resource.Quantity, nodeName string) telempol.TASPolicyRule {
+	if target, ok := overrides[nodeName]; ok && !IsRangeOperator(rule.Operator) {
+		rule.Target = target
+	}
+
+	return rule
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestTargetOverrides(t *testing.T) {
+	got := TargetOverrides("node A", map[string]string{
+		"telemetry.intel.com/default.thermal.temperature.target":      "95",
+		"telemetry.intel.com/gpus.gpu.policy.hot-cards.target":        "80.5",
+		"telemetry.intel.com/default.thermal.fan.target":              "fast",
+		"telemetry.intel.com/thermal.temperature.target":              "90",
+		"telemetry.intel.com/default.thermal.temperature.upper-bound": "90",
+		"example.com/default.thermal.temperature.target":              "90",
+	})
+	want := map[string]resource.Quantity{
+		"default.thermal.temperature": resource.MustParse("95"),
+		"gpus.gpu.policy.hot-cards":   resource.MustParse("80.5"),
+	}
+
+	if !equality.Semantic.DeepEqual(got, want) {
+		t.Errorf("TargetOverrides() = %v, want %v", got, want)
+	}
+}
+
+func TestEvaluateTargetOverrides(t *testing.T) {
+	mockCache := cache.MockEmptySelfUpdatingCache()
+	info := metrics.NodeMetricsInfo{}
+
+	for node, value := range map[string]string{"node A": "85", "node B": "85", "node C": "96"} {
+		info[node] = metrics.NodeMetric{Value: resource.MustParse(value), Timestamp: time.Now(), Window: time.Second}
+	}
+
+	if err := mockCache.WriteMetric("temperature", info); err != nil {
+		t.Fatalf("Cannot write metric to mock cache: %v", err)
+	}
+
+	for node, target := range map[string]string{"node B": "90", "node C": "100"} {
+		if err := mockCache.WriteNodeTargets(node, map[string]resource.Quantity{"default.thermal.temperature": resource.MustParse(target)}); err != nil {
+			t.Fatalf("Cannot write node targets to mock cache: %v", err)
+		}
+	}
+
+	rule := telemetrypolicy.TASPolicyRule{Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("80")}
+	spec := telemetrypolicy.TASPolicyStrategy{PolicyName: "thermal", PolicyNamespace: "default", Rules: []telemetrypolicy.TASPolicyRule{rule}}
+
+	got := map[string]string{}
+	for node, results := range Evaluate(spec, mockCache) {
+		got[node] = results[0].Rule.Target.String()
+	}
+
+	if want := map[string]string{"node A": "80"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Evaluate() violated with targets %v, want %v", got, want)
+	}
+
+	for _, other := range []telemetrypolicy.TASPolicyStrategy{{PolicyName: "other", PolicyNamespace: "default"},
+		{PolicyName: "thermal", PolicyNamespace: "staging"}} {
+		other.Rules = spec.Rules
+		if got := Evaluate(other, mockCache); len(got) != len(info) {
+			t.Errorf("Evaluate() of policy %v/%v violated on %v, want all nodes", other.PolicyNamespace, other.PolicyName, got)
+		}
+	}
+
+	if key := TargetOverrideKey("default", "thermal", rule); key != "default.thermal.temperature" {
+		t.Errorf("TargetOverrideKey() = %v, want default.thermal.temperature", key)
+	}
+
+	ordered := OrderedList(info, rule, mockCache.ReadTargetOverrides("default.thermal.temperature"))
+	want := []NodeSortableMetric{{"node A", resource.MustParse("85")}, {"node C", resource.MustParse("96")},
+		{"node B", resource.MustParse("85")}}
+
+	if !reflect.DeepEqual(ordered, want) {
+		t.Errorf("OrderedList() = %v, want %v", ordered, want)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+)
+
+// ErrUnknownStrategyType is returned when no factory is registered for a strategy type.
+var ErrUnknownStrategyType = errors.New("unknown strategy type")
+
+// Factory builds a strategy of a specific type from the strategy section of a policy.
+type Factory func(policy telempol.TASPolicyStrategy) Interface
+
+var (
+	factories   = map[string]Factory{}
+	factoriesMu sync.RWMutex
+)
+
+// Register makes a strategy type available to the controller under the given name.
+// Strategy packages call it from their init function, so importing a package is enough to enable its strategy.
+// Register panics if the name is empty, the factory is nil or a factory is already registered under the name.
+func Register(strategyType string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if strategyType == "" || factory == nil {
+		panic("strategy registration needs a type name and a factory")
+	}
+
+	if _, ok := factories[strategyType]; ok {
+		panic("strategy type registered twice: " + strategyType)
+	}
+
+	factories[strategyType] = factory
+}
+
+// New builds the strategy registered under the given type name from the policy strategy.
+func New(strategyType string, policy telempol.TASPolicyStrategy) (Interface, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[strategyType]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownStrategyType, strategyType)
+	}
+
+	return factory(policy), nil
+}
+
+// RegisteredTypes returns the sorted names of all strategy types with a registered factory.
+func RegisteredTypes() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	output := make([]string, 0, len(factories))
+	for name := range factories {
+		output = append(output, name)
+	}
+
+	sort.Strings(output)
+
+	return output
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+)
+
+func TestRegistry(t *testing.T) {
+	factory := func(policy telempol.TASPolicyStrategy) Interface {
+		return &MockStrategy{StrategyTypeMock: "registry-test"}
+	}
+
+	Register("registry-test", factory)
+
+	str, err := New("registry-test", telempol.TASPolicyStrategy{})
+	if err != nil || str.StrategyType() != "registry-test" {
+		t.Errorf("Got %v %v, want registry-test strategy", str, err)
+	}
+
+	if _, err := New("registry-missing", telempol.TASPolicyStrategy{}); !errors.Is(err, ErrUnknownStrategyType) {
+		t.Errorf("Got error %v, want %v", err, ErrUnknownStrategyType)
+	}
+
+	if got := RegisteredTypes(); !reflect.DeepEqual(got, []string{"registry-test"}) {
+		t.Errorf("Got registered types %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Registering a type twice should panic")
+		}
+	}()
+
+	Register("registry-test", factory)
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+)
+
+var (
+	errNoPopulation = errors.New("no node values to derive the relative target from")
+	errStatistic    = errors.New("unknown statistic")
+)
+
+// ResolveTarget returns the rule with the target it compares against for the given node values. Relative rules get
+// the target derived from the values of all the nodes, other rules are returned unchanged.
+func ResolveTarget(rule telempol.TASPolicyRule, nodeMetrics metrics.NodeMetricsInfo) (telempol.TASPolicyRule, error) {
+	if rule.Relative == nil {
+		return rule, nil
+	}
+
+	if len(nodeMetrics) == 0 {
+		return rule, errNoPopulation
+	}
+
+	values := make([]float64, 0, len(nodeMetrics))
+	for _, nodeMetric := range nodeMetrics {
+		values = append(values, nodeMetric.Value.AsApproximateFloat64())
+	}
+
+	sort.Float64s(values)
+
+	var statistic float64
+
+	switch rule.Relative.Statistic {
+	case telempol.Mean:
+		statistic = mean(values)
+	case telempol.Median:
+		statistic = percentile(values, 50)
+	case telempol.Percentile:
+		statistic = percentile(values, float64(rule.Relative.Percentile))
+	default:
+		return rule, fmt.Errorf("%w: %v", errStatistic, rule.Relative.Statistic)
+	}
+
+	target := statistic
+	if rule.Relative.Factor != nil {
+		target *= rule.Relative.Factor.AsApproximateFloat64()
+	}
+
+	if rule.Relative.Deviations != nil {
+		target += rule.Relative.Deviations.AsApproximateFloat64() * standardDeviation(values)
+	}
+
+	quantity, err := metrics.FloatQuantity(target)
+	if err != nil {
+		return rule, fmt.Errorf("cannot derive relative target: %w", err)
+	}
+
+	rule.Target = quantity
+
+	return rule, nil
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+
+	return sum / float64(len(values))
+}
+
+// standardDeviation returns the population standard deviation of the values.
+func standardDeviation(values []float64) float64 {
+	avg := mean(values)
+	sum := 0.0
+
+	for _, value := range values {
+		sum += (value - avg) * (value - avg)
+	}
+
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+// percentile interpolates linearly between the closest ranks of the sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
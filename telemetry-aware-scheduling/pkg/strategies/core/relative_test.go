@@ -0,0 +1,90 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestResolveTarget(t *testing.T) {
+	info := metrics.NodeMetricsInfo{}
+	for i := 1; i <= 10; i++ {
+		info["node "+strconv.Itoa(i)] = metrics.NodeMetric{Value: *resource.NewQuantity(int64(i*10), resource.DecimalSI)}
+	}
+
+	quantity := func(value string) *resource.Quantity {
+		q := resource.MustParse(value)
+
+		return &q
+	}
+
+	tests := []struct {
+		name         string
+		relative     *telemetrypolicy.TASPolicyRuleRelative
+		nodeMetrics  metrics.NodeMetricsInfo
+		want         string
+		wantViolated []string
+		wantErr      bool
+	}{
+		{name: "top 10 percent", relative: &telemetrypolicy.TASPolicyRuleRelative{Statistic: "Percentile", Percentile: 90},
+			nodeMetrics: info, want: "91", wantViolated: []string{"node 10"}},
+		{name: "two standard deviations above mean",
+			relative: &telemetrypolicy.TASPolicyRuleRelative{Statistic: "Mean", Deviations: quantity("2")},
+			nodeMetrics: metrics.NodeMetricsInfo{"node 1": {Value: resource.MustParse("2")}, "node 2": {Value: resource.MustParse("4")},
+				"node 3": {Value: resource.MustParse("4")}, "node 4": {Value: resource.MustParse("4")},
+				"node 5": {Value: resource.MustParse("5")}, "node 6": {Value: resource.MustParse("5")},
+				"node 7": {Value: resource.MustParse("7")}, "node 8": {Value: resource.MustParse("9")}},
+			want: "9", wantViolated: []string{}},
+		{name: "20 percent above median", relative: &telemetrypolicy.TASPolicyRuleRelative{Statistic: "Median", Factor: quantity("1.2")},
+			nodeMetrics: info, want: "66", wantViolated: []string{"node 10", "node 7", "node 8", "node 9"}},
+		{name: "no nodes", relative: &telemetrypolicy.TASPolicyRuleRelative{Statistic: "Mean"}, nodeMetrics: metrics.NodeMetricsInfo{},
+			wantErr: true},
+		{name: "unknown statistic", relative: &telemetrypolicy.TASPolicyRuleRelative{Statistic: "Mode"}, nodeMetrics: info,
+			wantErr: true},
+		{name: "fixed target", nodeMetrics: info, want: "85", wantViolated: []string{"node 9", "node 10"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rule := telemetrypolicy.TASPolicyRule{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("85"),
+				Relative: tt.relative}
+
+			got, err := ResolveTarget(rule, tt.nodeMetrics)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got.Target.Cmp(resource.MustParse(tt.want)) != 0 {
+				t.Errorf("ResolveTarget() target = %v, want %v", got.Target.String(), tt.want)
+			}
+
+			violated := []string{}
+
+			for node, nodeMetric := range tt.nodeMetrics {
+				if EvaluateRule(nodeMetric.Value, got) {
+					violated = append(violated, node)
+				}
+			}
+
+			sort.Strings(violated)
+			sort.Strings(tt.wantViolated)
+
+			if !reflect.DeepEqual(violated, tt.wantViolated) {
+				t.Errorf("EvaluateRule() violated on %v, want %v", violated, tt.wantViolated)
+			}
+		})
+	}
+}
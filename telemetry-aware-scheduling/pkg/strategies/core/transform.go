@@ -0,0 +1,72 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var (
+	errMetricInput = errors.New("metric must set exactly one of source and expression")
+	errBoolMetric  = errors.New("metric expression must evaluate to a number")
+)
+
+// NewTransform returns the transform computing a metric defined by a policy. Without scale the values are kept as
+// they are, without min or max they aren't clamped.
+func NewTransform(metric telempol.TASPolicyMetric) (metrics.Transform, error) {
+	if (metric.Source == "") == (metric.Expression == "") {
+		return metrics.Transform{}, errMetricInput
+	}
+
+	transform := metrics.Transform{Source: metric.Source, Expression: metric.Expression, Scale: 1}
+
+	if metric.Expression != "" {
+		expr, err := CompileExpression(metric.Expression)
+		if err != nil {
+			return metrics.Transform{}, err
+		}
+
+		if expr.Bool {
+			return metrics.Transform{}, errBoolMetric
+		}
+
+		transform.Inputs = expr.Metrics
+		transform.Derive = func(values map[string]float64) (float64, error) {
+			value, err := expr.Evaluate(values)
+			if err != nil {
+				return 0, fmt.Errorf("metric %v: %w", metric.Name, err)
+			}
+
+			return value.AsApproximateFloat64(), nil
+		}
+	}
+
+	if metric.Scale != nil {
+		transform.Scale = metric.Scale.AsApproximateFloat64()
+	}
+
+	if metric.Offset != nil {
+		transform.Offset = metric.Offset.AsApproximateFloat64()
+	}
+
+	transform.Min = approximateFloat(metric.Min)
+	transform.Max = approximateFloat(metric.Max)
+
+	return transform, nil
+}
+
+func approximateFloat(quantity *resource.Quantity) *float64 {
+	if quantity == nil {
+		return nil
+	}
+
+	value := quantity.AsApproximateFloat64()
+
+	return &value
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestNewTransform(t *testing.T) {
+	milli, two := resource.MustParse("1m"), resource.MustParse("2")
+
+	tests := []struct {
+		name       string
+		metric     telempol.TASPolicyMetric
+		wantInputs []string
+		wantValue  float64
+		wantErr    bool
+	}{
+		{"source", telempol.TASPolicyMetric{Name: "power_watts", Source: "power_milliwatts", Scale: &milli}, nil, 0, false},
+		{"expression", telempol.TASPolicyMetric{Name: "power_ratio", Expression: "metrics.power / metrics.budget", Max: &two},
+			[]string{"budget", "power"}, 0.5, false},
+		{"bool expression", telempol.TASPolicyMetric{Name: "hot", Expression: "metrics.temperature > 80"}, nil, 0, true},
+		{"source and expression", telempol.TASPolicyMetric{Name: "power_watts", Source: "power", Expression: "metrics.power"},
+			nil, 0, true},
+		{"no input", telempol.TASPolicyMetric{Name: "power_watts"}, nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTransform(tt.metric)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTransform() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got.Inputs, tt.wantInputs) || got.Source != tt.metric.Source {
+				t.Errorf("NewTransform() reads %v %v, want %v %v", got.Source, got.Inputs, tt.metric.Source, tt.wantInputs)
+			}
+
+			if tt.metric.Scale != nil && got.Scale != tt.metric.Scale.AsApproximateFloat64() {
+				t.Errorf("NewTransform() scale = %v, want %v", got.Scale, tt.metric.Scale)
+			}
+
+			if tt.metric.Max != nil && (got.Max == nil || *got.Max != tt.metric.Max.AsApproximateFloat64()) {
+				t.Errorf("NewTransform() max = %v, want %v", got.Max, tt.metric.Max)
+			}
+
+			if got.Derived() {
+				value, err := got.Derive(map[string]float64{"power": 150, "budget": 300})
+				if err != nil || value != tt.wantValue {
+					t.Errorf("Derive() = %v, %v, want %v", value, err, tt.wantValue)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// defaultSmoothing is the level and trend smoothing factor of the ExponentialSmoothing model when none is set.
+const defaultSmoothing = 0.5
+
+var (
+	errTooFewSamples = errors.New("at least two samples at different times are needed to predict a trend")
+	errTrendModel    = errors.New("unknown trend model")
+)
+
+// Predict extrapolates the samples of a node, oldest first, to the value expected Horizon after the latest sample.
+func Predict(samples []metrics.NodeMetric, trend telempol.TASPolicyRuleTrend) (resource.Quantity, error) {
+	if len(samples) < 2 || !samples[len(samples)-1].Timestamp.After(samples[0].Timestamp) {
+		return resource.Quantity{}, errTooFewSamples
+	}
+
+	var level, slope float64
+
+	switch trend.Model {
+	case telempol.Linear:
+		level, slope = linearFit(samples)
+	case telempol.ExponentialSmoothing:
+		level, slope = holtFit(samples, smoothing(trend.Alpha), smoothing(trend.Beta))
+	default:
+		return resource.Quantity{}, fmt.Errorf("%w: %v", errTrendModel, trend.Model)
+	}
+
+	quantity, err := metrics.FloatQuantity(level + slope*trend.Horizon.Seconds())
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("cannot predict trend: %w", err)
+	}
+
+	return quantity, nil
+}
+
+// linearFit fits a line to the samples by least squares. It returns the fitted value at the latest sample and the
+// slope per second.
+func linearFit(samples []metrics.NodeMetric) (float64, float64) {
+	latest := samples[len(samples)-1].Timestamp
+
+	var sumX, sumY, sumXY, sumXX float64
+
+	for _, sample := range samples {
+		x := sample.Timestamp.Sub(latest).Seconds()
+		y := sample.Value.AsApproximateFloat64()
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	count := float64(len(samples))
+	slope := (count*sumXY - sumX*sumY) / (count*sumXX - sumX*sumX)
+
+	return (sumY - slope*sumX) / count, slope
+}
+
+// holtFit follows the level and the trend per second of the samples with Holt's linear exponential smoothing, scaling
+// the trend by the time between samples so irregular sampling is handled. It returns the level and trend after the
+// latest sample.
+func holtFit(samples []metrics.NodeMetric, alpha, beta float64) (float64, float64) {
+	level := samples[0].Value.AsApproximateFloat64()
+	slope := 0.0
+	previous := samples[0].Timestamp
+	started := false
+
+	for _, sample := range samples[1:] {
+		elapsed := sample.Timestamp.Sub(previous).Seconds()
+		if elapsed <= 0 {
+			continue
+		}
+
+		value := sample.Value.AsApproximateFloat64()
+		if !started {
+			slope = (value - level) / elapsed
+			started = true
+		}
+
+		lastLevel := level
+		level = alpha*value + (1-alpha)*(level+slope*elapsed)
+		slope = beta*(level-lastLevel)/elapsed + (1-beta)*slope
+		previous = sample.Timestamp
+	}
+
+	return level, slope
+}
+
+func smoothing(factor *resource.Quantity) float64 {
+	if factor == nil {
+		return defaultSmoothing
+	}
+
+	return factor.AsApproximateFloat64()
+}
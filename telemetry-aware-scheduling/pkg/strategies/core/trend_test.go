@@ -0,0 +1,110 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var trendStart = time.Unix(1700000000, 0)
+
+func samples(step time.Duration, values ...string) []metrics.NodeMetric {
+	output := []metrics.NodeMetric{}
+	for i, value := range values {
+		output = append(output, metrics.NodeMetric{Timestamp: trendStart.Add(time.Duration(i) * step), Window: step,
+			Value: resource.MustParse(value)})
+	}
+
+	return output
+}
+
+func TestPredict(t *testing.T) {
+	alpha := resource.MustParse("1")
+
+	tests := []struct {
+		name    string
+		samples []metrics.NodeMetric
+		trend   telemetrypolicy.TASPolicyRuleTrend
+		want    string
+		wantErr bool
+	}{
+		{name: "linear rise", samples: samples(time.Minute, "50", "55", "60", "65"),
+			trend: telemetrypolicy.TASPolicyRuleTrend{Model: "Linear", Horizon: metav1.Duration{Duration: 2 * time.Minute}}, want: "75"},
+		{name: "linear fit of noisy samples", samples: samples(time.Minute, "50", "56", "58", "66"),
+			trend: telemetrypolicy.TASPolicyRuleTrend{Model: "Linear", Horizon: metav1.Duration{Duration: time.Minute}}, want: "70"},
+		{name: "exponential smoothing of steady rise", samples: samples(30*time.Second, "50", "55", "60", "65"),
+			trend: telemetrypolicy.TASPolicyRuleTrend{Model: "ExponentialSmoothing", Horizon: metav1.Duration{Duration: time.Minute}},
+			want:  "75"},
+		{name: "exponential smoothing following the latest sample", samples: samples(time.Minute, "50", "60", "40"),
+			trend: telemetrypolicy.TASPolicyRuleTrend{Model: "ExponentialSmoothing", Horizon: metav1.Duration{Duration: time.Minute},
+				Alpha: &alpha, Beta: &alpha}, want: "20"},
+		{name: "single sample", samples: samples(time.Minute, "50"),
+			trend: telemetrypolicy.TASPolicyRuleTrend{Model: "Linear", Horizon: metav1.Duration{Duration: time.Minute}}, wantErr: true},
+		{name: "unknown model", samples: samples(time.Minute, "50", "60"),
+			trend: telemetrypolicy.TASPolicyRuleTrend{Model: "Quadratic", Horizon: metav1.Duration{Duration: time.Minute}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Predict(tt.samples, tt.trend)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Predict() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && got.Cmp(resource.MustParse(tt.want)) != 0 {
+				t.Errorf("Predict() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateTrend(t *testing.T) {
+	mockCache := cache.NewAutoUpdatingCache()
+
+	go mockCache.PeriodicUpdate(*time.NewTicker(time.Second), metrics.NewDummyMetricsClient(map[string]metrics.NodeMetricsInfo{}),
+		map[string]interface{}{})
+
+	if err := mockCache.WriteHistory("temperature"); err != nil {
+		t.Fatalf("Cannot add history reference to cache: %v", err)
+	}
+
+	history := map[string][]metrics.NodeMetric{
+		"heating": samples(time.Minute, "70", "74", "78"),
+		"cooling": samples(time.Minute, "85", "80", "75"),
+		"steady":  samples(time.Minute, "70", "70", "70"),
+		"hot":     samples(time.Minute, "90", "90", "90"),
+	}
+
+	for i := 0; i < 3; i++ {
+		info := metrics.NodeMetricsInfo{}
+		for node, nodeSamples := range history {
+			info[node] = nodeSamples[i]
+		}
+
+		if err := mockCache.WriteMetric("temperature", info); err != nil {
+			t.Fatalf("Cannot write metric to cache: %v", err)
+		}
+	}
+
+	rule := telemetrypolicy.TASPolicyRule{Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("80"),
+		Trend: &telemetrypolicy.TASPolicyRuleTrend{Model: "Linear", Horizon: metav1.Duration{Duration: 2 * time.Minute}}}
+
+	got := map[string]string{}
+	for node, results := range Evaluate(telemetrypolicy.TASPolicyStrategy{Rules: []telemetrypolicy.TASPolicyRule{rule}}, mockCache) {
+		got[node] = results[0].Value.String()
+	}
+
+	if want := map[string]string{"heating": "86", "hot": "90"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Evaluate() = %v, want %v", got, want)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // Interface describes expected behavior of a specific strategy.
@@ -26,6 +27,12 @@ type Enforceable interface {
 	Cleanup(enforcer *MetricEnforcer, policyName string) error
 }
 
+// Validator is implemented by strategies with constraints beyond the ones shared by all strategies.
+// It's used to reject invalid policies at admission. The path points to the strategy in the policy spec.
+type Validator interface {
+	Validate(path *field.Path) field.ErrorList
+}
+
 // Enforcer registers strategies by type, adds specific strategies to a registry, and Enforces those strategies.
 type Enforcer interface {
 	RegisterStrategyType(strategy Interface)
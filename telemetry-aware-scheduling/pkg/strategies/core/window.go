@@ -0,0 +1,173 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+)
+
+// WindowTimeFormat is the HH:MM format of the start and end of active windows.
+const WindowTimeFormat = "15:04"
+
+// windowDays is the number of days around a time searched for window occurrences. The day before covers windows
+// spanning midnight, the week after is enough to find the next transition of any window.
+const windowDays = 8
+
+var (
+	locations   = map[string]*time.Location{}
+	locationsMu sync.Mutex
+)
+
+// Windowed is implemented by strategies which only apply in their active windows.
+type Windowed interface {
+	GetActiveWindows() []telempol.TASPolicyActiveWindow
+}
+
+// Now returns the current time. It's a variable so tests can set the clock used for active windows.
+var Now = time.Now
+
+// IsActive checks if the strategy applies at the given time. Strategies without active windows always apply.
+func IsActive(str Interface, now time.Time) bool {
+	windowed, ok := str.(Windowed)
+	if !ok {
+		return true
+	}
+
+	return InWindows(windowed.GetActiveWindows(), now)
+}
+
+// InWindows checks if the time is in one of the windows. It's always true without windows.
+// Windows which can't be parsed are ignored.
+func InWindows(windows []telempol.TASPolicyActiveWindow, now time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+
+	for _, window := range windows {
+		for _, occurrence := range windowOccurrences(window, now) {
+			if !now.Before(occurrence[0]) && now.Before(occurrence[1]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// NextTransition returns the first time after now at which a window starts or ends, which is when InWindows may
+// change. It returns the zero time without windows.
+func NextTransition(windows []telempol.TASPolicyActiveWindow, now time.Time) time.Time {
+	next := time.Time{}
+
+	for _, window := range windows {
+		for _, occurrence := range windowOccurrences(window, now) {
+			for _, transition := range occurrence {
+				if transition.After(now) && (next.IsZero() || transition.Before(next)) {
+					next = transition
+				}
+			}
+		}
+	}
+
+	return next
+}
+
+// LoadLocation returns the location of an IANA time zone name. Locations are cached by name, so loading them when a
+// policy is validated or ingested spares later evaluations from reading the time zone database.
+func LoadLocation(name string) (*time.Location, error) {
+	locationsMu.Lock()
+	defer locationsMu.Unlock()
+
+	if location, ok := locations[name]; ok {
+		return location, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+
+	locations[name] = location
+
+	return location, nil
+}
+
+// ParseWindow returns the location of the window together with its start and end as offsets from midnight.
+func ParseWindow(window telempol.TASPolicyActiveWindow) (*time.Location, time.Duration, time.Duration, error) {
+	location, err := LoadLocation(window.TimeZone)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	start, err := clockOffset(window.Start)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	end, err := clockOffset(window.End)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return location, start, end, nil
+}
+
+// clockOffset returns the offset from midnight of an HH:MM time.
+func clockOffset(clock string) (time.Duration, error) {
+	parsed, err := time.Parse(WindowTimeFormat, clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: %w", clock, err)
+	}
+
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// windowOccurrences returns the start and end of each occurrence of the window starting from the day before the
+// given time up to a week after it. Boundaries are wall clock times of their day, so days with a daylight saving
+// change keep them at the clock times of the window.
+func windowOccurrences(window telempol.TASPolicyActiveWindow, now time.Time) [][2]time.Time {
+	location, start, end, err := ParseWindow(window)
+	if err != nil {
+		return nil
+	}
+
+	local := now.In(location)
+	occurrences := make([][2]time.Time, 0, windowDays)
+
+	for day := -1; day < windowDays-1; day++ {
+		date := time.Date(local.Year(), local.Month(), local.Day()+day, 0, 0, 0, 0, location)
+		if len(window.Days) > 0 && !containsDay(window.Days, date.Weekday()) {
+			continue
+		}
+
+		endDay := 0
+		if end <= start {
+			endDay = 1
+		}
+
+		occurrences = append(occurrences, [2]time.Time{atClock(date, 0, start), atClock(date, endDay, end)})
+	}
+
+	return occurrences
+}
+
+// atClock returns the time at the offset from midnight on the given number of days after the date, in its location.
+func atClock(date time.Time, days int, offset time.Duration) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day()+days, int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0,
+		date.Location())
+}
+
+func containsDay(days []string, weekday time.Weekday) bool {
+	for _, day := range days {
+		if day == weekday.String() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+)
+
+var (
+	nightWindow   = telemetrypolicy.TASPolicyActiveWindow{Start: "22:00", End: "06:00"}
+	weekendWindow = telemetrypolicy.TASPolicyActiveWindow{Days: []string{"Saturday", "Sunday"}, Start: "09:00", End: "17:00"}
+	sundayNight   = telemetrypolicy.TASPolicyActiveWindow{Days: []string{"Sunday"}, Start: "22:00", End: "02:00"}
+	newYorkWindow = telemetrypolicy.TASPolicyActiveWindow{Start: "09:00", End: "17:00", TimeZone: "America/New_York"}
+)
+
+// at returns the given time in January 2024 in UTC. The 6th is a Saturday and the 8th a Monday.
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, time.January, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestInWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		windows []telemetrypolicy.TASPolicyActiveWindow
+		now     time.Time
+		want    bool
+	}{
+		{"no windows", nil, at(8, 12, 0), true},
+		{"night window before midnight", []telemetrypolicy.TASPolicyActiveWindow{nightWindow}, at(8, 23, 0), true},
+		{"night window after midnight", []telemetrypolicy.TASPolicyActiveWindow{nightWindow}, at(8, 5, 59), true},
+		{"night window end", []telemetrypolicy.TASPolicyActiveWindow{nightWindow}, at(8, 6, 0), false},
+		{"weekend window on saturday", []telemetrypolicy.TASPolicyActiveWindow{weekendWindow}, at(6, 10, 0), true},
+		{"weekend window on monday", []telemetrypolicy.TASPolicyActiveWindow{weekendWindow}, at(8, 10, 0), false},
+		{"window spanning midnight belongs to its start day", []telemetrypolicy.TASPolicyActiveWindow{sundayNight}, at(8, 1, 0), true},
+		{"window spanning midnight on another day", []telemetrypolicy.TASPolicyActiveWindow{sundayNight}, at(8, 23, 0), false},
+		{"time zone inside window", []telemetrypolicy.TASPolicyActiveWindow{newYorkWindow}, at(8, 15, 0), true},
+		{"time zone outside window", []telemetrypolicy.TASPolicyActiveWindow{newYorkWindow}, at(8, 13, 0), false},
+		{"any window", []telemetrypolicy.TASPolicyActiveWindow{weekendWindow, nightWindow}, at(8, 23, 0), true},
+		{"invalid window", []telemetrypolicy.TASPolicyActiveWindow{{Start: "9am", End: "17:00"}}, at(8, 12, 0), false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InWindows(tt.windows, tt.now); got != tt.want {
+				t.Errorf("InWindows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		windows []telemetrypolicy.TASPolicyActiveWindow
+		now     time.Time
+		want    time.Time
+	}{
+		{"no windows", nil, at(8, 12, 0), time.Time{}},
+		{"window start", []telemetrypolicy.TASPolicyActiveWindow{nightWindow}, at(8, 12, 0), at(8, 22, 0)},
+		{"window end", []telemetrypolicy.TASPolicyActiveWindow{nightWindow}, at(8, 23, 0), at(9, 6, 0)},
+		{"next week", []telemetrypolicy.TASPolicyActiveWindow{weekendWindow}, at(8, 12, 0), at(13, 9, 0)},
+		{"earliest window", []telemetrypolicy.TASPolicyActiveWindow{weekendWindow, newYorkWindow}, at(8, 12, 0), at(8, 14, 0)},
+		{"daylight saving start", []telemetrypolicy.TASPolicyActiveWindow{newYorkWindow},
+			time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC), time.Date(2024, time.March, 10, 13, 0, 0, 0, time.UTC)},
+		{"daylight saving end", []telemetrypolicy.TASPolicyActiveWindow{newYorkWindow},
+			time.Date(2024, time.November, 3, 12, 0, 0, 0, time.UTC), time.Date(2024, time.November, 3, 14, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextTransition(tt.windows, tt.now); !got.Equal(tt.want) {
+				t.Errorf("NextTransition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// windowedStrategy counts the times it's enforced and cleaned up.
+type windowedStrategy struct {
+	MockStrategy
+	windows  []telemetrypolicy.TASPolicyActiveWindow
+	enforced int
+	cleaned  int
+}
+
+func (w *windowedStrategy) Enforce(*MetricEnforcer, cache.Reader) (int, error) {
+	w.enforced++
+
+	return 0, nil
+}
+
+func (w *windowedStrategy) Cleanup(*MetricEnforcer, string) error {
+	w.cleaned++
+
+	return nil
+}
+
+func (w *windowedStrategy) GetActiveWindows() []telemetrypolicy.TASPolicyActiveWindow {
+	return w.windows
+}
+
+func TestMetricEnforcer_enforceStrategyWindows(t *testing.T) {
+	defer func() { Now = time.Now }()
+
+	str := &windowedStrategy{MockStrategy: MockStrategy{StrategyTypeMock: "windowed"},
+		windows: []telemetrypolicy.TASPolicyActiveWindow{nightWindow}}
+	enforcer := NewEnforcer(nil)
+	enforcer.RegisterStrategyType(str)
+	enforcer.AddStrategy(str, str.StrategyType())
+
+	steps := []struct {
+		now          time.Time
+		wantEnforced int
+		wantCleaned  int
+	}{
+		{at(8, 23, 0), 1, 0},
+		{at(9, 5, 0), 2, 0},
+		{at(9, 7, 0), 2, 1},
+		{at(9, 8, 0), 2, 1},
+		{at(9, 22, 0), 3, 1},
+		{at(10, 6, 0), 3, 2},
+	}
+
+	for _, step := range steps {
+		Now = func() time.Time { return step.now }
+
+		enforcer.enforceStrategy(str.StrategyType(), cache.MockEmptySelfUpdatingCache())
+
+		if str.enforced != step.wantEnforced || str.cleaned != step.wantCleaned {
+			t.Errorf("At %v got %d enforcements and %d cleanups, want %d and %d", step.now, str.enforced, str.cleaned,
+				step.wantEnforced, step.wantCleaned)
+		}
+	}
+}
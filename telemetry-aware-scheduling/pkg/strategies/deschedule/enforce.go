@@ -209,10 +209,16 @@ func (d *Strategy) updateNodeLabels(enforcer *strategy.MetricEnforcer, viols vio
 }
 
 // nodeStatusForStrategy returns a list of nodes that are violating the given strategy by calling the strategies Violated method.
+// Strategies outside their active windows are skipped, so the labels their cleanup removed aren't added back.
 func (d *Strategy) nodeStatusForStrategy(enforcer *strategy.MetricEnforcer, cache cache.Reader) violationList {
 	violations := violationList{}
+	now := strategy.Now()
 
 	for strg := range enforcer.RegisteredStrategies[StrategyType] {
+		if !strategy.IsActive(strg, now) {
+			continue
+		}
+
 		klog.V(l2).InfoS("Evaluating "+strg.GetPolicyName(), "component", "controller")
 		nodes := strg.Violated(cache)
 
@@ -18,7 +18,7 @@ import (
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
 	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
-	telpol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telpol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -101,8 +101,8 @@ func TestDescheduleStrategy_Enforce(t *testing.T) {
 	}{
 		{name: "node label test",
 			d: &Strategy{PolicyName: "deschedule-test", Rules: []telpol.TASPolicyRule{
-				{Metricname: "memory", Operator: "GreaterThan", Target: 1},
-				{Metricname: "cpu", Operator: "LessThan", Target: 10}}},
+				{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("1")},
+				{Metricname: "cpu", Operator: "LessThan", Target: resource.MustParse("10")}}},
 			nodes: []*v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"deschedule-test": "", "node-1-label": "test"}}},
 				{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"deschedule-test": "violating", "node-2-label": "test"}}},
 				{ObjectMeta: metav1.ObjectMeta{Name: "node-3", Labels: map[string]string{"node-3-label": "test"}}}},
@@ -118,8 +118,8 @@ func TestDescheduleStrategy_Enforce(t *testing.T) {
 			}},
 		{name: "node unlabel test",
 			d: &Strategy{PolicyName: "deschedule-test", Rules: []telpol.TASPolicyRule{
-				{Metricname: "memory", Operator: "GreaterThan", Target: 1000},
-				{Metricname: "cpu", Operator: "LessThan", Target: 10}}},
+				{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("1000")},
+				{Metricname: "cpu", Operator: "LessThan", Target: resource.MustParse("10")}}},
 			nodes: []*v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"deschedule-test": "violating", "node-1-label": "test"}}},
 				{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"deschedule-test": "violating", "node-2-label": "test"}}},
 				{ObjectMeta: metav1.ObjectMeta{Name: "node-3", Labels: map[string]string{"node-3-label": "test"}}}},
@@ -133,8 +133,8 @@ func TestDescheduleStrategy_Enforce(t *testing.T) {
 				labeledNodes: map[string]map[string]string{}}},
 		{name: "list nodes with exception",
 			d: &Strategy{PolicyName: "deschedule-test", Rules: []telpol.TASPolicyRule{
-				{Metricname: "memory", Operator: "GreaterThan", Target: 1000},
-				{Metricname: "cpu", Operator: "LessThan", Target: 10}}},
+				{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("1000")},
+				{Metricname: "cpu", Operator: "LessThan", Target: resource.MustParse("10")}}},
 			nodes: []*v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"deschedule-test": "violating", "node-1-label": "test"}}},
 				{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"deschedule-test": "violating", "node-2-label": "test"}}},
 				{ObjectMeta: metav1.ObjectMeta{Name: "node-3", Labels: map[string]string{"node-3-label": "test"}}}},
@@ -146,8 +146,8 @@ func TestDescheduleStrategy_Enforce(t *testing.T) {
 			wantErrMessageToken: failNodeListEnforceMessage},
 		{name: "list nodes with patch exception",
 			d: &Strategy{PolicyName: "deschedule-test", Rules: []telpol.TASPolicyRule{
-				{Metricname: "memory", Operator: "GreaterThan", Target: 1000},
-				{Metricname: "cpu", Operator: "LessThan", Target: 10}}},
+				{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("1000")},
+				{Metricname: "cpu", Operator: "LessThan", Target: resource.MustParse("10")}}},
 			nodes:        []*v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"deschedule-test": "violating", "node-1-label": "test"}}}},
 			cacheMetrics: map[string]CacheMetric{"node-1": {"cpu", 40}},
 			args: args{enforcer: strategy.NewEnforcer(getClientWithPatchException()),
@@ -205,6 +205,41 @@ func TestDescheduleStrategy_Enforce(t *testing.T) {
 	}
 }
 
+func TestDescheduleStrategy_EnforceInactive(t *testing.T) {
+	defer func() { strategy.Now = time.Now }()
+
+	strategy.Now = func() time.Time { return time.Date(2024, time.January, 8, 12, 0, 0, 0, time.UTC) }
+
+	rules := []telpol.TASPolicyRule{{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("1")}}
+	active := &Strategy{PolicyName: "day", Rules: rules}
+	inactive := &Strategy{PolicyName: "night", Rules: rules,
+		ActiveWindows: []telpol.TASPolicyActiveWindow{{Start: "22:00", End: "06:00"}}}
+
+	enforcer := strategy.NewEnforcer(testclient.NewSimpleClientset())
+	enforcer.RegisterStrategyType(active)
+	enforcer.AddStrategy(active, StrategyType)
+	enforcer.AddStrategy(inactive, StrategyType)
+
+	mockCache := cache.MockEmptySelfUpdatingCache()
+	if err := mockCache.WriteMetric("memory", metrics.NodeMetricsInfo{
+		"node-1": {Timestamp: time.Now(), Window: 1, Value: *resource.NewQuantity(100, resource.DecimalSI)}}); err != nil {
+		t.Fatalf("Cannot write metric to mock cache for test: %v", err)
+	}
+
+	if _, err := enforcer.KubeClient.CoreV1().Nodes().Create(context.TODO(),
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"node-1-label": "test"}}},
+		metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Cannot create node: %v", err)
+	}
+
+	if _, err := active.Enforce(enforcer, mockCache); err != nil {
+		t.Fatalf("Unexpected error from Enforce: %v", err)
+	}
+
+	nodes, _ := enforcer.KubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	assertViolatingNodes(t, nodes, map[string]map[string]string{"node-1": {"day": "violating", "node-1-label": "test"}})
+}
+
 func TestDescheduleStrategy_Cleanup(t *testing.T) {
 	type args struct {
 		enforcer *strategy.MetricEnforcer
@@ -222,8 +257,8 @@ func TestDescheduleStrategy_Cleanup(t *testing.T) {
 	}{
 		{name: "node with violating label",
 			d: &Strategy{PolicyName: "deschedule-test", Rules: []telpol.TASPolicyRule{
-				{Metricname: "memory", Operator: "GreaterThan", Target: 1},
-				{Metricname: "cpu", Operator: "LessThan", Target: 10}}},
+				{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("1")},
+				{Metricname: "cpu", Operator: "LessThan", Target: resource.MustParse("10")}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"deschedule-test": "violating", "node-1-label": "test"}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()),
 				cache: cache.MockEmptySelfUpdatingCache()},
@@ -233,8 +268,8 @@ func TestDescheduleStrategy_Cleanup(t *testing.T) {
 			}},
 		{name: "node without violating label",
 			d: &Strategy{PolicyName: "deschedule-test", Rules: []telpol.TASPolicyRule{
-				{Metricname: "memory", Operator: "GreaterThan", Target: 1000},
-				{Metricname: "cpu", Operator: "LessThan", Target: 10}}},
+				{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("1000")},
+				{Metricname: "cpu", Operator: "LessThan", Target: resource.MustParse("10")}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"deschedule-test": "", "node-2-label": "test"}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()),
 				cache: cache.MockEmptySelfUpdatingCache()},
@@ -244,8 +279,8 @@ func TestDescheduleStrategy_Cleanup(t *testing.T) {
 			}},
 		{name: "list nodes throws an error",
 			d: &Strategy{PolicyName: "deschedule-test", Rules: []telpol.TASPolicyRule{
-				{Metricname: "memory", Operator: "GreaterThan", Target: 1000},
-				{Metricname: "cpu", Operator: "LessThan", Target: 10}}},
+				{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("1000")},
+				{Metricname: "cpu", Operator: "LessThan", Target: resource.MustParse("10")}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"deschedule-test": "", "test": "label"}}},
 			args: args{enforcer: strategy.NewEnforcer(getClientWithListException()),
 				cache: cache.MockEmptySelfUpdatingCache()},
@@ -254,8 +289,8 @@ func TestDescheduleStrategy_Cleanup(t *testing.T) {
 			want:                expected{}},
 		{name: "patch nodes throws an error",
 			d: &Strategy{PolicyName: "deschedule-test", Rules: []telpol.TASPolicyRule{
-				{Metricname: "memory", Operator: "GreaterThan", Target: 1000},
-				{Metricname: "cpu", Operator: "LessThan", Target: 10}}},
+				{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("1000")},
+				{Metricname: "cpu", Operator: "LessThan", Target: resource.MustParse("10")}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"deschedule-test": "violating", "test": "label"}}},
 			args: args{enforcer: strategy.NewEnforcer(getClientWithPatchException()),
 				cache: cache.MockEmptySelfUpdatingCache()},
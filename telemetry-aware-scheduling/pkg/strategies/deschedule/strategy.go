@@ -8,12 +8,14 @@ package deschedule
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"k8s.io/klog/v2"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
-	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 )
 
 // StrategyType is set to de-schedule.
@@ -24,6 +26,14 @@ const (
 // Strategy type for de-scheduling from a single policy.
 type Strategy telempol.TASPolicyStrategy
 
+func init() {
+	core.Register(StrategyType, func(policy telempol.TASPolicyStrategy) core.Interface {
+		str := Strategy(policy)
+
+		return &str
+	})
+}
+
 // StrategyType returns the name of the strategy type. This is used to place it in the registry.
 func (d *Strategy) StrategyType() string {
 	return StrategyType
@@ -33,40 +43,16 @@ func (d *Strategy) StrategyType() string {
 // Returns a map of nodeNames as key with an empty value associated with each.
 func (d *Strategy) Violated(cache cache.Reader) map[string]interface{} {
 	violatingNodes := map[string]interface{}{}
-	nodeMetricViol := map[string]int{}
-
-	for _, rule := range d.Rules {
-		nodeMetrics, err := cache.ReadMetric(rule.Metricname)
-
-		if err != nil {
-			klog.V(l2).InfoS(err.Error(), "component", "controller")
 
-			continue
+	for nodeName, results := range core.Evaluate(telempol.TASPolicyStrategy(*d), cache) {
+		rules := make([]string, 0, len(results))
+		for _, result := range results {
+			rules = append(rules, ruleToString(result.Rule))
 		}
 
-		for nodeName, nodeMetric := range nodeMetrics {
-			msg := fmt.Sprint(nodeName+" "+rule.Metricname, " = ", nodeMetric.Value.AsDec())
-			klog.V(l4).InfoS(msg, "component", "controller")
+		klog.V(l2).InfoS(nodeName+" violating "+d.PolicyName+": "+strings.Join(rules, ", "), "component", "controller")
 
-			if core.EvaluateRule(nodeMetric.Value, rule) {
-				klog.V(l2).Infof("%v violated in node %v", rule.Metricname, nodeName)
-				nodeMetricViol[nodeName]++
-
-				if d.LogicalOperator == "allOf" {
-					if nodeMetricViol[nodeName] == len(d.Rules) {
-						msg := nodeName + " violating all the rules in " + d.StrategyType() + " strategy"
-						klog.V(l2).InfoS(msg, "component", "controller")
-
-						violatingNodes[nodeName] = nil
-					}
-				} else {
-					msg := fmt.Sprintf(nodeName + " violating " + d.PolicyName + ": " + ruleToString(rule))
-					klog.V(l2).InfoS(msg, "component", "controller")
-
-					violatingNodes[nodeName] = nil
-				}
-			}
-		}
+		violatingNodes[nodeName] = nil
 	}
 
 	return violatingNodes
@@ -74,7 +60,7 @@ func (d *Strategy) Violated(cache cache.Reader) map[string]interface{} {
 
 // ruleToString returns the rule passed to it as a single string.
 func ruleToString(rule telempol.TASPolicyRule) string {
-	return fmt.Sprintf("%v %v %v", rule.Metricname, rule.Operator, rule.Target)
+	return fmt.Sprintf("%v %v %v", core.RuleName(rule), rule.Operator, core.TargetString(rule))
 }
 
 // Equals checks if a strategy is the same as the passed strategy.
@@ -82,27 +68,10 @@ func ruleToString(rule telempol.TASPolicyRule) string {
 // TODO: Remedial action if equal, i.e. point to other strategies. Make method order ambivalent.
 func (d *Strategy) Equals(other core.Interface) bool {
 	otherDeschedulerStrategy, ok := other.(*Strategy)
-	sameName := other.GetPolicyName() == d.GetPolicyName()
 
-	if ok && sameName && len(d.Rules) > 0 && len(d.Rules) == len(otherDeschedulerStrategy.Rules) {
-		for i, rule := range d.Rules {
-			if rule.Metricname != otherDeschedulerStrategy.Rules[i].Metricname {
-				return false
-			}
-
-			if rule.Target != otherDeschedulerStrategy.Rules[i].Target {
-				return false
-			}
-
-			if rule.Operator != otherDeschedulerStrategy.Rules[i].Operator {
-				return false
-			}
-		}
-
-		return true
-	}
-
-	return false
+	return ok && other.GetPolicyName() == d.GetPolicyName() && len(d.Rules) > 0 &&
+		core.EqualRules(telempol.TASPolicyStrategy(*d), telempol.TASPolicyStrategy(*otherDeschedulerStrategy)) &&
+		reflect.DeepEqual(d.ActiveWindows, otherDeschedulerStrategy.ActiveWindows)
 }
 
 // GetPolicyName returns the name of the policy that originated strategy.
@@ -110,6 +79,11 @@ func (d *Strategy) GetPolicyName() string {
 	return d.PolicyName
 }
 
+// GetActiveWindows returns the time windows in which the strategy applies.
+func (d *Strategy) GetActiveWindows() []telempol.TASPolicyActiveWindow {
+	return d.ActiveWindows
+}
+
 // SetPolicyName adds a policy name to be associated with this strategy.
 func (d *Strategy) SetPolicyName(name string) {
 	d.PolicyName = name
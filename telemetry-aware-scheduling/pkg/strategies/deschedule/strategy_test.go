@@ -17,7 +17,7 @@ import (
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
-	v1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	v1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
@@ -110,6 +110,10 @@ func TestDescheduleStrategy_Equals(t *testing.T) {
 			d: strategyRuleDefault("test name", "memory", "GreaterThan", 10),
 			args: args{
 				other: strategyRuleDefault("test name", "memory", "GreaterThan", 50)}},
+		{name: "Not equal different logical operator",
+			d: strategyRule("test name", "allOf", "memory", "GreaterThan", 50),
+			args: args{
+				other: strategyRule("test name", "anyOf", "memory", "GreaterThan", 50)}},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -354,7 +358,7 @@ func strategyRuleDefault(policyname, metricname, operator string, target int64)
 func strategyRule(policyname, logicalOp, metricname, operator string, target int64) *Strategy {
 	return &Strategy{
 		PolicyName:      policyname,
-		LogicalOperator: logicalOp,
+		LogicalOperator: v1.LogicalOperator(logicalOp),
 		Rules: []v1.TASPolicyRule{
 			metricRules(metricname, operator, target)}}
 }
@@ -362,8 +366,8 @@ func strategyRule(policyname, logicalOp, metricname, operator string, target int
 func metricRules(metricname string, operator string, target int64) v1.TASPolicyRule {
 	return v1.TASPolicyRule{
 		Metricname: metricname,
-		Operator:   operator,
-		Target:     target,
+		Operator:   v1.Operator(operator),
+		Target:     *resource.NewQuantity(target, resource.DecimalSI),
 	}
 }
 func TestDescheduleStrategy_StrategyType(t *testing.T) {
@@ -7,17 +7,27 @@ package dontschedule
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"k8s.io/klog/v2"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
-	telemetryPolicyV1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telemetryPolicyV1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 )
 
 // Strategy represents the TAS policy strategies.
 type Strategy telemetryPolicyV1.TASPolicyStrategy
 
+func init() {
+	core.Register(StrategyType, func(policy telemetryPolicyV1.TASPolicyStrategy) core.Interface {
+		str := Strategy(policy)
+
+		return &str
+	})
+}
+
 // StrategyType is set to not schedule.
 const (
 	StrategyType = "dontschedule"
@@ -25,41 +35,20 @@ const (
 )
 
 // Violated compares the list of rules against the metric values pulled from the cache.
-// If any single rule is violated the method returns a set of nodes that are currently in violation.
+// The rules are combined by the strategy group or logical operator. The method returns the set of nodes that are
+// currently in violation.
 func (d *Strategy) Violated(cache cache.Reader) map[string]interface{} {
 	violatingNodes := map[string]interface{}{}
-	nodeMetricViol := map[string]int{}
-
-	for _, rule := range d.Rules {
-		nodeMetrics, err := cache.ReadMetric(rule.Metricname)
-		if err != nil {
-			klog.V(l2).InfoS(err.Error(), "component", "controller")
 
-			continue
+	for nodeName, results := range core.Evaluate(telemetryPolicyV1.TASPolicyStrategy(*d), cache) {
+		rules := make([]string, 0, len(results))
+		for _, result := range results {
+			rules = append(rules, ruleToString(result.Rule))
 		}
 
-		for nodeName, nodeMetric := range nodeMetrics {
-			msg := fmt.Sprint(nodeName+" "+rule.Metricname, " = ", nodeMetric.Value.AsDec())
-			klog.V(l2).InfoS(msg, "component", "controller")
+		klog.V(l2).InfoS(nodeName+" violating "+d.PolicyName+": "+strings.Join(rules, ", "), "component", "controller")
 
-			if core.EvaluateRule(nodeMetric.Value, rule) {
-				nodeMetricViol[nodeName]++
-
-				if d.LogicalOperator == "allOf" {
-					if nodeMetricViol[nodeName] == len(d.Rules) {
-						msg := nodeName + " violating all the rules in " + d.StrategyType() + " strategy"
-						klog.V(l2).InfoS(msg, "component", "controller")
-
-						violatingNodes[nodeName] = nil
-					}
-				} else {
-					msg := nodeName + " violating " + d.PolicyName + ": " + ruleToString(rule)
-					klog.V(l2).InfoS(msg, "component", "controller")
-
-					violatingNodes[nodeName] = nil
-				}
-			}
-		}
+		violatingNodes[nodeName] = nil
 	}
 
 	return violatingNodes
@@ -79,27 +68,11 @@ func (d *Strategy) StrategyType() string {
 // Used to avoid duplications and to find the correct strategy for deletions in the index.
 func (d *Strategy) Equals(other core.Interface) bool {
 	OtherDontScheduleStrategy, ok := other.(*Strategy)
-	sameName := other.GetPolicyName() == d.GetPolicyName()
 
-	if ok && sameName && len(d.Rules) > 0 && len(d.Rules) == len(OtherDontScheduleStrategy.Rules) {
-		for i, rule := range d.Rules {
-			if rule.Metricname != OtherDontScheduleStrategy.Rules[i].Metricname {
-				return false
-			}
-
-			if rule.Target != OtherDontScheduleStrategy.Rules[i].Target {
-				return false
-			}
-
-			if rule.Operator != OtherDontScheduleStrategy.Rules[i].Operator {
-				return false
-			}
-		}
-
-		return true
-	}
-
-	return false
+	return ok && other.GetPolicyName() == d.GetPolicyName() && len(d.Rules) > 0 &&
+		core.EqualRules(telemetryPolicyV1.TASPolicyStrategy(*d), telemetryPolicyV1.TASPolicyStrategy(*OtherDontScheduleStrategy)) &&
+		reflect.DeepEqual(d.ActiveWindows, OtherDontScheduleStrategy.ActiveWindows) &&
+		d.FailClosed == OtherDontScheduleStrategy.FailClosed
 }
 
 // SetPolicyName sets a connected policy name for this strategy.
@@ -112,7 +85,12 @@ func (d *Strategy) GetPolicyName() string {
 	return d.PolicyName
 }
 
+// GetActiveWindows returns the time windows in which the strategy applies.
+func (d *Strategy) GetActiveWindows() []telemetryPolicyV1.TASPolicyActiveWindow {
+	return d.ActiveWindows
+}
+
 // Formats the rules as an interpretable string.
 func ruleToString(rule telemetryPolicyV1.TASPolicyRule) string {
-	return fmt.Sprintf("%v %v %v", rule.Metricname, rule.Operator, rule.Target)
+	return fmt.Sprintf("%v %v %v", core.RuleName(rule), rule.Operator, core.TargetString(rule))
 }
@@ -0,0 +1,308 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package evict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+const (
+	l2                     = 2
+	l4                     = 4
+	policyLabel            = "telemetry-policy"
+	daemonSetKind          = "DaemonSet"
+	failPodListMessage     = "failed to list pods during enforce"
+	defaultInterval        = 5 * time.Second
+	defaultPerTick         = 1
+	defaultPerNode         = 1
+	defaultClusterFraction = 0.1
+)
+
+var errNull = errors.New("")
+
+// Limits bounds the number of evictions the evict strategy is allowed to trigger.
+type Limits struct {
+	// Interval is the window over which PerTick and PerNode are counted. It is normally the enforcement period.
+	Interval time.Duration
+	// PerTick is the maximum number of pods evicted across the cluster in one Interval.
+	PerTick int
+	// PerNode is the maximum number of pods evicted from a single node in one Interval.
+	PerNode int
+	// MaxClusterFraction is the maximum fraction of all cluster pods allowed to be terminating at once.
+	MaxClusterFraction float64
+}
+
+// DefaultLimits returns the limits used when none are set with SetLimits.
+func DefaultLimits() Limits {
+	return Limits{
+		Interval:           defaultInterval,
+		PerTick:            defaultPerTick,
+		PerNode:            defaultPerNode,
+		MaxClusterFraction: defaultClusterFraction,
+	}
+}
+
+// eviction records a single eviction triggered by the strategy.
+type eviction struct {
+	nodeName string
+	time     time.Time
+}
+
+// rateLimiter keeps the recent eviction history shared by all evict strategies.
+type rateLimiter struct {
+	limits  Limits
+	history []eviction
+	sync.Mutex
+}
+
+var limiter = newRateLimiter(DefaultLimits())
+
+func newRateLimiter(limits Limits) *rateLimiter {
+	return &rateLimiter{limits: limits}
+}
+
+// SetLimits changes the eviction limits shared by all evict strategies.
+func SetLimits(limits Limits) {
+	limiter.Lock()
+	defer limiter.Unlock()
+
+	limiter.limits = limits
+}
+
+// prune drops the evictions which happened before the current interval.
+func (r *rateLimiter) prune(now time.Time) {
+	recent := r.history[:0]
+
+	for _, ev := range r.history {
+		if now.Sub(ev.time) < r.limits.Interval {
+			recent = append(recent, ev)
+		}
+	}
+
+	r.history = recent
+}
+
+// allow returns true if a pod on the given node can be evicted without breaking the per tick and per node limits.
+func (r *rateLimiter) allow(nodeName string, now time.Time) bool {
+	r.prune(now)
+
+	if len(r.history) >= r.limits.PerTick {
+		return false
+	}
+
+	fromNode := 0
+
+	for _, ev := range r.history {
+		if ev.nodeName == nodeName {
+			fromNode++
+		}
+	}
+
+	return fromNode < r.limits.PerNode
+}
+
+// record adds an eviction from the given node to the history.
+func (r *rateLimiter) record(nodeName string, now time.Time) {
+	r.history = append(r.history, eviction{nodeName: nodeName, time: now})
+}
+
+// clusterBudget returns how many more pods can be evicted before the terminating pods go over the cluster fraction.
+// A positive fraction always allows one terminating pod, a fraction of 0 or less disables evictions.
+func (r *rateLimiter) clusterBudget(allPods *v1.PodList) int {
+	if r.limits.MaxClusterFraction <= 0 {
+		return 0
+	}
+
+	terminating := 0
+
+	for _, pod := range allPods.Items {
+		if pod.DeletionTimestamp != nil {
+			terminating++
+		}
+	}
+
+	// The fraction is rounded down but allows at least one terminating pod, so small clusters can still evict.
+	maxTerminating := max(1, int(math.Floor(r.limits.MaxClusterFraction*float64(len(allPods.Items)))))
+
+	return maxTerminating - terminating
+}
+
+// Enforce evicts the pods linked to the policy from the nodes violating the strategy.
+// Pods are linked to the policy with the telemetry-policy label or by the pod selector of the strategy. Evictions go
+// through the Eviction API, so a pod protected by a PodDisruptionBudget is skipped until the budget allows it to be
+// disrupted.
+// The number of evictions is bounded by the limits set with SetLimits.
+func (d *Strategy) Enforce(enforcer *strategy.MetricEnforcer, cache cache.Reader) (int, error) {
+	violatingNodes := d.Violated(cache)
+	if len(violatingNodes) == 0 {
+		return 0, nil
+	}
+
+	allPods, err := enforcer.KubeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		msg := fmt.Sprintf("cannot list pods: %v", err)
+		klog.V(l2).InfoS(msg, "component", "controller")
+
+		return -1, fmt.Errorf("%s: %w", failPodListMessage, err)
+	}
+
+	candidates, err := d.candidatePods(violatingNodes, allPods)
+	if err != nil {
+		klog.V(l2).InfoS(err.Error(), "component", "controller")
+
+		return -1, err
+	}
+
+	return d.evictPods(enforcer, candidates, allPods)
+}
+
+// candidatePods returns the pods linked to the policy running on violating nodes, ordered by node and pod name.
+// Pods are linked by the telemetry-policy label or, if the strategy has one, its pod selector.
+func (d *Strategy) candidatePods(violatingNodes map[string]interface{}, allPods *v1.PodList) ([]v1.Pod, error) {
+	selectors := []labels.Selector{labels.SelectorFromSet(labels.Set{policyLabel: d.PolicyName})}
+
+	if d.PodSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(d.PodSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pod selector: %w", err)
+		}
+
+		selectors = append(selectors, selector)
+	}
+
+	candidates := []v1.Pod{}
+
+	for _, pod := range allPods.Items {
+		if _, ok := violatingNodes[pod.Spec.NodeName]; !ok {
+			continue
+		}
+
+		if !matchesAny(selectors, pod) || pod.DeletionTimestamp != nil || isDaemonSetPod(pod) {
+			continue
+		}
+
+		candidates = append(candidates, pod)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Spec.NodeName != candidates[j].Spec.NodeName {
+			return candidates[i].Spec.NodeName < candidates[j].Spec.NodeName
+		}
+
+		return candidates[i].Namespace+"/"+candidates[i].Name < candidates[j].Namespace+"/"+candidates[j].Name
+	})
+
+	return candidates, nil
+}
+
+// matchesAny returns true if one of the selectors matches the labels of the pod.
+func matchesAny(selectors []labels.Selector, pod v1.Pod) bool {
+	for _, selector := range selectors {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// evictPods sends eviction requests for the candidate pods as long as the limits allow it.
+// It returns the number of pods evicted.
+func (d *Strategy) evictPods(enforcer *strategy.MetricEnforcer, candidates []v1.Pod, allPods *v1.PodList) (int, error) {
+	limiter.Lock()
+	defer limiter.Unlock()
+
+	budget := limiter.clusterBudget(allPods)
+	evicted := 0
+	evictErrs := ""
+
+	for _, pod := range candidates {
+		if evicted >= budget {
+			msg := fmt.Sprintf("eviction of %v/%v for %v blocked, at most %v of the cluster pods can be terminating",
+				pod.Namespace, pod.Name, d.PolicyName, limiter.limits.MaxClusterFraction)
+			klog.V(l2).InfoS(msg, "component", "controller")
+
+			break
+		}
+
+		now := time.Now()
+		if !limiter.allow(pod.Spec.NodeName, now) {
+			klog.V(l4).InfoS("Eviction rate limit reached for node "+pod.Spec.NodeName, "component", "controller")
+
+			continue
+		}
+
+		err := evictPod(enforcer, pod)
+		if apierrors.IsTooManyRequests(err) {
+			msg := fmt.Sprintf("eviction of %v/%v blocked by disruption budget", pod.Namespace, pod.Name)
+			klog.V(l2).InfoS(msg, "component", "controller")
+
+			continue
+		}
+
+		if err != nil {
+			klog.V(l4).InfoS(err.Error(), "component", "controller")
+			evictErrs = evictErrs + pod.Namespace + "/" + pod.Name + "; "
+
+			continue
+		}
+
+		limiter.record(pod.Spec.NodeName, now)
+		evicted++
+
+		msg := fmt.Sprintf("Evicted %v/%v from %v violating %v", pod.Namespace, pod.Name, pod.Spec.NodeName, d.PolicyName)
+		klog.V(l2).InfoS(msg, "component", "controller")
+	}
+
+	if len(evictErrs) > 0 {
+		return evicted, fmt.Errorf("could not evict %v %w", evictErrs, errNull)
+	}
+
+	return evicted, nil
+}
+
+// evictPod sends an eviction request for the given pod to the API server.
+func evictPod(enforcer *strategy.MetricEnforcer, pod v1.Pod) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+	}
+
+	err := enforcer.KubeClient.CoreV1().Pods(pod.Namespace).EvictV1(context.TODO(), eviction)
+	if err != nil {
+		return fmt.Errorf("failed to evict pod %v/%v: %w", pod.Namespace, pod.Name, err)
+	}
+
+	return nil
+}
+
+// isDaemonSetPod returns true if the pod is owned by a DaemonSet. Those pods would be recreated on the same node.
+func isDaemonSetPod(pod v1.Pod) bool {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == daemonSetKind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Cleanup is a no-op for evict. Evicted pods are not restored when the policy is removed.
+func (d *Strategy) Cleanup(_ *strategy.MetricEnforcer, _ string) error {
+	return nil
+}
@@ -0,0 +1,282 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package evict
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	telpol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var errMockTest = errors.New("error when calling list")
+
+func testPod(name, nodeName, policyName string, owner string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{}},
+		Spec:       v1.PodSpec{NodeName: nodeName},
+	}
+	if policyName != "" {
+		pod.Labels[policyLabel] = policyName
+	}
+
+	if owner != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: owner, Name: "owner"}}
+	}
+
+	return pod
+}
+
+func labelledPod(pod *v1.Pod, key, value string) *v1.Pod {
+	pod.Labels[key] = value
+
+	return pod
+}
+
+// evictionRecorder returns a client which records the evicted pods. Pods in the blocked set are refused as if a
+// PodDisruptionBudget didn't allow the disruption.
+func evictionRecorder(evicted *[]string, blocked map[string]bool) *testclient.Clientset {
+	client := testclient.NewSimpleClientset()
+	client.PrependReactor("create", "pods",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			if action.GetSubresource() != "eviction" {
+				return false, nil, nil
+			}
+
+			createAction, ok := action.(k8stesting.CreateAction)
+			if !ok {
+				return false, nil, nil
+			}
+
+			eviction, ok := createAction.GetObject().(*policyv1.Eviction)
+			if !ok {
+				return false, nil, nil
+			}
+
+			if blocked[eviction.Name] {
+				return true, nil, apierrors.NewTooManyRequests("disruption budget", 0)
+			}
+
+			*evicted = append(*evicted, eviction.Name)
+
+			return true, nil, nil
+		})
+
+	return client
+}
+
+func TestEvictStrategy_Enforce(t *testing.T) {
+	rules := []telpol.TASPolicyRule{{Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("90")}}
+
+	tests := []struct {
+		name          string
+		d             *Strategy
+		limits        Limits
+		pods          []*v1.Pod
+		blocked       map[string]bool
+		nodeMetrics   map[string]int64
+		listErr       bool
+		want          []string
+		wantErr       bool
+		wantErrString string
+	}{
+		{name: "evict labelled pod from violating node",
+			d:      &Strategy{PolicyName: "evict-test", Rules: rules},
+			limits: Limits{Interval: time.Minute, PerTick: 10, PerNode: 10, MaxClusterFraction: 1},
+			pods: []*v1.Pod{testPod("pod-1", "node-1", "evict-test", ""),
+				testPod("pod-2", "node-1", "other-policy", ""),
+				testPod("pod-3", "node-2", "evict-test", ""),
+				testPod("pod-4", "node-1", "", "")},
+			nodeMetrics: map[string]int64{"node-1": 100, "node-2": 50},
+			want:        []string{"pod-1"}},
+		{name: "daemonset pods are not evicted",
+			d:      &Strategy{PolicyName: "evict-test", Rules: rules},
+			limits: Limits{Interval: time.Minute, PerTick: 10, PerNode: 10, MaxClusterFraction: 1},
+			pods: []*v1.Pod{testPod("pod-1", "node-1", "evict-test", daemonSetKind),
+				testPod("pod-2", "node-1", "evict-test", "ReplicaSet")},
+			nodeMetrics: map[string]int64{"node-1": 100},
+			want:        []string{"pod-2"}},
+		{name: "per node limit",
+			d:      &Strategy{PolicyName: "evict-test", Rules: rules},
+			limits: Limits{Interval: time.Minute, PerTick: 10, PerNode: 1, MaxClusterFraction: 1},
+			pods: []*v1.Pod{testPod("pod-1", "node-1", "evict-test", ""),
+				testPod("pod-2", "node-1", "evict-test", ""),
+				testPod("pod-3", "node-2", "evict-test", "")},
+			nodeMetrics: map[string]int64{"node-1": 100, "node-2": 100},
+			want:        []string{"pod-1", "pod-3"}},
+		{name: "per tick limit",
+			d:      &Strategy{PolicyName: "evict-test", Rules: rules},
+			limits: Limits{Interval: time.Minute, PerTick: 1, PerNode: 10, MaxClusterFraction: 1},
+			pods: []*v1.Pod{testPod("pod-1", "node-1", "evict-test", ""),
+				testPod("pod-2", "node-2", "evict-test", "")},
+			nodeMetrics: map[string]int64{"node-1": 100, "node-2": 100},
+			want:        []string{"pod-1"}},
+		{name: "cluster fraction limit",
+			d:      &Strategy{PolicyName: "evict-test", Rules: rules},
+			limits: Limits{Interval: time.Minute, PerTick: 10, PerNode: 10, MaxClusterFraction: 0.5},
+			pods: []*v1.Pod{testPod("pod-1", "node-1", "evict-test", ""),
+				testPod("pod-2", "node-1", "evict-test", ""),
+				testPod("pod-3", "node-1", "evict-test", ""),
+				testPod("pod-4", "node-2", "", "")},
+			nodeMetrics: map[string]int64{"node-1": 100},
+			want:        []string{"pod-1", "pod-2"}},
+		{name: "cluster fraction allows one pod in small clusters",
+			d:      &Strategy{PolicyName: "evict-test", Rules: rules},
+			limits: Limits{Interval: time.Minute, PerTick: 10, PerNode: 10, MaxClusterFraction: 0.1},
+			pods: []*v1.Pod{testPod("pod-1", "node-1", "evict-test", ""),
+				testPod("pod-2", "node-1", "evict-test", "")},
+			nodeMetrics: map[string]int64{"node-1": 100},
+			want:        []string{"pod-1"}},
+		{name: "zero cluster fraction disables evictions",
+			d:           &Strategy{PolicyName: "evict-test", Rules: rules},
+			limits:      Limits{Interval: time.Minute, PerTick: 10, PerNode: 10, MaxClusterFraction: 0},
+			pods:        []*v1.Pod{testPod("pod-1", "node-1", "evict-test", "")},
+			nodeMetrics: map[string]int64{"node-1": 100},
+			want:        []string{}},
+		{name: "pod selector",
+			d: &Strategy{PolicyName: "evict-test", Rules: rules, PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "batch"}}},
+			limits: Limits{Interval: time.Minute, PerTick: 10, PerNode: 10, MaxClusterFraction: 1},
+			pods: []*v1.Pod{testPod("pod-1", "node-1", "evict-test", ""),
+				labelledPod(testPod("pod-2", "node-1", "", ""), "app", "batch"),
+				labelledPod(testPod("pod-3", "node-1", "", ""), "app", "web"),
+				labelledPod(testPod("pod-4", "node-2", "", ""), "app", "batch")},
+			nodeMetrics: map[string]int64{"node-1": 100, "node-2": 50},
+			want:        []string{"pod-1", "pod-2"}},
+		{name: "invalid pod selector",
+			d: &Strategy{PolicyName: "evict-test", Rules: rules, PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Unknown"}}}},
+			limits:        Limits{Interval: time.Minute, PerTick: 10, PerNode: 10, MaxClusterFraction: 1},
+			pods:          []*v1.Pod{testPod("pod-1", "node-1", "evict-test", "")},
+			nodeMetrics:   map[string]int64{"node-1": 100},
+			want:          []string{},
+			wantErr:       true,
+			wantErrString: "invalid pod selector"},
+		{name: "disruption budget blocks eviction",
+			d:      &Strategy{PolicyName: "evict-test", Rules: rules},
+			limits: Limits{Interval: time.Minute, PerTick: 10, PerNode: 10, MaxClusterFraction: 1},
+			pods: []*v1.Pod{testPod("pod-1", "node-1", "evict-test", ""),
+				testPod("pod-2", "node-1", "evict-test", "")},
+			blocked:     map[string]bool{"pod-1": true},
+			nodeMetrics: map[string]int64{"node-1": 100},
+			want:        []string{"pod-2"}},
+		{name: "no violation",
+			d:      &Strategy{PolicyName: "evict-test", Rules: rules},
+			limits: Limits{Interval: time.Minute, PerTick: 10, PerNode: 10, MaxClusterFraction: 1},
+			pods:   []*v1.Pod{testPod("pod-1", "node-1", "evict-test", "")},
+			want:   []string{}},
+		{name: "list pods with exception",
+			d:             &Strategy{PolicyName: "evict-test", Rules: rules},
+			limits:        Limits{Interval: time.Minute, PerTick: 10, PerNode: 10, MaxClusterFraction: 1},
+			pods:          []*v1.Pod{testPod("pod-1", "node-1", "evict-test", "")},
+			nodeMetrics:   map[string]int64{"node-1": 100},
+			listErr:       true,
+			want:          []string{},
+			wantErr:       true,
+			wantErrString: failPodListMessage},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			limiter = newRateLimiter(tt.limits)
+			evicted := []string{}
+			client := evictionRecorder(&evicted, tt.blocked)
+
+			for _, pod := range tt.pods {
+				if _, err := client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+					t.Errorf("Cannot create pod %s: %v", pod.Name, err)
+				}
+			}
+
+			if tt.listErr {
+				client.PrependReactor("list", "pods",
+					func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+						return true, &v1.PodList{}, errMockTest
+					})
+			}
+
+			mockCache := cache.MockEmptySelfUpdatingCache()
+			nodeMetrics := metrics.NodeMetricsInfo{}
+
+			for nodeName, value := range tt.nodeMetrics {
+				nodeMetrics[nodeName] = metrics.NodeMetric{Timestamp: time.Now(), Window: 1, Value: *resource.NewQuantity(value, resource.DecimalSI)}
+			}
+
+			if err := mockCache.WriteMetric("temperature", nodeMetrics); err != nil {
+				t.Errorf("Cannot write metric to mock cache for test: %v", err)
+			}
+
+			enforcer := strategy.NewEnforcer(client)
+			enforcer.RegisterStrategyType(tt.d)
+			enforcer.AddStrategy(tt.d, tt.d.StrategyType())
+
+			got, err := tt.d.Enforce(enforcer, mockCache)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Enforce() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if err != nil && !strings.Contains(err.Error(), tt.wantErrString) {
+				t.Errorf("Expecting error to contain %v, got %v", tt.wantErrString, err)
+			}
+
+			sort.Strings(evicted)
+
+			if !reflect.DeepEqual(evicted, tt.want) {
+				t.Errorf("Evicted pods %v, want %v", evicted, tt.want)
+			}
+
+			if !tt.wantErr && got != len(tt.want) {
+				t.Errorf("Enforce() = %v, want %v", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestRateLimiter_allow(t *testing.T) {
+	now := time.Now()
+	r := newRateLimiter(Limits{Interval: time.Second, PerTick: 2, PerNode: 1, MaxClusterFraction: 1})
+
+	if !r.allow("node-1", now) {
+		t.Error("first eviction should be allowed")
+	}
+
+	r.record("node-1", now)
+
+	if r.allow("node-1", now) {
+		t.Error("second eviction from the same node should not be allowed")
+	}
+
+	if !r.allow("node-2", now) {
+		t.Error("eviction from another node should be allowed")
+	}
+
+	r.record("node-2", now)
+
+	if r.allow("node-3", now) {
+		t.Error("eviction over the per tick limit should not be allowed")
+	}
+
+	if !r.allow("node-1", now.Add(2*time.Second)) {
+		t.Error("eviction should be allowed after the interval")
+	}
+}
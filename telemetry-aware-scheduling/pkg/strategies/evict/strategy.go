@@ -0,0 +1,68 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package evict provides the evict strategy. Violation conditions are shared with the deschedule strategy.
+// When a node is violating the evict strategy, the enforcer evicts the pods linked to the policy, by their label or
+// the pod selector of the strategy, from that node through the Eviction API, so PodDisruptionBudgets are honored.
+package evict
+
+import (
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/deschedule"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// StrategyType is set to "evict".
+const (
+	StrategyType = "evict"
+)
+
+// Strategy type for evicting pods from a single policy.
+type Strategy telempol.TASPolicyStrategy
+
+func init() {
+	core.Register(StrategyType, func(policy telempol.TASPolicyStrategy) core.Interface {
+		str := Strategy(policy)
+
+		return &str
+	})
+}
+
+// StrategyType returns the name of the strategy type. This is used to place it in the registry.
+func (d *Strategy) StrategyType() string {
+	return StrategyType
+}
+
+// Violated returns the nodes violating the rules of the strategy. The evaluation is the same as for deschedule.
+func (d *Strategy) Violated(cache cache.Reader) map[string]interface{} {
+	return (*deschedule.Strategy)(d).Violated(cache)
+}
+
+// Equals checks if a strategy is the same as the passed strategy.
+// It can be used to prevent duplication of strategies in the API and is also used to find strategies for deletion.
+func (d *Strategy) Equals(other core.Interface) bool {
+	otherEvictStrategy, ok := other.(*Strategy)
+	if !ok {
+		return false
+	}
+
+	return (*deschedule.Strategy)(d).Equals((*deschedule.Strategy)(otherEvictStrategy)) &&
+		equality.Semantic.DeepEqual(d.PodSelector, otherEvictStrategy.PodSelector)
+}
+
+// GetPolicyName returns the name of the policy that originated strategy.
+func (d *Strategy) GetPolicyName() string {
+	return d.PolicyName
+}
+
+// GetActiveWindows returns the time windows in which the strategy applies.
+func (d *Strategy) GetActiveWindows() []telempol.TASPolicyActiveWindow {
+	return d.ActiveWindows
+}
+
+// SetPolicyName adds a policy name to be associated with this strategy.
+func (d *Strategy) SetPolicyName(name string) {
+	d.PolicyName = name
+}
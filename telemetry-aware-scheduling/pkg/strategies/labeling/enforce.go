@@ -8,7 +8,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
@@ -188,41 +187,52 @@ func (d *Strategy) updateNodeLabels(enforcer *strategy.MetricEnforcer,
 	return totalViolations, errOut
 }
 
-// Function  will choose the biggest/lowest value depending on the rule operator.
+// Function  will choose the value scoring higher for the rule operator, e.g. the biggest one for GreaterThan and
+// the lowest one for LessThan.
 func shouldUpdateRuleThreshold(result, olderRes ruleResult) bool {
-	return ((result.rule.Operator == "GreaterThan" && result.quantity.Cmp(olderRes.quantity) > 0) ||
-		(result.rule.Operator == "LessThan" && result.quantity.Cmp(olderRes.quantity) < 0))
+	resultScore, olderScore := strategy.Score(result.quantity, result.rule), strategy.Score(olderRes.quantity, olderRes.rule)
+
+	return resultScore.Cmp(olderScore) > 0
 }
 
 // minMaxFilterViolatedRules filters out violated rules in case the same label name is being used.
 // When the name is equal, only the largest or smallest value having metric among the rules will be
-// returned in the result map, depending on the operator of the rule.
+// returned in the result map, depending on the operator of the rule. Rules sharing a label name must have the same
+// operator, a label name used by violated rules with different operators is left out and logged.
 func minMaxFilterViolatedRules(violationResult interface{}) map[string]ruleResult {
 	violatedRules := map[string]ruleResult{}
+	conflicts := map[string]bool{}
 
-	defer func() {
-		err := recover()
-		if err != nil {
-			klog.Error("Unsupported config: With equivalent labels, the operator in related rules must also be equivalent.")
-		}
-	}()
+	results, ok := violationResult.(*violationResultType)
+	if !ok {
+		return violatedRules
+	}
 
-	for _, result := range violationResult.(*violationResultType).ruleResults {
+	for _, result := range results.ruleResults {
 		for _, label := range result.rule.Labels {
 			nameValuePair := strings.Split(label, "=")
 			name := nameValuePair[0]
 			olderRes, old := violatedRules[name]
 
 			if old && olderRes.rule.Operator != result.rule.Operator {
-				log.Panic()
+				conflicts[name] = true
+
+				continue
 			}
 
-			if !old || old && shouldUpdateRuleThreshold(result, olderRes) {
+			if !old || shouldUpdateRuleThreshold(result, olderRes) {
 				violatedRules[name] = result
 			}
 		}
 	}
 
+	for name := range conflicts {
+		msg := fmt.Sprintf("label %v not set: rules sharing a label name must have the same operator", name)
+		klog.V(l2).InfoS(msg, "component", "controller")
+
+		delete(violatedRules, name)
+	}
+
 	return violatedRules
 }
 
@@ -241,17 +251,23 @@ func createLabels(violatedRules map[string]ruleResult,
 // as a flat map of the nodeName->label->true for quick access searching.
 // Within same policy, overlapping label key values will go through min-max filtering, largest or smallest
 // value producing metric will get its label depending on rule operator. Unique label keys will always be
-// returned for the violating cases.
+// returned for the violating cases. Strategies outside their active windows are skipped, so the labels their cleanup
+// removed aren't added back.
 func (d *Strategy) nodeStatusForStrategy(enforcer *strategy.MetricEnforcer,
 	cache cache.Reader) (violationMap, nodeViolations) {
 	violations := violationMap{}
 	allViolatedLabels := nodeViolations{}
+	now := strategy.Now()
+
+	for strg := range enforcer.RegisteredStrategies[StrategyType] {
+		if !strategy.IsActive(strg, now) {
+			continue
+		}
 
-	for strategy := range enforcer.RegisteredStrategies[StrategyType] {
-		policyName := strategy.GetPolicyName()
+		policyName := strg.GetPolicyName()
 		klog.V(l2).InfoS("Evaluating "+policyName, "component", "controller")
 
-		nodes := strategy.Violated(cache)
+		nodes := strg.Violated(cache)
 
 		for nodeName, violationResult := range nodes {
 			if _, ok := violations[nodeName]; !ok {
@@ -12,7 +12,7 @@ import (
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
 	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
-	telpol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telpol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -44,7 +44,7 @@ func TestLabelingStrategy_Enforce(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "labeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "GreaterThan", Target: 99, Labels: []string{"gpu-card1=false"}}}},
+					{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("99"), Labels: []string{"gpu-card1=false"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{"telemetry.aware.scheduling.labeling-test/gpu-card1": "false"}}},
@@ -53,7 +53,7 @@ func TestLabelingStrategy_Enforce(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "labeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "GreaterThan", Target: 3000, Labels: []string{"gpu-card0=false"}}}},
+					{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("3000"), Labels: []string{"gpu-card0=false"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
@@ -63,8 +63,8 @@ func TestLabelingStrategy_Enforce(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "labeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "GreaterThan", Target: 99, Labels: []string{"gpu-card1=false"}},
-					{Metricname: "cpu", Operator: "GreaterThan", Target: 10, Labels: []string{"gpu-card2=true"}}}},
+					{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("99"), Labels: []string{"gpu-card1=false"}},
+					{Metricname: "cpu", Operator: "GreaterThan", Target: resource.MustParse("10"), Labels: []string{"gpu-card2=true"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{"telemetry.aware.scheduling.labeling-test/gpu-card1": "false",
@@ -76,8 +76,8 @@ func TestLabelingStrategy_Enforce(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "labeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "GreaterThan", Target: 100, Labels: []string{"gpu-device=card0"}},
-					{Metricname: "cpu", Operator: "GreaterThan", Target: 100, Labels: []string{"gpu-device=card1"}}}},
+					{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("100"), Labels: []string{"gpu-device=card0"}},
+					{Metricname: "cpu", Operator: "GreaterThan", Target: resource.MustParse("100"), Labels: []string{"gpu-device=card1"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{"telemetry.aware.scheduling.labeling-test/gpu-device": "card0"}}},
@@ -88,8 +88,8 @@ func TestLabelingStrategy_Enforce(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "labeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "LessThan", Target: 10000, Labels: []string{"gpu-device=card0"}},
-					{Metricname: "cpu", Operator: "LessThan", Target: 10000, Labels: []string{"gpu-device=card1"}}}},
+					{Metricname: "memory", Operator: "LessThan", Target: resource.MustParse("10000"), Labels: []string{"gpu-device=card0"}},
+					{Metricname: "cpu", Operator: "LessThan", Target: resource.MustParse("10000"), Labels: []string{"gpu-device=card1"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{"telemetry.aware.scheduling.labeling-test/gpu-device": "card1"}}},
@@ -98,8 +98,8 @@ func TestLabelingStrategy_Enforce(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "labeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "Equals", Target: 2000, Labels: []string{"gpu-device=card1"}},
-					{Metricname: "cpu", Operator: "Equals", Target: 200, Labels: []string{"gpu-device=card0"}}}},
+					{Metricname: "memory", Operator: "Equals", Target: resource.MustParse("2000"), Labels: []string{"gpu-device=card1"}},
+					{Metricname: "cpu", Operator: "Equals", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card0"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{"telemetry.aware.scheduling.labeling-test/gpu-device": "card1"}}},
@@ -108,8 +108,8 @@ func TestLabelingStrategy_Enforce(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "labeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "Equals", Target: 2000, Labels: []string{"gpu-device=card0"}},
-					{Metricname: "cpu", Operator: "Equals", Target: 200, Labels: []string{"gpu-device=card1"}}}},
+					{Metricname: "memory", Operator: "Equals", Target: resource.MustParse("2000"), Labels: []string{"gpu-device=card0"}},
+					{Metricname: "cpu", Operator: "Equals", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card1"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{"telemetry.aware.scheduling.labeling-test/gpu-device": "card0"}}},
@@ -118,11 +118,35 @@ func TestLabelingStrategy_Enforce(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "labeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "Equals", Target: 2000, Labels: []string{"gpu-device=card0"}},
-					{Metricname: "cpu", Operator: "Equals", Target: 200, Labels: []string{"gpu-device=card1"}}}},
+					{Metricname: "memory", Operator: "Equals", Target: resource.MustParse("2000"), Labels: []string{"gpu-device=card0"}},
+					{Metricname: "cpu", Operator: "Equals", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card1"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{"telemetry.aware.scheduling.labeling-test/gpu-device": "card0"}}},
+
+		// same label key: gpu-device - metric "memory" >= "cpu", inclusive of the target
+		{name: "node single labelled: -different metrics -same inclusive op, tag, and label key",
+			d: &Strategy{
+				PolicyName: "labeling-test",
+				Rules: []telpol.TASPolicyRule{
+					{Metricname: "cpu", Operator: "GreaterOrEqual", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card1"}},
+					{Metricname: "memory", Operator: "GreaterOrEqual", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card0"}}}},
+			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
+			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
+			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{"telemetry.aware.scheduling.labeling-test/gpu-device": "card0"}}},
+
+		// same label key: gpu-device - metric "cpu" is further out of its range than "memory"
+		{name: "node single labelled: -different metrics -out of range, same tag and label key",
+			d: &Strategy{
+				PolicyName: "labeling-test",
+				Rules: []telpol.TASPolicyRule{
+					{Metricname: "memory", Operator: "OutOfRange", Labels: []string{"gpu-device=card0"},
+						Range: &telpol.TASPolicyRuleRange{Lower: resource.MustParse("0"), Upper: resource.MustParse("1900")}},
+					{Metricname: "cpu", Operator: "OutOfRange", Labels: []string{"gpu-device=card1"},
+						Range: &telpol.TASPolicyRuleRange{Lower: resource.MustParse("1000"), Upper: resource.MustParse("2000")}}}},
+			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
+			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
+			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{"telemetry.aware.scheduling.labeling-test/gpu-device": "card1"}}},
 	}
 
 	for _, tt := range tests {
@@ -174,6 +198,44 @@ func TestLabelingStrategy_Enforce(t *testing.T) {
 	}
 }
 
+func TestLabelingStrategy_EnforceInactive(t *testing.T) {
+	defer func() { strategy.Now = time.Now }()
+
+	strategy.Now = func() time.Time { return time.Date(2024, time.January, 8, 12, 0, 0, 0, time.UTC) }
+
+	rule := telpol.TASPolicyRule{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("99"),
+		Labels: []string{"hot=true"}}
+	active := &Strategy{PolicyName: "day", Rules: []telpol.TASPolicyRule{rule}}
+	inactive := &Strategy{PolicyName: "night", Rules: []telpol.TASPolicyRule{rule},
+		ActiveWindows: []telpol.TASPolicyActiveWindow{{Start: "22:00", End: "06:00"}}}
+
+	enforcer := strategy.NewEnforcer(testclient.NewSimpleClientset())
+	enforcer.RegisterStrategyType(active)
+	enforcer.AddStrategy(active, StrategyType)
+	enforcer.AddStrategy(inactive, StrategyType)
+
+	mockCache := cache.MockEmptySelfUpdatingCache()
+	if err := mockCache.WriteMetric("memory", metrics.NodeMetricsInfo{"node-1": {Timestamp: time.Now(), Window: 1,
+		Value: *resource.NewQuantity(2000, resource.DecimalSI)}}); err != nil {
+		t.Fatalf("Cannot write metric to mock cache for test: %v", err)
+	}
+
+	if _, err := enforcer.KubeClient.CoreV1().Nodes().Create(context.TODO(),
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"node-1-label": "test"}}},
+		metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Cannot create node: %v", err)
+	}
+
+	if _, err := active.Enforce(enforcer, mockCache); err != nil {
+		t.Fatalf("Unexpected error from Enforce: %v", err)
+	}
+
+	node, _ := enforcer.KubeClient.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+	if want := map[string]string{"node-1-label": "test", labelPrefix + "day/hot": "true"}; !reflect.DeepEqual(node.Labels, want) {
+		t.Errorf("Got labels %v, want %v", node.Labels, want)
+	}
+}
+
 func TestLabelingStrategy_Enforce_unsupportedCases(t *testing.T) {
 	type args struct {
 		enforcer *strategy.MetricEnforcer
@@ -197,8 +259,8 @@ func TestLabelingStrategy_Enforce_unsupportedCases(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "labeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "GreaterThan", Target: 200, Labels: []string{"gpu-device=card0"}},
-					{Metricname: "cpu", Operator: "LessThan", Target: 200, Labels: []string{"gpu-device=card1"}}}},
+					{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card0"}},
+					{Metricname: "cpu", Operator: "LessThan", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card1"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
@@ -207,8 +269,8 @@ func TestLabelingStrategy_Enforce_unsupportedCases(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "labeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "LessThan", Target: 2000, Labels: []string{"gpu-device=card0"}},
-					{Metricname: "cpu", Operator: "Equals", Target: 200, Labels: []string{"gpu-device=card1"}}}},
+					{Metricname: "memory", Operator: "LessThan", Target: resource.MustParse("2000"), Labels: []string{"gpu-device=card0"}},
+					{Metricname: "cpu", Operator: "Equals", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card1"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
@@ -217,11 +279,22 @@ func TestLabelingStrategy_Enforce_unsupportedCases(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "labeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "GreaterThan", Target: 20, Labels: []string{"gpu-device=card1"}},
-					{Metricname: "cpu", Operator: "Equals", Target: 200, Labels: []string{"gpu-device=card0"}}}},
+					{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("20"), Labels: []string{"gpu-device=card1"}},
+					{Metricname: "cpu", Operator: "Equals", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card0"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
+
+		{name: "node labelled for other label keys: -different op, the same label keys",
+			d: &Strategy{
+				PolicyName: "labeling-test",
+				Rules: []telpol.TASPolicyRule{
+					{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("20"), Labels: []string{"gpu-device=card1"}},
+					{Metricname: "cpu", Operator: "Equals", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card0"}},
+					{Metricname: "cpu", Operator: "GreaterThan", Target: resource.MustParse("20"), Labels: []string{"cpu-load=high"}}}},
+			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.labeling-test": ""}}},
+			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
+			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{"telemetry.aware.scheduling.labeling-test/cpu-load": "high"}}},
 	}
 
 	for _, tt := range tests {
@@ -274,6 +347,45 @@ func TestLabelingStrategy_Enforce_unsupportedCases(t *testing.T) {
 	}
 }
 
+func TestMinMaxFilterViolatedRules(t *testing.T) {
+	hot := telpol.TASPolicyRule{Metricname: "temperature", Operator: "GreaterThan", Labels: []string{"card0=hot"}}
+	hotter := telpol.TASPolicyRule{Metricname: "power", Operator: "GreaterThan", Labels: []string{"card0=hotter"}}
+	cold := telpol.TASPolicyRule{Metricname: "temperature", Operator: "LessThan", Labels: []string{"card0=cold"}}
+	busy := telpol.TASPolicyRule{Metricname: "load", Operator: "GreaterThan", Labels: []string{"card1=busy"}}
+
+	tests := []struct {
+		name    string
+		results []ruleResult
+		want    map[string]string
+	}{
+		{name: "largest value wins",
+			results: []ruleResult{{rule: hot, quantity: resource.MustParse("80")}, {rule: hotter, quantity: resource.MustParse("90")}},
+			want:    map[string]string{"card0": "power"}},
+		{name: "conflicting operators leave the label out",
+			results: []ruleResult{{rule: hot, quantity: resource.MustParse("80")}, {rule: cold, quantity: resource.MustParse("10")},
+				{rule: busy, quantity: resource.MustParse("5")}},
+			want: map[string]string{"card1": "load"}},
+		{name: "conflict found after a later update",
+			results: []ruleResult{{rule: hot, quantity: resource.MustParse("80")}, {rule: hotter, quantity: resource.MustParse("90")},
+				{rule: cold, quantity: resource.MustParse("10")}},
+			want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			for name, result := range minMaxFilterViolatedRules(&violationResultType{ruleResults: tt.results}) {
+				got[name] = result.rule.Metricname
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("minMaxFilterViolatedRules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestLabelingStrategy_Cleanup(t *testing.T) {
 	type args struct {
 		enforcer *strategy.MetricEnforcer
@@ -297,7 +409,7 @@ func TestLabelingStrategy_Cleanup(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "unlabeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "GreaterThan", Target: 99, Labels: []string{"gpu-card1=false"}}}},
+					{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("99"), Labels: []string{"gpu-card1=false"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.unlabeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
@@ -307,7 +419,7 @@ func TestLabelingStrategy_Cleanup(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "unlabeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "GreaterThan", Target: 3000, Labels: []string{"gpu-card0=false"}}}},
+					{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("3000"), Labels: []string{"gpu-card0=false"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.unlabeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
@@ -317,8 +429,8 @@ func TestLabelingStrategy_Cleanup(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "unlabeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "GreaterThan", Target: 99, Labels: []string{"gpu-card1=false"}},
-					{Metricname: "cpu", Operator: "GreaterThan", Target: 10, Labels: []string{"gpu-card2=true"}}}},
+					{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("99"), Labels: []string{"gpu-card1=false"}},
+					{Metricname: "cpu", Operator: "GreaterThan", Target: resource.MustParse("10"), Labels: []string{"gpu-card2=true"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.unlabeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
@@ -328,8 +440,8 @@ func TestLabelingStrategy_Cleanup(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "unlabeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "GreaterThan", Target: 100, Labels: []string{"gpu-device=card0"}},
-					{Metricname: "cpu", Operator: "GreaterThan", Target: 100, Labels: []string{"gpu-device=card1"}}}},
+					{Metricname: "memory", Operator: "GreaterThan", Target: resource.MustParse("100"), Labels: []string{"gpu-device=card0"}},
+					{Metricname: "cpu", Operator: "GreaterThan", Target: resource.MustParse("100"), Labels: []string{"gpu-device=card1"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.unlabeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
@@ -339,8 +451,8 @@ func TestLabelingStrategy_Cleanup(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "unlabeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "LessThan", Target: 10000, Labels: []string{"gpu-device=card0"}},
-					{Metricname: "cpu", Operator: "LessThan", Target: 10000, Labels: []string{"gpu-device=card1"}}}},
+					{Metricname: "memory", Operator: "LessThan", Target: resource.MustParse("10000"), Labels: []string{"gpu-device=card0"}},
+					{Metricname: "cpu", Operator: "LessThan", Target: resource.MustParse("10000"), Labels: []string{"gpu-device=card1"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.unlabeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
@@ -350,8 +462,8 @@ func TestLabelingStrategy_Cleanup(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "unlabeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "Equals", Target: 2000, Labels: []string{"gpu-device=card1"}},
-					{Metricname: "cpu", Operator: "Equals", Target: 200, Labels: []string{"gpu-device=card0"}}}},
+					{Metricname: "memory", Operator: "Equals", Target: resource.MustParse("2000"), Labels: []string{"gpu-device=card1"}},
+					{Metricname: "cpu", Operator: "Equals", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card0"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.unlabeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
@@ -361,8 +473,8 @@ func TestLabelingStrategy_Cleanup(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "unlabeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "Equals", Target: 2000, Labels: []string{"gpu-device=card0"}},
-					{Metricname: "cpu", Operator: "Equals", Target: 200, Labels: []string{"gpu-device=card1"}}}},
+					{Metricname: "memory", Operator: "Equals", Target: resource.MustParse("2000"), Labels: []string{"gpu-device=card0"}},
+					{Metricname: "cpu", Operator: "Equals", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card1"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.unlabeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
@@ -372,8 +484,8 @@ func TestLabelingStrategy_Cleanup(t *testing.T) {
 			d: &Strategy{
 				PolicyName: "unlabeling-test",
 				Rules: []telpol.TASPolicyRule{
-					{Metricname: "memory", Operator: "Equals", Target: 2000, Labels: []string{"gpu-device=card0"}},
-					{Metricname: "cpu", Operator: "Equals", Target: 200, Labels: []string{"gpu-device=card1"}}}},
+					{Metricname: "memory", Operator: "Equals", Target: resource.MustParse("2000"), Labels: []string{"gpu-device=card0"}},
+					{Metricname: "cpu", Operator: "Equals", Target: resource.MustParse("200"), Labels: []string{"gpu-device=card1"}}}},
 			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"telemetry.aware.scheduling.unlabeling-test": ""}}},
 			args: args{enforcer: strategy.NewEnforcer(testclient.NewSimpleClientset()), cache: cache.MockEmptySelfUpdatingCache()},
 			want: expected{nodeNames: []string{"node-1"}, nodeLabels: map[string]string{}}},
@@ -8,10 +8,11 @@ package labeling
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
-	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
 )
@@ -26,6 +27,14 @@ const (
 // Strategy type for labeling from a single policy.
 type Strategy telempol.TASPolicyStrategy
 
+func init() {
+	core.Register(StrategyType, func(policy telempol.TASPolicyStrategy) core.Interface {
+		str := Strategy(policy)
+
+		return &str
+	})
+}
+
 // StrategyType returns the name of the strategy type. This is used to place it in the registry.
 func (d *Strategy) StrategyType() string {
 	return StrategyType
@@ -40,98 +49,30 @@ type violationResultType struct {
 	ruleResults []ruleResult
 }
 
-func (d *Strategy) fetchRuleViolatingNodes(cache cache.Reader) map[string]interface{} {
+// Violated checks if the strategy is violated by searching for nodes that have metrics that don't accord with
+// the target in labeling strategy. The rules are combined by the strategy group or logical operator.
+// Returns a map of nodeNames as key with a slice of violated rules and metric quantities in the result type.
+func (d *Strategy) Violated(cache cache.Reader) map[string]interface{} {
 	violatingNodes := map[string]interface{}{}
 
-	for _, rule := range d.Rules {
-		nodeMetrics, err := cache.ReadMetric(rule.Metricname)
-		if err != nil {
-			klog.V(l2).InfoS(err.Error(), "component", "controller")
-
-			continue
-		}
-
-		for nodeName, nodeMetric := range nodeMetrics {
-			msg := fmt.Sprint(nodeName+" "+rule.Metricname, " = ", nodeMetric.Value.AsDec())
-			klog.V(l4).InfoS(msg, "component", "controller")
-
-			if core.EvaluateRule(nodeMetric.Value, rule) {
-				msg := fmt.Sprintf(nodeName + " violating " + d.PolicyName + ": " + ruleToString(rule))
-				klog.V(l2).InfoS(msg, "component", "controller")
+	for nodeName, results := range core.Evaluate(telempol.TASPolicyStrategy(*d), cache) {
+		res := &violationResultType{}
 
-				if _, ok := violatingNodes[nodeName]; !ok {
-					violatingNodes[nodeName] = &violationResultType{}
-				}
+		for _, result := range results {
+			klog.V(l2).InfoS(nodeName+" violating "+d.PolicyName+": "+ruleToString(result.Rule), "component", "controller")
 
-				res, ok := violatingNodes[nodeName].(*violationResultType)
-				if !ok {
-					klog.Error("unexpected type")
-
-					continue
-				}
-
-				res.ruleResults = append(res.ruleResults, ruleResult{rule: rule, quantity: nodeMetric.Value})
-				if len(res.ruleResults) > 0 {
-					for _, ruleRes := range res.ruleResults {
-						klog.V(l2).Infof("Violated rules: %v", ruleToString(ruleRes.rule))
-					}
-				}
-			}
+			res.ruleResults = append(res.ruleResults, ruleResult{rule: result.Rule, quantity: result.Value})
 		}
-	}
-
-	return violatingNodes
-}
 
-func (d *Strategy) filterViolatingNodeForAllLogicalOperator(violatingNodes map[string]interface{}) {
-	if d.LogicalOperator == "allOf" {
-		for nodeName := range violatingNodes {
-			if _, ok := violatingNodes[nodeName]; ok {
-				if res, ok := violatingNodes[nodeName].(*violationResultType); ok {
-					if len(res.ruleResults) != len(d.Rules) {
-						delete(violatingNodes, nodeName)
-					}
-				}
-			}
-		}
+		violatingNodes[nodeName] = res
 	}
-}
-
-// Violated checks if the strategy is violated by searching for nodes that have metrics that don't accord with
-// the target in labeling strategy.
-// Returns a map of nodeNames as key with a slice of violated rules and metric quantities in the result type.
-func (d *Strategy) Violated(cache cache.Reader) map[string]interface{} {
-	violatingNodes := d.fetchRuleViolatingNodes(cache)
-	d.filterViolatingNodeForAllLogicalOperator(violatingNodes)
 
 	return violatingNodes
 }
 
 // ruleToString returns the rule passed to it as a single string.
 func ruleToString(rule telempol.TASPolicyRule) string {
-	return fmt.Sprintf("%v %v %v %v", rule.Metricname, rule.Operator, rule.Target, rule.Labels)
-}
-
-func equalRules(a, b *telempol.TASPolicyRule) bool {
-	if a.Metricname != b.Metricname {
-		return false
-	}
-
-	if a.Target != b.Target {
-		return false
-	}
-
-	if a.Operator != b.Operator {
-		return false
-	}
-
-	for j := range a.Labels {
-		if a.Labels[j] != b.Labels[j] {
-			return false
-		}
-	}
-
-	return true
+	return fmt.Sprintf("%v %v %v %v", core.RuleName(rule), rule.Operator, core.TargetString(rule), rule.Labels)
 }
 
 // Equals checks if a strategy is the same as the passed strategy.
@@ -140,19 +81,9 @@ func equalRules(a, b *telempol.TASPolicyRule) bool {
 func (d *Strategy) Equals(other core.Interface) bool {
 	otherLabelingStrategy, ok := other.(*Strategy)
 
-	sameName := other.GetPolicyName() == d.GetPolicyName()
-	if ok && sameName && len(d.Rules) > 0 && len(d.Rules) == len(otherLabelingStrategy.Rules) {
-		for i, rule := range d.Rules {
-			rule := rule
-			if !equalRules(&rule, &otherLabelingStrategy.Rules[i]) {
-				return false
-			}
-		}
-
-		return true
-	}
-
-	return false
+	return ok && other.GetPolicyName() == d.GetPolicyName() && len(d.Rules) > 0 &&
+		core.EqualRules(telempol.TASPolicyStrategy(*d), telempol.TASPolicyStrategy(*otherLabelingStrategy)) &&
+		reflect.DeepEqual(d.ActiveWindows, otherLabelingStrategy.ActiveWindows)
 }
 
 // GetPolicyName returns the name of the policy that originated strategy.
@@ -160,6 +91,11 @@ func (d *Strategy) GetPolicyName() string {
 	return d.PolicyName
 }
 
+// GetActiveWindows returns the time windows in which the strategy applies.
+func (d *Strategy) GetActiveWindows() []telempol.TASPolicyActiveWindow {
+	return d.ActiveWindows
+}
+
 // SetPolicyName adds a policy name to be associated with this strategy.
 func (d *Strategy) SetPolicyName(name string) {
 	d.PolicyName = name
@@ -15,7 +15,7 @@ import (
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
-	v1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	v1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
 )
@@ -408,7 +408,7 @@ func strategyRuleDefault(policyname, metricname, operator string, target int64,
 func strategyRule(policyname, logicalOp, metricname, operator string, target int64, labels []string) *Strategy {
 	return &Strategy{
 		PolicyName:      policyname,
-		LogicalOperator: logicalOp,
+		LogicalOperator: v1.LogicalOperator(logicalOp),
 		Rules: []v1.TASPolicyRule{
 			metricRules(metricname, operator, target, labels)}}
 }
@@ -416,8 +416,8 @@ func strategyRule(policyname, logicalOp, metricname, operator string, target int
 func metricRules(metricname string, operator string, target int64, labels []string) v1.TASPolicyRule {
 	return v1.TASPolicyRule{
 		Metricname: metricname,
-		Operator:   operator,
-		Target:     target,
+		Operator:   v1.Operator(operator),
+		Target:     *resource.NewQuantity(target, resource.DecimalSI),
 		Labels:     labels,
 	}
 }
@@ -0,0 +1,58 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package labeling
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Validate checks that every rule label is a name=value pair which makes a valid node label once prefixed with the
+// policy name. Rules sharing a label name must use the same operator, as the min-max filtering of violated rules
+// can't pick between them otherwise.
+func (d *Strategy) Validate(path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	labelRules := map[string]int{}
+
+	for i, rule := range d.Rules {
+		rulePath := path.Child("rules").Index(i)
+
+		for j, label := range rule.Labels {
+			labelPath := rulePath.Child("labels").Index(j)
+
+			nameValuePair := strings.Split(label, "=")
+			if len(nameValuePair) != pairValue {
+				allErrs = append(allErrs, field.Invalid(labelPath, label, "must be of the form name=value"))
+
+				continue
+			}
+
+			for _, msg := range validation.IsQualifiedName(getPrefix(d.PolicyName) + nameValuePair[0]) {
+				allErrs = append(allErrs, field.Invalid(labelPath, label, "invalid label name: "+msg))
+			}
+
+			for _, msg := range validation.IsValidLabelValue(nameValuePair[1]) {
+				allErrs = append(allErrs, field.Invalid(labelPath, label, "invalid label value: "+msg))
+			}
+
+			other, seen := labelRules[nameValuePair[0]]
+			if !seen {
+				labelRules[nameValuePair[0]] = i
+
+				continue
+			}
+
+			if d.Rules[other].Operator != rule.Operator {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("operator"), rule.Operator,
+					fmt.Sprintf("label name %v is also used by rule %d with operator %v", nameValuePair[0], other,
+						d.Rules[other].Operator)))
+			}
+		}
+	}
+
+	return allErrs
+}
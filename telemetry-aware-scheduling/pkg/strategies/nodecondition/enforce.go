@@ -0,0 +1,176 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package nodecondition
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	conditionTypePrefix        = "TelemetryPolicyViolation/"
+	violatedReason             = "RulesViolated"
+	compliantReason            = "RulesCompliant"
+	failNodeListCleanUpMessage = "failed to list nodes during clean-up"
+	failNodeListEnforceMessage = "failed to list all nodes during enforce"
+	failNodePatchMessage       = "failed to patch node status"
+)
+
+var errNull = errors.New("")
+
+// ConditionType returns the type of the node condition set for the given policy.
+func ConditionType(policyName string) v1.NodeConditionType {
+	return v1.NodeConditionType(conditionTypePrefix + policyName)
+}
+
+// findCondition returns the condition of the given type from the node status, or nil if the node doesn't have it.
+func findCondition(node *v1.Node, conditionType v1.NodeConditionType) *v1.NodeCondition {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == conditionType {
+			return &node.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// desiredCondition builds the condition for a node. The transition time is kept when the status doesn't change.
+func (d *Strategy) desiredCondition(current *v1.NodeCondition, violating bool, message string, now metav1.Time) v1.NodeCondition {
+	condition := v1.NodeCondition{
+		Type:               ConditionType(d.PolicyName),
+		Status:             v1.ConditionFalse,
+		Reason:             compliantReason,
+		Message:            "node complies with all rules of " + d.PolicyName,
+		LastHeartbeatTime:  now,
+		LastTransitionTime: now,
+	}
+
+	if violating {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = violatedReason
+		condition.Message = "node violates " + d.PolicyName + ": " + message
+	}
+
+	if current != nil && current.Status == condition.Status {
+		condition.LastTransitionTime = current.LastTransitionTime
+	}
+
+	return condition
+}
+
+// patchNodeStatus sends a strategic merge patch for the node status conditions to the API server.
+func (d *Strategy) patchNodeStatus(nodeName string, enforcer *strategy.MetricEnforcer, condition interface{}) error {
+	payload := map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{condition},
+		},
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		klog.V(l4).InfoS(err.Error(), "component", "controller")
+
+		return fmt.Errorf("fail to encode patch %v to JSON: %w", payload, err)
+	}
+
+	_, err = enforcer.KubeClient.CoreV1().Nodes().PatchStatus(context.TODO(), nodeName, jsonPayload)
+	if err != nil {
+		klog.V(l4).InfoS(err.Error(), "component", "controller")
+
+		return fmt.Errorf("%s %v: %w", failNodePatchMessage, nodeName, err)
+	}
+
+	return nil
+}
+
+// Enforce sets the policy node condition on every node. Violating nodes get a condition with status True and a
+// message listing the violated rules and metric values. Nodes which previously violated the policy get their
+// condition switched back to False. Node status is only patched when the condition status or message changes.
+func (d *Strategy) Enforce(enforcer *strategy.MetricEnforcer, cache cache.Reader) (int, error) {
+	nodes, err := enforcer.KubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		msg := fmt.Sprintf("cannot list nodes: %v", err)
+		klog.V(l2).InfoS(msg, "component", "controller")
+
+		return -1, fmt.Errorf("%s: %w", failNodeListEnforceMessage, err)
+	}
+
+	violations := d.Violated(cache)
+	now := metav1.Now()
+	totalViolations := 0
+	patchErrs := ""
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		message, violating := violations[node.Name]
+		current := findCondition(node, ConditionType(d.PolicyName))
+
+		if violating {
+			totalViolations++
+		}
+
+		if !violating && current == nil {
+			continue
+		}
+
+		messageText, _ := message.(string)
+		condition := d.desiredCondition(current, violating, messageText, now)
+
+		if current != nil && current.Status == condition.Status && current.Message == condition.Message {
+			continue
+		}
+
+		klog.V(l2).InfoS("Setting node condition", "node", node.Name, "type", condition.Type,
+			"status", condition.Status, "component", "controller")
+
+		err := d.patchNodeStatus(node.Name, enforcer, condition)
+		if err != nil {
+			patchErrs = patchErrs + node.Name + "; "
+		}
+	}
+
+	if len(patchErrs) > 0 {
+		return totalViolations, fmt.Errorf("could not set condition on %v %w", patchErrs, errNull)
+	}
+
+	return totalViolations, nil
+}
+
+// Cleanup removes the policy node condition from all nodes when the policy is deleted.
+func (d *Strategy) Cleanup(enforcer *strategy.MetricEnforcer, policyName string) error {
+	nodes, err := enforcer.KubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		msg := fmt.Sprintf("cannot list nodes: %v", err)
+		klog.V(l2).InfoS(msg, "component", "controller")
+
+		return fmt.Errorf("%s: %w", failNodeListCleanUpMessage, err)
+	}
+
+	conditionType := ConditionType(policyName)
+
+	for i := range nodes.Items {
+		if findCondition(&nodes.Items[i], conditionType) == nil {
+			continue
+		}
+
+		deletion := map[string]interface{}{"type": conditionType, "$patch": "delete"}
+
+		err := d.patchNodeStatus(nodes.Items[i].Name, enforcer, deletion)
+		if err != nil {
+			klog.V(l2).InfoS(err.Error(), "component", "controller")
+		}
+	}
+
+	klog.V(l2).InfoS(fmt.Sprintf("Remove the node condition on policy %v deletion", policyName), "component", "controller")
+
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package nodecondition
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	telpol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+)
+
+func nodeWithCondition(name string, status v1.ConditionStatus, transition time.Time) *v1.Node {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if status != "" {
+		node.Status.Conditions = []v1.NodeCondition{{Type: ConditionType("condition-test"), Status: status,
+			Message:            "node violates condition-test: temperature GreaterThan 90 (value 95)",
+			LastTransitionTime: metav1.NewTime(transition)}}
+	}
+
+	return node
+}
+
+func TestNodeConditionStrategy_Enforce(t *testing.T) {
+	before := time.Now().Add(-time.Hour).Truncate(time.Second)
+	rules := []telpol.TASPolicyRule{{Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("90")}}
+
+	tests := []struct {
+		name           string
+		node           *v1.Node
+		value          int64
+		wantStatus     v1.ConditionStatus
+		wantMessage    string
+		wantTransition bool
+	}{
+		{name: "violating node gets condition",
+			node: nodeWithCondition("node-1", "", before), value: 100,
+			wantStatus: v1.ConditionTrue, wantMessage: "temperature GreaterThan 90 (value 100)", wantTransition: true},
+		{name: "compliant node without condition is untouched",
+			node: nodeWithCondition("node-1", "", before), value: 50},
+		{name: "violating node keeps transition time",
+			node: nodeWithCondition("node-1", v1.ConditionTrue, before), value: 95,
+			wantStatus: v1.ConditionTrue, wantMessage: "(value 95)"},
+		{name: "message updated with new value",
+			node: nodeWithCondition("node-1", v1.ConditionTrue, before), value: 99,
+			wantStatus: v1.ConditionTrue, wantMessage: "(value 99)"},
+		{name: "node recovers",
+			node: nodeWithCondition("node-1", v1.ConditionTrue, before), value: 10,
+			wantStatus: v1.ConditionFalse, wantMessage: "complies", wantTransition: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Strategy{PolicyName: "condition-test", Rules: rules}
+			client := testclient.NewSimpleClientset(tt.node)
+			enforcer := strategy.NewEnforcer(client)
+			mockCache := cache.MockEmptySelfUpdatingCache()
+
+			err := mockCache.WriteMetric("temperature", metrics.NodeMetricsInfo{tt.node.Name: {Timestamp: time.Now(), Window: 1,
+				Value: *resource.NewQuantity(tt.value, resource.DecimalSI)}})
+			if err != nil {
+				t.Errorf("Cannot write metric to mock cache for test: %v", err)
+			}
+
+			if _, err := d.Enforce(enforcer, mockCache); err != nil {
+				t.Errorf("Unexpected error from Enforce: %v", err)
+
+				return
+			}
+
+			node, err := client.CoreV1().Nodes().Get(context.TODO(), tt.node.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Errorf("Cannot get node: %v", err)
+
+				return
+			}
+
+			condition := findCondition(node, ConditionType(d.PolicyName))
+			if tt.wantStatus == "" {
+				if condition != nil {
+					t.Errorf("Unexpected condition %v", condition)
+				}
+
+				return
+			}
+
+			if condition == nil {
+				t.Errorf("Condition not found in %v", node.Status.Conditions)
+
+				return
+			}
+
+			if condition.Status != tt.wantStatus || !strings.Contains(condition.Message, tt.wantMessage) {
+				t.Errorf("Got condition %v %q, want %v %q", condition.Status, condition.Message, tt.wantStatus, tt.wantMessage)
+			}
+
+			if transitioned := !condition.LastTransitionTime.Time.Equal(before); transitioned != tt.wantTransition {
+				t.Errorf("Transition time changed: %v, want %v", transitioned, tt.wantTransition)
+			}
+		})
+	}
+}
+
+func TestNodeConditionStrategy_Cleanup(t *testing.T) {
+	d := &Strategy{PolicyName: "condition-test"}
+	client := testclient.NewSimpleClientset(nodeWithCondition("node-1", v1.ConditionTrue, time.Now()))
+
+	err := d.Cleanup(strategy.NewEnforcer(client), d.PolicyName)
+	if err != nil {
+		t.Errorf("Unexpected error from Cleanup: %v", err)
+	}
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+	if condition := findCondition(node, ConditionType(d.PolicyName)); condition != nil {
+		t.Errorf("Condition not removed: %v", condition)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nodecondition provides the nodecondition strategy. Violation conditions are shared with the deschedule strategy.
+// When a node is violating the strategy, the enforcer sets a custom NodeCondition on the node status. The condition
+// carries the violated rules with their metric values and transition times, which labels can't hold.
+package nodecondition
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/deschedule"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+)
+
+// StrategyType is set to "nodecondition".
+const (
+	StrategyType = "nodecondition"
+	l2           = 2
+	l4           = 4
+)
+
+// Strategy type for setting node conditions from a single policy.
+type Strategy telempol.TASPolicyStrategy
+
+func init() {
+	core.Register(StrategyType, func(policy telempol.TASPolicyStrategy) core.Interface {
+		str := Strategy(policy)
+
+		return &str
+	})
+}
+
+// StrategyType returns the name of the strategy type. This is used to place it in the registry.
+func (d *Strategy) StrategyType() string {
+	return StrategyType
+}
+
+// Violated returns the nodes violating the strategy. The evaluation is the same as for deschedule.
+// Each node is mapped to a human-readable description of the rules it breaks and the metric values breaking them.
+func (d *Strategy) Violated(cache cache.Reader) map[string]interface{} {
+	violatingNodes := map[string]interface{}{}
+
+	for nodeName, results := range core.Evaluate(telempol.TASPolicyStrategy(*d), cache) {
+		rules := make([]string, 0, len(results))
+		for _, result := range results {
+			rules = append(rules, fmt.Sprintf("%v %v %v (value %v)", core.RuleName(result.Rule), result.Rule.Operator,
+				core.TargetString(result.Rule), result.Value.String()))
+		}
+
+		sort.Strings(rules)
+		violatingNodes[nodeName] = strings.Join(rules, "; ")
+	}
+
+	return violatingNodes
+}
+
+// Equals checks if a strategy is the same as the passed strategy.
+// It can be used to prevent duplication of strategies in the API and is also used to find strategies for deletion.
+func (d *Strategy) Equals(other core.Interface) bool {
+	otherConditionStrategy, ok := other.(*Strategy)
+	if !ok {
+		return false
+	}
+
+	return (*deschedule.Strategy)(d).Equals((*deschedule.Strategy)(otherConditionStrategy))
+}
+
+// GetPolicyName returns the name of the policy that originated strategy.
+func (d *Strategy) GetPolicyName() string {
+	return d.PolicyName
+}
+
+// GetActiveWindows returns the time windows in which the strategy applies.
+func (d *Strategy) GetActiveWindows() []telempol.TASPolicyActiveWindow {
+	return d.ActiveWindows
+}
+
+// SetPolicyName adds a policy name to be associated with this strategy.
+func (d *Strategy) SetPolicyName(name string) {
+	d.PolicyName = name
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/klog/v2"
+)
+
+// violationKey identifies a rule broken on a node.
+type violationKey struct {
+	node string
+	rule string
+}
+
+// violationTracker remembers, per policy, which rules were violated on which nodes at the last enforcement.
+type violationTracker struct {
+	policies map[string]map[violationKey]string
+	sync.Mutex
+}
+
+var tracker = newViolationTracker()
+
+func newViolationTracker() *violationTracker {
+	return &violationTracker{policies: map[string]map[violationKey]string{}}
+}
+
+// transitions updates the tracked violations of the policy and returns an event for each change. Events are stamped
+// with the timestamp of the metric the rule was evaluated on, or with now for rules no longer evaluated on the node.
+func (t *violationTracker) transitions(policyName string, current map[violationKey]string,
+	timestamps map[violationKey]time.Time, now time.Time) []Event {
+	t.Lock()
+	defer t.Unlock()
+
+	events := []Event{}
+	previous := t.policies[policyName]
+
+	timestamp := func(key violationKey) time.Time {
+		if evaluatedAt, ok := timestamps[key]; ok {
+			return evaluatedAt
+		}
+
+		return now
+	}
+
+	for key, value := range current {
+		if _, ok := previous[key]; !ok {
+			events = append(events, Event{Policy: policyName, Node: key.node, Rule: key.rule, Value: value, Violating: true,
+				Timestamp: timestamp(key)})
+		}
+	}
+
+	for key, value := range previous {
+		if _, ok := current[key]; !ok {
+			events = append(events, Event{Policy: policyName, Node: key.node, Rule: key.rule, Value: value, Violating: false,
+				Timestamp: timestamp(key)})
+		}
+	}
+
+	t.policies[policyName] = current
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Node != events[j].Node {
+			return events[i].Node < events[j].Node
+		}
+
+		return events[i].Rule < events[j].Rule
+	})
+
+	return events
+}
+
+// Enforce compares the current violations of the strategy with those found at the last enforcement.
+// An event is queued for the webhook for every rule a node started or stopped violating, stamped with the time of the
+// metric showing the change.
+// It returns the number of violating nodes.
+func (d *Strategy) Enforce(_ *strategy.MetricEnforcer, cache cache.Reader) (int, error) {
+	hook := currentWebhook()
+	if hook == nil {
+		return -1, fmt.Errorf("cannot notify for %v: %w", d.PolicyName, errNotConfigured)
+	}
+
+	violatingNodes, current, timestamps := d.evaluate(cache)
+
+	for _, event := range tracker.transitions(d.PolicyName, current, timestamps, time.Now()) {
+		klog.V(l2).InfoS("Notifying", "policy", event.Policy, "node", event.Node, "rule", event.Rule,
+			"violating", event.Violating, "component", "controller")
+		hook.queue(event)
+	}
+
+	return len(violatingNodes), nil
+}
+
+// evaluate returns the nodes violating the strategy, the value of each rule violated on a node and the timestamp of
+// the metric each rule was evaluated on for each node, violated or not.
+func (d *Strategy) evaluate(cache cache.Reader) (map[string]interface{}, map[violationKey]string, map[violationKey]time.Time) {
+	violatingNodes := map[string]interface{}{}
+	current := map[violationKey]string{}
+	timestamps := map[violationKey]time.Time{}
+
+	for nodeName, evaluation := range strategy.EvaluateNodes(telempol.TASPolicyStrategy(*d), cache) {
+		for _, rule := range evaluation.Rules {
+			if rule.Metric == nil {
+				continue
+			}
+
+			key := violationKey{node: nodeName, rule: ruleToString(rule.Rule)}
+			timestamps[key] = rule.Metric.Timestamp
+
+			if evaluation.Violated && rule.Violated {
+				current[key] = rule.Value.String()
+			}
+		}
+
+		if evaluation.Violated {
+			violatingNodes[nodeName] = nil
+		}
+	}
+
+	return violatingNodes, current, timestamps
+}
+
+// Cleanup sends a final event for every violation still tracked for the removed policy.
+func (d *Strategy) Cleanup(_ *strategy.MetricEnforcer, policyName string) error {
+	events := tracker.transitions(policyName, map[violationKey]string{}, nil, time.Now())
+
+	tracker.Lock()
+	delete(tracker.policies, policyName)
+	tracker.Unlock()
+
+	hook := currentWebhook()
+	if hook == nil {
+		return nil
+	}
+
+	for _, event := range events {
+		hook.queue(event)
+	}
+
+	klog.V(l2).InfoS(fmt.Sprintf("Cleared notifications on policy %v deletion", policyName), "component", "controller")
+
+	return nil
+}
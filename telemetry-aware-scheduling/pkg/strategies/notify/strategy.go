@@ -0,0 +1,76 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package notify provides the notify strategy. Violation conditions are shared with the deschedule strategy.
+// When a node enters or leaves violation of one of the strategy rules, the enforcer posts an event to a webhook.
+package notify
+
+import (
+	"fmt"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/deschedule"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+)
+
+// StrategyType is set to "notify".
+const (
+	StrategyType = "notify"
+	l2           = 2
+)
+
+// Strategy type for webhook notifications from a single policy.
+type Strategy telempol.TASPolicyStrategy
+
+func init() {
+	core.Register(StrategyType, func(policy telempol.TASPolicyStrategy) core.Interface {
+		str := Strategy(policy)
+
+		return &str
+	})
+}
+
+// StrategyType returns the name of the strategy type. This is used to place it in the registry.
+func (d *Strategy) StrategyType() string {
+	return StrategyType
+}
+
+// Violated returns the nodes violating the strategy, each mapped to nil. The evaluation is the same as for deschedule
+// and is shared with Enforce.
+func (d *Strategy) Violated(cache cache.Reader) map[string]interface{} {
+	violatingNodes, _, _ := d.evaluate(cache)
+
+	return violatingNodes
+}
+
+// ruleToString returns the rule passed to it as a single string.
+func ruleToString(rule telempol.TASPolicyRule) string {
+	return fmt.Sprintf("%v %v %v", core.RuleName(rule), rule.Operator, core.TargetString(rule))
+}
+
+// Equals checks if a strategy is the same as the passed strategy.
+// It can be used to prevent duplication of strategies in the API and is also used to find strategies for deletion.
+func (d *Strategy) Equals(other core.Interface) bool {
+	otherNotifyStrategy, ok := other.(*Strategy)
+	if !ok {
+		return false
+	}
+
+	return (*deschedule.Strategy)(d).Equals((*deschedule.Strategy)(otherNotifyStrategy))
+}
+
+// GetPolicyName returns the name of the policy that originated strategy.
+func (d *Strategy) GetPolicyName() string {
+	return d.PolicyName
+}
+
+// GetActiveWindows returns the time windows in which the strategy applies.
+func (d *Strategy) GetActiveWindows() []telempol.TASPolicyActiveWindow {
+	return d.ActiveWindows
+}
+
+// SetPolicyName adds a policy name to be associated with this strategy.
+func (d *Strategy) SetPolicyName(name string) {
+	d.PolicyName = name
+}
@@ -0,0 +1,348 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SignatureHeader holds the hex encoded HMAC-SHA256 of the request body, prefixed by "sha256=".
+	SignatureHeader      = "X-TAS-Signature"
+	signaturePrefix      = "sha256="
+	defaultRetries       = 3
+	defaultRetryBackoff  = time.Second
+	defaultBatchSize     = 50
+	defaultBatchInterval = 5 * time.Second
+	defaultTimeout       = 10 * time.Second
+	eventQueueLength     = 1000
+	// flushTimeout bounds the delivery of the events still pending when the webhook stops.
+	flushTimeout = 10 * time.Second
+)
+
+var (
+	errNotConfigured = errors.New("notify webhook not configured")
+	errStatus        = errors.New("webhook returned unexpected status")
+	errCACert        = errors.New("no certificates found in CA file")
+	errConfig        = errors.New("invalid notify webhook configuration")
+)
+
+// Config holds the webhook settings shared by all notify strategies. There's a single webhook, so the events of every
+// policy with a notify strategy are posted to the same URL and receivers tell them apart by their policy.
+type Config struct {
+	// URL is the endpoint receiving the events.
+	URL string
+	// Secret is the key used to sign each request body with HMAC-SHA256. Requests are not signed if it's empty.
+	Secret []byte
+	// CertFile and KeyFile are an optional client certificate presented to the webhook.
+	CertFile string
+	KeyFile  string
+	// CAFile optionally replaces the system roots for verifying the webhook server certificate.
+	CAFile string
+	// Retries is the number of extra attempts made for a failed batch. RetryBackoff doubles after each attempt.
+	Retries      int
+	RetryBackoff time.Duration
+	// BatchSize is the maximum number of events in one request. BatchInterval is how long events are buffered.
+	BatchSize     int
+	BatchInterval time.Duration
+	Timeout       time.Duration
+}
+
+// DefaultConfig returns the webhook settings used for any value left unset by the flags.
+func DefaultConfig() Config {
+	return Config{
+		Retries:       defaultRetries,
+		RetryBackoff:  defaultRetryBackoff,
+		BatchSize:     defaultBatchSize,
+		BatchInterval: defaultBatchInterval,
+		Timeout:       defaultTimeout,
+	}
+}
+
+// Event describes a node entering or leaving violation of a single rule. Value is the value violating the rule and
+// Timestamp the time of the metric showing the change, or of the enforcement when the node no longer has the metric.
+type Event struct {
+	Policy    string    `json:"policy"`
+	Node      string    `json:"node"`
+	Rule      string    `json:"rule"`
+	Value     string    `json:"value"`
+	Violating bool      `json:"violating"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Payload is the JSON body posted to the webhook.
+type Payload struct {
+	Events []Event `json:"events"`
+}
+
+// webhook batches events and delivers them to the configured URL. done is closed once it stopped.
+type webhook struct {
+	config Config
+	client *http.Client
+	events chan Event
+	done   chan struct{}
+}
+
+var (
+	sender   *webhook
+	senderMu sync.RWMutex
+)
+
+// Start configures the webhook and starts delivering events until the context is done. The events still pending then
+// are delivered within the flush timeout. It returns an error if the batching or retry settings are invalid.
+func Start(ctx context.Context, config Config) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return err
+	}
+
+	hook := newWebhook(config, client)
+
+	senderMu.Lock()
+	sender = hook
+	senderMu.Unlock()
+
+	go hook.run(ctx)
+
+	return nil
+}
+
+// validate checks that every batch gets at least one attempt and that events are batched.
+func (c Config) validate() error {
+	switch {
+	case c.Retries < 0:
+		return fmt.Errorf("%w: negative retries %d", errConfig, c.Retries)
+	case c.BatchSize <= 0:
+		return fmt.Errorf("%w: batch size %d isn't positive", errConfig, c.BatchSize)
+	case c.BatchInterval <= 0:
+		return fmt.Errorf("%w: batch interval %v isn't positive", errConfig, c.BatchInterval)
+	}
+
+	return nil
+}
+
+// Wait returns once the webhook started by Start delivered the events pending when its context was done, or right away
+// if it wasn't started.
+func Wait() {
+	if hook := currentWebhook(); hook != nil {
+		<-hook.done
+	}
+}
+
+// currentWebhook returns the configured webhook or nil if Start hasn't been called.
+func currentWebhook() *webhook {
+	senderMu.RLock()
+	defer senderMu.RUnlock()
+
+	return sender
+}
+
+// newHTTPClient returns a client using the optional client certificate and CA from the config.
+func newHTTPClient(config Config) (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load notify client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if config.CAFile != "" {
+		caCert, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read notify CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("%w: %v", errCACert, config.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+	}, nil
+}
+
+func newWebhook(config Config, client *http.Client) *webhook {
+	return &webhook{
+		config: config,
+		client: client,
+		events: make(chan Event, eventQueueLength),
+		done:   make(chan struct{}),
+	}
+}
+
+// queue adds an event to the next batch. Events are dropped if the queue is full.
+func (w *webhook) queue(event Event) {
+	select {
+	case w.events <- event:
+	default:
+		klog.V(l2).InfoS("notify queue full, dropping event for "+event.Node, "component", "controller")
+	}
+}
+
+// run collects events and posts them in batches, either when a batch is full or when the batch interval ends.
+// Once the context is done the pending events are flushed, including a batch whose delivery it interrupted.
+func (w *webhook) run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.BatchInterval)
+	defer ticker.Stop()
+	defer close(w.done)
+
+	batch := []Event{}
+
+	for {
+		select {
+		case event := <-w.events:
+			batch = append(batch, event)
+			if len(batch) >= w.config.BatchSize && w.deliver(ctx, batch) {
+				batch = []Event{}
+			}
+		case <-ticker.C:
+			if len(batch) > 0 && w.deliver(ctx, batch) {
+				batch = []Event{}
+			}
+		case <-ctx.Done():
+			w.flush(batch)
+
+			return
+		}
+	}
+}
+
+// flush delivers the batch and the queued events, in batches of the batch size, giving up after the flush timeout.
+func (w *webhook) flush(batch []Event) {
+	for queued := true; queued; {
+		select {
+		case event := <-w.events:
+			batch = append(batch, event)
+		default:
+			queued = false
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	for len(batch) > 0 {
+		size := min(w.config.BatchSize, len(batch))
+
+		if !w.deliver(ctx, batch[:size]) {
+			break
+		}
+
+		batch = batch[size:]
+	}
+
+	if len(batch) > 0 {
+		klog.V(l2).InfoS(fmt.Sprintf("dropping %d notify events not delivered before the flush timeout", len(batch)),
+			"component", "controller")
+	}
+}
+
+// deliver posts the batch, retrying with an exponential backoff. The batch is dropped when all attempts fail.
+// It returns false, with the batch neither delivered nor dropped, if the context is done first.
+func (w *webhook) deliver(ctx context.Context, batch []Event) bool {
+	backoff := w.config.RetryBackoff
+
+	for attempt := 0; attempt <= w.config.Retries; attempt++ {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		err := w.post(ctx, batch)
+		if err == nil {
+			return true
+		}
+
+		klog.V(l2).InfoS("notify attempt failed: "+err.Error(), "attempt", attempt+1, "component", "controller")
+
+		if attempt == w.config.Retries {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return false
+		}
+
+		backoff *= 2
+	}
+
+	if ctx.Err() != nil {
+		return false
+	}
+
+	klog.V(l2).InfoS(fmt.Sprintf("dropping %d notify events after %d attempts", len(batch), w.config.Retries+1),
+		"component", "controller")
+
+	return true
+}
+
+// post sends a single signed request containing the batch.
+func (w *webhook) post(ctx context.Context, batch []Event) error {
+	body, err := json.Marshal(Payload{Events: batch})
+	if err != nil {
+		return fmt.Errorf("failed to encode notify payload: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notify request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	if len(w.config.Secret) > 0 {
+		request.Header.Set(SignatureHeader, Sign(w.config.Secret, body))
+	}
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to post notify request: %w", err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %v", errStatus, response.Status)
+	}
+
+	return nil
+}
+
+// Sign returns the value of the signature header for the given body.
+// Receivers can compute the same value with the shared secret to authenticate requests.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
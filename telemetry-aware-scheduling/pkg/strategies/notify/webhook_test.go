@@ -0,0 +1,307 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	telpol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// receiver records the payloads posted to a test webhook. The first failures requests are answered with an error.
+type receiver struct {
+	payloads   []Payload
+	signatures []string
+	requests   int
+	failures   int
+	sync.Mutex
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.requests++
+	if r.requests <= r.failures {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	payload := Payload{}
+	_ = json.Unmarshal(body, &payload)
+
+	r.payloads = append(r.payloads, payload)
+	r.signatures = append(r.signatures, req.Header.Get(SignatureHeader))
+
+	if req.Header.Get(SignatureHeader) != Sign([]byte("secret"), body) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+}
+
+func testConfig(url string) Config {
+	return Config{URL: url, Secret: []byte("secret"), Retries: 2, RetryBackoff: time.Millisecond,
+		BatchSize: 2, BatchInterval: time.Hour, Timeout: time.Second}
+}
+
+func TestStart(t *testing.T) {
+	tests := []struct {
+		name    string
+		update  func(*Config)
+		wantErr bool
+	}{
+		{"valid", func(*Config) {}, false},
+		{"no retries", func(c *Config) { c.Retries = 0 }, false},
+		{"negative retries", func(c *Config) { c.Retries = -1 }, true},
+		{"zero batch size", func(c *Config) { c.BatchSize = 0 }, true},
+		{"negative batch size", func(c *Config) { c.BatchSize = -1 }, true},
+		{"zero batch interval", func(c *Config) { c.BatchInterval = 0 }, true},
+		{"negative batch interval", func(c *Config) { c.BatchInterval = -time.Second }, true},
+	}
+
+	defer func() {
+		senderMu.Lock()
+		sender = nil
+		senderMu.Unlock()
+	}()
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			config := testConfig("http://localhost")
+			tt.update(&config)
+
+			err := Start(ctx, config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Start() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr && !errors.Is(err, errConfig) {
+				t.Errorf("Start() error = %v, want %v", err, errConfig)
+			}
+		})
+	}
+}
+
+func TestWebhook_deliver(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		wantRequests int
+		wantPayloads int
+	}{
+		{name: "delivered first time", failures: 0, wantRequests: 1, wantPayloads: 1},
+		{name: "delivered after retries", failures: 2, wantRequests: 3, wantPayloads: 1},
+		{name: "dropped after all retries fail", failures: 5, wantRequests: 3, wantPayloads: 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &receiver{failures: tt.failures}
+			server := httptest.NewServer(rec)
+			defer server.Close()
+
+			config := testConfig(server.URL)
+			client, err := newHTTPClient(config)
+			if err != nil {
+				t.Errorf("Unexpected error creating client: %v", err)
+
+				return
+			}
+
+			hook := newWebhook(config, client)
+			hook.deliver(context.Background(), []Event{{Policy: "notify-test", Node: "node-1", Violating: true}})
+
+			if rec.requests != tt.wantRequests || len(rec.payloads) != tt.wantPayloads {
+				t.Errorf("Got %d requests and %d payloads, want %d and %d",
+					rec.requests, len(rec.payloads), tt.wantRequests, tt.wantPayloads)
+			}
+		})
+	}
+}
+
+func TestWebhook_run(t *testing.T) {
+	rec := &receiver{}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	config := testConfig(server.URL)
+	client, _ := newHTTPClient(config)
+	hook := newWebhook(config, client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go hook.run(ctx)
+
+	for _, node := range []string{"node-1", "node-2", "node-3", "node-4"} {
+		hook.queue(Event{Policy: "notify-test", Node: node, Violating: true})
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		rec.Lock()
+		done := len(rec.payloads) == 2
+		rec.Unlock()
+
+		if done {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rec.Lock()
+	defer rec.Unlock()
+
+	if len(rec.payloads) != 2 {
+		t.Errorf("Got %d batches, want 2", len(rec.payloads))
+
+		return
+	}
+
+	for i, payload := range rec.payloads {
+		if len(payload.Events) != config.BatchSize || rec.signatures[i] == "" {
+			t.Errorf("Unexpected batch %v with signature %q", payload.Events, rec.signatures[i])
+		}
+	}
+}
+
+func TestWebhook_runFlush(t *testing.T) {
+	rec := &receiver{}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	config := testConfig(server.URL)
+	client, _ := newHTTPClient(config)
+	hook := newWebhook(config, client)
+
+	for _, node := range []string{"node-1", "node-2", "node-3"} {
+		hook.queue(Event{Policy: "notify-test", Node: node, Violating: true})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	go hook.run(ctx)
+
+	select {
+	case <-hook.done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Webhook didn't stop")
+	}
+
+	rec.Lock()
+	defer rec.Unlock()
+
+	events := 0
+	for _, payload := range rec.payloads {
+		events += len(payload.Events)
+	}
+
+	if events != 3 || len(rec.payloads) != 2 {
+		t.Errorf("Got %d events in %d batches after stopping, want 3 in 2", events, len(rec.payloads))
+	}
+}
+
+func TestNotifyStrategy_Enforce(t *testing.T) {
+	d := &Strategy{PolicyName: "notify-test", Rules: []telpol.TASPolicyRule{
+		{Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("90")},
+	}}
+	mockCache := cache.MockEmptySelfUpdatingCache()
+	hook := newWebhook(testConfig(""), http.DefaultClient)
+
+	senderMu.Lock()
+	sender = hook
+	senderMu.Unlock()
+
+	defer func() {
+		senderMu.Lock()
+		sender = nil
+		senderMu.Unlock()
+	}()
+
+	steps := []struct {
+		name   string
+		values map[string]int64
+		want   []Event
+	}{
+		{name: "node enters violation", values: map[string]int64{"node-1": 95, "node-2": 50},
+			want: []Event{{Node: "node-1", Value: "95", Violating: true}}},
+		{name: "no change", values: map[string]int64{"node-1": 99, "node-2": 50}},
+		{name: "one node leaves and another enters", values: map[string]int64{"node-1": 50, "node-2": 91},
+			want: []Event{{Node: "node-1", Value: "99", Violating: false}, {Node: "node-2", Value: "91", Violating: true}}},
+	}
+
+	for i, step := range steps {
+		timestamp := time.Date(2024, time.January, 8, 12, i, 0, 0, time.UTC)
+		nodeMetrics := metrics.NodeMetricsInfo{}
+
+		for node, value := range step.values {
+			nodeMetrics[node] = metrics.NodeMetric{Timestamp: timestamp, Window: 1,
+				Value: *resource.NewQuantity(value, resource.DecimalSI)}
+		}
+
+		if err := mockCache.WriteMetric("temperature", nodeMetrics); err != nil {
+			t.Errorf("Cannot write metric to mock cache for test: %v", err)
+		}
+
+		if _, err := d.Enforce(nil, mockCache); err != nil {
+			t.Errorf("%v: unexpected error from Enforce: %v", step.name, err)
+		}
+
+		got := drain(hook)
+		if len(got) != len(step.want) {
+			t.Errorf("%v: got events %v, want %v", step.name, got, step.want)
+
+			continue
+		}
+
+		for i := range got {
+			if got[i].Node != step.want[i].Node || got[i].Value != step.want[i].Value ||
+				got[i].Violating != step.want[i].Violating || got[i].Rule != "temperature GreaterThan 90" ||
+				!got[i].Timestamp.Equal(timestamp) {
+				t.Errorf("%v: got event %v, want %v", step.name, got[i], step.want[i])
+			}
+		}
+	}
+
+	if err := d.Cleanup(nil, d.PolicyName); err != nil {
+		t.Errorf("Unexpected error from Cleanup: %v", err)
+	}
+
+	if got := drain(hook); len(got) != 1 || got[0].Node != "node-2" || got[0].Violating {
+		t.Errorf("Got cleanup events %v, want node-2 leaving violation", got)
+	}
+}
+
+// drain returns all events currently queued on the webhook.
+func drain(hook *webhook) []Event {
+	events := []Event{}
+
+	for {
+		select {
+		case event := <-hook.events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
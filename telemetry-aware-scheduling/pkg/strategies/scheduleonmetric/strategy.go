@@ -6,14 +6,24 @@
 package scheduleonmetric
 
 import (
+	"reflect"
+
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
-	telemetryPolicyV1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telemetryPolicyV1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 )
 
 // Strategy represents the TAS policy strategies.
 type Strategy telemetryPolicyV1.TASPolicyStrategy
 
+func init() {
+	core.Register(StrategyType, func(policy telemetryPolicyV1.TASPolicyStrategy) core.Interface {
+		str := Strategy(policy)
+
+		return &str
+	})
+}
+
 // StrategyType is set to schedule.
 const (
 	StrategyType = "scheduleonmetric"
@@ -40,27 +50,10 @@ func (d *Strategy) StrategyType() string {
 // This (like the equal method under the other strategy, is a naive implementation which could be expanded.
 func (d *Strategy) Equals(other core.Interface) bool {
 	otherScheduleOnMetricStrategy, ok := other.(*Strategy)
-	sameName := other.GetPolicyName() == d.GetPolicyName()
-
-	if ok && sameName && len(d.Rules) > 0 && len(d.Rules) == len(otherScheduleOnMetricStrategy.Rules) {
-		for i, rule := range d.Rules {
-			if rule.Metricname != otherScheduleOnMetricStrategy.Rules[i].Metricname {
-				return false
-			}
 
-			if rule.Target != otherScheduleOnMetricStrategy.Rules[i].Target {
-				return false
-			}
-
-			if rule.Operator != otherScheduleOnMetricStrategy.Rules[i].Operator {
-				return false
-			}
-		}
-
-		return true
-	}
-
-	return false
+	return ok && other.GetPolicyName() == d.GetPolicyName() && len(d.Rules) > 0 &&
+		core.EqualRules(telemetryPolicyV1.TASPolicyStrategy(*d), telemetryPolicyV1.TASPolicyStrategy(*otherScheduleOnMetricStrategy)) &&
+		reflect.DeepEqual(d.ActiveWindows, otherScheduleOnMetricStrategy.ActiveWindows)
 }
 
 // GetPolicyName returns the policy name associated with this strategy.
@@ -68,6 +61,11 @@ func (d *Strategy) GetPolicyName() string {
 	return d.PolicyName
 }
 
+// GetActiveWindows returns the time windows in which the strategy applies.
+func (d *Strategy) GetActiveWindows() []telemetryPolicyV1.TASPolicyActiveWindow {
+	return d.ActiveWindows
+}
+
 // SetPolicyName sets the policy name for this strategy.
 func (d *Strategy) SetPolicyName(policyName string) {
 	d.PolicyName = policyName
@@ -43,9 +43,14 @@ type TASPolicySpec struct {
 	Strategies map[string]TASPolicyStrategy `json:"strategies"`
 }
 
-// TASPolicyStatus defines the observed state of TASpolicy. Currently no status object is implemented.
-// TODO: Implement policy status object.
+// TASPolicyStatus defines the observed state of TASpolicy as seen by the TAS controller.
 type TASPolicyStatus struct {
+	// Compliance is "Invalid" when some of the policy strategies can't be enforced and empty otherwise.
+	Compliance string `json:"compliance,omitempty"`
+	// Message describes why the policy is not fully enforced.
+	Message string `json:"message,omitempty"`
+	// UnknownStrategies lists the strategy types in the policy which have no registered implementation.
+	UnknownStrategies []string `json:"unknownStrategies,omitempty"`
 }
 
 // TASPolicyList contains a list of TASpolicy.
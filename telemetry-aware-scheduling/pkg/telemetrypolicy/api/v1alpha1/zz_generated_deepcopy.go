@@ -13,7 +13,7 @@ func (in *TASPolicy) DeepCopyInto(out *TASPolicy) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicy.
@@ -96,6 +96,11 @@ func (in *TASPolicySpec) DeepCopy() *TASPolicySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TASPolicyStatus) DeepCopyInto(out *TASPolicyStatus) {
 	*out = *in
+	if in.UnknownStrategies != nil {
+		in, out := &in.UnknownStrategies, &out.UnknownStrategies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyStatus.
@@ -0,0 +1,52 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ConvertFromV1alpha1 returns the v1beta1 version of a v1alpha1 policy with defaults set.
+// Every v1alpha1 policy can be converted. There is no conversion back: v1alpha1 is deprecated and the CRD serves it
+// without conversion, so it's only meant to create policies written for it, which are read as v1beta1.
+func ConvertFromV1alpha1(in *v1alpha1.TASPolicy) *TASPolicy {
+	out := &TASPolicy{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Status:     TASPolicyStatus{Compliance: in.Status.Compliance, Message: in.Status.Message},
+	}
+	out.APIVersion = Group + "/" + Version
+
+	if in.Status.UnknownStrategies != nil {
+		out.Status.UnknownStrategies = append([]string{}, in.Status.UnknownStrategies...)
+	}
+
+	if in.Spec.Strategies != nil {
+		out.Spec.Strategies = make(map[string]TASPolicyStrategy, len(in.Spec.Strategies))
+	}
+
+	for name, strategy := range in.Spec.Strategies {
+		rules := make([]TASPolicyRule, 0, len(strategy.Rules))
+
+		for _, rule := range strategy.Rules {
+			rules = append(rules, TASPolicyRule{
+				Metricname: rule.Metricname,
+				Operator:   Operator(rule.Operator),
+				Labels:     append([]string(nil), rule.Labels...),
+				Target:     *resource.NewQuantity(rule.Target, resource.DecimalSI),
+			})
+		}
+
+		out.Spec.Strategies[name] = TASPolicyStrategy{
+			PolicyName:      strategy.PolicyName,
+			LogicalOperator: LogicalOperator(strategy.LogicalOperator),
+			Rules:           rules,
+		}
+	}
+
+	SetDefaults(out)
+
+	return out
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConvertFromV1alpha1(t *testing.T) {
+	in := &v1alpha1.TASPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "conversion-test", Namespace: "default"},
+		Spec: v1alpha1.TASPolicySpec{Strategies: map[string]v1alpha1.TASPolicyStrategy{
+			"dontschedule": {Rules: []v1alpha1.TASPolicyRule{{Metricname: "temperature", Operator: "GreaterThan", Target: 90}}},
+			"labeling": {LogicalOperator: "allOf", Rules: []v1alpha1.TASPolicyRule{
+				{Metricname: "temperature", Operator: "LessThan", Target: -1, Labels: []string{"cold=true"}}}},
+		}},
+		Status: v1alpha1.TASPolicyStatus{Compliance: "Invalid", UnknownStrategies: []string{"reschedule"}},
+	}
+	want := &TASPolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: Group + "/" + Version},
+		ObjectMeta: metav1.ObjectMeta{Name: "conversion-test", Namespace: "default"},
+		Spec: TASPolicySpec{Strategies: map[string]TASPolicyStrategy{
+			"dontschedule": {LogicalOperator: AnyOf, Rules: []TASPolicyRule{
+				{Metricname: "temperature", Operator: GreaterThan, Target: resource.MustParse("90")}}},
+			"labeling": {LogicalOperator: AllOf, Rules: []TASPolicyRule{
+				{Metricname: "temperature", Operator: LessThan, Target: resource.MustParse("-1"), Labels: []string{"cold=true"}}}},
+		}},
+		Status: TASPolicyStatus{Compliance: "Invalid", UnknownStrategies: []string{"reschedule"}},
+	}
+
+	got := ConvertFromV1alpha1(in)
+	if !apiequality.Semantic.DeepEqual(got, want) {
+		t.Errorf("ConvertFromV1alpha1() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+// SetDefaults fills in the optional fields of the policy. It mirrors the defaults declared in the CRD schema, so
+// policies built in code or converted from v1alpha1 behave the same as those read from the API server.
+func SetDefaults(policy *TASPolicy) {
+	for name, strategy := range policy.Spec.Strategies {
+		if strategy.LogicalOperator == "" {
+			strategy.LogicalOperator = AnyOf
+		}
+
+		policy.Spec.Strategies[name] = strategy
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package v1beta1 describes the structure of the Telemetry Policy CRD.
+// Compared to v1alpha1, rule targets are quantities and operators are explicit enums.
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Defines key values for policy CRD.
+const (
+	Plural  = "taspolicies"
+	Group   = "telemetry.intel.com"
+	Version = "v1beta1"
+)
+
+// Operator is the comparison between a metric value and the rule target.
+type Operator string
+
+// Operators supported in policy rules. InRange and OutOfRange compare against the rule range instead of the target.
+const (
+	LessThan       Operator = "LessThan"
+	LessOrEqual    Operator = "LessOrEqual"
+	GreaterThan    Operator = "GreaterThan"
+	GreaterOrEqual Operator = "GreaterOrEqual"
+	Equals         Operator = "Equals"
+	NotEquals      Operator = "NotEquals"
+	InRange        Operator = "InRange"
+	OutOfRange     Operator = "OutOfRange"
+)
+
+// LogicalOperator defines how the results of the strategy rules are combined.
+type LogicalOperator string
+
+// Logical operators supported in policy strategies.
+const (
+	// AnyOf is violated when any of the rules is violated. It's the default.
+	AnyOf LogicalOperator = "anyOf"
+	// AllOf is violated when all of the rules are violated.
+	AllOf LogicalOperator = "allOf"
+)
+
+// Statistic is a value computed from the node population which relative rule targets are derived from.
+type Statistic string
+
+// The statistics relative rule targets can be derived from.
+const (
+	Mean       Statistic = "Mean"
+	Median     Statistic = "Median"
+	Percentile Statistic = "Percentile"
+)
+
+// TrendModel is how the metric history of a node is extrapolated by trend rules.
+type TrendModel string
+
+// The models trend rules can extrapolate with. Linear fits a line to the samples by least squares, ExponentialSmoothing
+// follows the level and trend of the samples with Holt's linear method, giving more weight to recent samples.
+const (
+	Linear               TrendModel = "Linear"
+	ExponentialSmoothing TrendModel = "ExponentialSmoothing"
+)
+
+// Aggregation folds the values of the series a rule metric has on a node into one, e.g. for metrics with a series per
+// GPU, socket or disk, or with a series per pod running on the node.
+type Aggregation string
+
+// The aggregations of the series of a node. Max is the default, Count is the number of series.
+const (
+	Max   Aggregation = "Max"
+	Min   Aggregation = "Min"
+	Sum   Aggregation = "Sum"
+	Avg   Aggregation = "Avg"
+	Count Aggregation = "Count"
+)
+
+// MetricScope is the kind of object rule metrics describe in the custom metrics API.
+type MetricScope string
+
+// Node metrics are read as they are, the default. Pod metrics are attributed to the node each pod runs on, so the
+// values of the pods on a node are its series.
+const (
+	NodeScope MetricScope = "Node"
+	PodScope  MetricScope = "Pod"
+)
+
+// TASPolicy is the Schema for the taspolicies API.
+type TASPolicy struct {
+	Status            TASPolicyStatus `json:"status,omitempty"`
+	Spec              TASPolicySpec   `json:"spec"`
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+// TASPolicyStrategy contains a set of TASPolicyRule which define the strategy.
+// Without a Group the rules are combined with the LogicalOperator. A Group combines named rules in nested groups and
+// takes precedence over the LogicalOperator. A strategy with ActiveWindows only applies within one of them.
+// FailClosed is only supported by dontschedule, which then filters out the nodes without a value for one of its rules.
+// PodSelector is only supported by evict, which then also evicts the pods it selects besides the labelled ones.
+// PolicyName and PolicyNamespace are set by TAS to the policy holding the strategy.
+type TASPolicyStrategy struct {
+	PolicyName      string                  `json:"policyName"`
+	PolicyNamespace string                  `json:"policyNamespace,omitempty"`
+	LogicalOperator LogicalOperator         `json:"logicalOperator,omitempty"`
+	Rules           []TASPolicyRule         `json:"rules"`
+	Group           *TASPolicyRuleGroup     `json:"group,omitempty"`
+	ActiveWindows   []TASPolicyActiveWindow `json:"activeWindows,omitempty"`
+	FailClosed      bool                    `json:"failClosed,omitempty"`
+	PodSelector     *metav1.LabelSelector   `json:"podSelector,omitempty"`
+}
+
+// TASPolicyActiveWindow is a daily period from Start to End, both as HH:MM in TimeZone, on the given Days.
+// Days are English weekday names, e.g. Monday, and default to every day. TimeZone is an IANA name such as
+// Europe/Dublin and defaults to UTC. A window whose End isn't after its Start ends on the next day, e.g. 22:00 to
+// 06:00, and belongs to the day it starts on.
+type TASPolicyActiveWindow struct {
+	Days     []string `json:"days,omitempty"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	TimeZone string   `json:"timeZone,omitempty"`
+}
+
+// TASPolicyRuleGroup is a node in a tree of rule groups. Exactly one of its fields is set: Rule names a rule of the
+// strategy, the others combine nested groups.
+type TASPolicyRuleGroup struct {
+	Rule    string               `json:"rule,omitempty"`
+	AnyOf   []TASPolicyRuleGroup `json:"anyOf,omitempty"`
+	AllOf   []TASPolicyRuleGroup `json:"allOf,omitempty"`
+	NoneOf  []TASPolicyRuleGroup `json:"noneOf,omitempty"`
+	AtLeast *TASPolicyAtLeast    `json:"atLeast,omitempty"`
+}
+
+// TASPolicyAtLeast holds when at least Count of its groups hold.
+type TASPolicyAtLeast struct {
+	Count int32                `json:"count"`
+	Of    []TASPolicyRuleGroup `json:"of"`
+}
+
+// TASPolicyRule contains the parameters for the strategy rule.
+// Target accepts any quantity, e.g. 90, 0.75 or 512Mi.
+// A rule reads either a single metric or evaluates a CEL expression over several metrics of a node, e.g.
+// metrics.power / metrics.power_limit > 0.9. Numeric expressions are compared to the target like a metric, bool
+// expressions take no operator and are violated when true.
+type TASPolicyRule struct {
+	// Name identifies the rule in the rule groups of the strategy.
+	Name       string              `json:"name,omitempty"`
+	Metricname string              `json:"metricname,omitempty"`
+	Expression string              `json:"expression,omitempty"`
+	Operator   Operator            `json:"operator,omitempty"`
+	Labels     []string            `json:"labels,omitempty"`
+	Target     resource.Quantity   `json:"target"`
+	Range      *TASPolicyRuleRange `json:"range,omitempty"`
+	// Relative replaces the target with one derived from the values all nodes have for the rule.
+	Relative *TASPolicyRuleRelative `json:"relative,omitempty"`
+	// Trend also compares the value each node is predicted to reach from its recent metric history.
+	Trend *TASPolicyRuleTrend `json:"trend,omitempty"`
+	// MetricSelector selects the series of the rule metrics by their labels in the custom metrics API.
+	MetricSelector *metav1.LabelSelector `json:"metricSelector,omitempty"`
+	// Aggregation folds the series a metric has on a node into the value of the node.
+	Aggregation Aggregation `json:"aggregation,omitempty"`
+	// Scope is whether the rule metrics describe nodes or the pods running on them.
+	Scope MetricScope `json:"scope,omitempty"`
+}
+
+// TASPolicyRuleTrend extrapolates the recent samples of the rule metric on each node. A node violates the rule when its
+// current value or the value predicted Horizon after its latest sample does, so nodes heading for the target violate
+// the rule before they reach it. Alpha and Beta are the level and trend smoothing factors of the ExponentialSmoothing
+// model, from 0 to 1 and 0.5 by default.
+type TASPolicyRuleTrend struct {
+	Model   TrendModel         `json:"model"`
+	Horizon metav1.Duration    `json:"horizon"`
+	Alpha   *resource.Quantity `json:"alpha,omitempty"`
+	Beta    *resource.Quantity `json:"beta,omitempty"`
+}
+
+// TASPolicyRuleRelative derives the rule target from the current values of all nodes when the rule is evaluated, as
+// Factor * Statistic + Deviations * the standard deviation of the values. Factor defaults to 1 and Deviations to 0,
+// e.g. Percentile 90 with GreaterThan is violated by the top 10% of nodes, Mean with Deviations 2 by nodes more than
+// two standard deviations above the mean and Median with Factor 1.2 by nodes more than 20% above the median.
+type TASPolicyRuleRelative struct {
+	Statistic Statistic `json:"statistic"`
+	// Percentile is used by the Percentile statistic, from 0 to 100.
+	Percentile int32              `json:"percentile,omitempty"`
+	Factor     *resource.Quantity `json:"factor,omitempty"`
+	Deviations *resource.Quantity `json:"deviations,omitempty"`
+}
+
+// TASPolicyRuleRange holds the bounds used by the InRange and OutOfRange operators. Both bounds belong to the range.
+type TASPolicyRuleRange struct {
+	Lower resource.Quantity `json:"lower"`
+	Upper resource.Quantity `json:"upper"`
+}
+
+// TASPolicySpec is a map of strategies indexed by their strategy type name i.e. scheduleonmetric, dontschedule.
+// Metrics defines metrics computed by TAS, which rules use by name like the metrics of the custom metrics API.
+type TASPolicySpec struct {
+	Strategies map[string]TASPolicyStrategy `json:"strategies"`
+	Metrics    []TASPolicyMetric            `json:"metrics,omitempty"`
+}
+
+// TASPolicyMetric is a metric computed on every metrics sync from the values of the Source metric or, for derived
+// metrics, from an arithmetic Expression over other metrics, e.g. metrics.mem_used / metrics.mem_total.
+// The value of each node is then multiplied by Scale, Offset is added and the result is clamped to Min and Max.
+// Metric names are shared by all policies, so policies defining the same metric need to define it the same way.
+type TASPolicyMetric struct {
+	Name       string             `json:"name"`
+	Source     string             `json:"source,omitempty"`
+	Expression string             `json:"expression,omitempty"`
+	Scale      *resource.Quantity `json:"scale,omitempty"`
+	Offset     *resource.Quantity `json:"offset,omitempty"`
+	Min        *resource.Quantity `json:"min,omitempty"`
+	Max        *resource.Quantity `json:"max,omitempty"`
+}
+
+// TASPolicyStatus defines the observed state of TASpolicy as seen by the TAS controller.
+type TASPolicyStatus struct {
+	// Compliance is "Invalid" when some of the policy strategies can't be enforced and empty otherwise.
+	Compliance string `json:"compliance,omitempty"`
+	// Message describes why the policy is not fully enforced.
+	Message string `json:"message,omitempty"`
+	// UnknownStrategies lists the strategy types in the policy which have no registered implementation.
+	UnknownStrategies []string `json:"unknownStrategies,omitempty"`
+	// Strategies shows whether each strategy with active windows currently applies.
+	Strategies []TASPolicyStrategyStatus `json:"strategies,omitempty"`
+	// TargetOverrides lists the effective targets of the nodes overriding a rule target with an annotation.
+	TargetOverrides []TASPolicyTargetOverride `json:"targetOverrides,omitempty"`
+}
+
+// TASPolicyStrategyStatus is the state of a strategy with active windows.
+type TASPolicyStrategyStatus struct {
+	Type   string `json:"type"`
+	Active bool   `json:"active"`
+}
+
+// TASPolicyTargetOverride is the target a node uses for a rule instead of the rule target.
+type TASPolicyTargetOverride struct {
+	Node   string            `json:"node"`
+	Rule   string            `json:"rule"`
+	Target resource.Quantity `json:"target"`
+}
+
+// TASPolicyList contains a list of TASpolicy.
+type TASPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TASPolicy `json:"items"`
+}
@@ -0,0 +1,455 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicy) DeepCopyInto(out *TASPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicy.
+func (in *TASPolicy) DeepCopy() *TASPolicy {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicy)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TASPolicy) DeepCopyObject() runtime.Object {
+	//nolint:revive
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyList) DeepCopyInto(out *TASPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TASPolicy, len(*in))
+
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyList.
+func (in *TASPolicyList) DeepCopy() *TASPolicyList {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyList)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TASPolicyList) DeepCopyObject() runtime.Object {
+	//nolint:revive
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicySpec) DeepCopyInto(out *TASPolicySpec) {
+	*out = *in
+
+	if in.Strategies != nil {
+		in, out := &in.Strategies, &out.Strategies
+		*out = make(map[string]TASPolicyStrategy, len(*in))
+
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]TASPolicyMetric, len(*in))
+
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicySpec.
+func (in *TASPolicySpec) DeepCopy() *TASPolicySpec {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicySpec)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyMetric) DeepCopyInto(out *TASPolicyMetric) {
+	*out = *in
+
+	if in.Scale != nil {
+		x := in.Scale.DeepCopy()
+		out.Scale = &x
+	}
+
+	if in.Offset != nil {
+		x := in.Offset.DeepCopy()
+		out.Offset = &x
+	}
+
+	if in.Min != nil {
+		x := in.Min.DeepCopy()
+		out.Min = &x
+	}
+
+	if in.Max != nil {
+		x := in.Max.DeepCopy()
+		out.Max = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyMetric.
+func (in *TASPolicyMetric) DeepCopy() *TASPolicyMetric {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyMetric)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyStrategy) DeepCopyInto(out *TASPolicyStrategy) {
+	*out = *in
+
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]TASPolicyRule, len(*in))
+
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+
+	if in.Group != nil {
+		in, out := &in.Group, &out.Group
+		*out = new(TASPolicyRuleGroup)
+		(*in).DeepCopyInto(*out)
+	}
+
+	if in.ActiveWindows != nil {
+		in, out := &in.ActiveWindows, &out.ActiveWindows
+		*out = make([]TASPolicyActiveWindow, len(*in))
+
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyStrategy.
+func (in *TASPolicyStrategy) DeepCopy() *TASPolicyStrategy {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyStrategy)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyActiveWindow) DeepCopyInto(out *TASPolicyActiveWindow) {
+	*out = *in
+
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyActiveWindow.
+func (in *TASPolicyActiveWindow) DeepCopy() *TASPolicyActiveWindow {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyActiveWindow)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyRule) DeepCopyInto(out *TASPolicyRule) {
+	*out = *in
+
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+
+	out.Target = in.Target.DeepCopy()
+
+	if in.Range != nil {
+		in, out := &in.Range, &out.Range
+		*out = new(TASPolicyRuleRange)
+		(*in).DeepCopyInto(*out)
+	}
+
+	if in.Relative != nil {
+		in, out := &in.Relative, &out.Relative
+		*out = new(TASPolicyRuleRelative)
+		(*in).DeepCopyInto(*out)
+	}
+
+	if in.Trend != nil {
+		in, out := &in.Trend, &out.Trend
+		*out = new(TASPolicyRuleTrend)
+		(*in).DeepCopyInto(*out)
+	}
+
+	if in.MetricSelector != nil {
+		in, out := &in.MetricSelector, &out.MetricSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyRule.
+func (in *TASPolicyRule) DeepCopy() *TASPolicyRule {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyRule)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyRuleRelative) DeepCopyInto(out *TASPolicyRuleRelative) {
+	*out = *in
+
+	if in.Factor != nil {
+		x := in.Factor.DeepCopy()
+		out.Factor = &x
+	}
+
+	if in.Deviations != nil {
+		x := in.Deviations.DeepCopy()
+		out.Deviations = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyRuleRelative.
+func (in *TASPolicyRuleRelative) DeepCopy() *TASPolicyRuleRelative {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyRuleRelative)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyTargetOverride) DeepCopyInto(out *TASPolicyTargetOverride) {
+	*out = *in
+	out.Target = in.Target.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyTargetOverride.
+func (in *TASPolicyTargetOverride) DeepCopy() *TASPolicyTargetOverride {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyTargetOverride)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyRuleTrend) DeepCopyInto(out *TASPolicyRuleTrend) {
+	*out = *in
+
+	if in.Alpha != nil {
+		x := in.Alpha.DeepCopy()
+		out.Alpha = &x
+	}
+
+	if in.Beta != nil {
+		x := in.Beta.DeepCopy()
+		out.Beta = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyRuleTrend.
+func (in *TASPolicyRuleTrend) DeepCopy() *TASPolicyRuleTrend {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyRuleTrend)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyStatus) DeepCopyInto(out *TASPolicyStatus) {
+	*out = *in
+
+	if in.UnknownStrategies != nil {
+		in, out := &in.UnknownStrategies, &out.UnknownStrategies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+
+	if in.TargetOverrides != nil {
+		in, out := &in.TargetOverrides, &out.TargetOverrides
+		*out = make([]TASPolicyTargetOverride, len(*in))
+
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+
+	if in.Strategies != nil {
+		in, out := &in.Strategies, &out.Strategies
+		*out = make([]TASPolicyStrategyStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyStatus.
+func (in *TASPolicyStatus) DeepCopy() *TASPolicyStatus {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyStatus)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyRuleRange) DeepCopyInto(out *TASPolicyRuleRange) {
+	*out = *in
+	out.Lower = in.Lower.DeepCopy()
+	out.Upper = in.Upper.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyRuleRange.
+func (in *TASPolicyRuleRange) DeepCopy() *TASPolicyRuleRange {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyRuleRange)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyRuleGroup) DeepCopyInto(out *TASPolicyRuleGroup) {
+	*out = *in
+	out.AnyOf = deepCopyRuleGroups(in.AnyOf)
+	out.AllOf = deepCopyRuleGroups(in.AllOf)
+	out.NoneOf = deepCopyRuleGroups(in.NoneOf)
+
+	if in.AtLeast != nil {
+		in, out := &in.AtLeast, &out.AtLeast
+		*out = new(TASPolicyAtLeast)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyRuleGroup.
+func (in *TASPolicyRuleGroup) DeepCopy() *TASPolicyRuleGroup {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyRuleGroup)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TASPolicyAtLeast) DeepCopyInto(out *TASPolicyAtLeast) {
+	*out = *in
+	out.Of = deepCopyRuleGroups(in.Of)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TASPolicyAtLeast.
+func (in *TASPolicyAtLeast) DeepCopy() *TASPolicyAtLeast {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TASPolicyAtLeast)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+func deepCopyRuleGroups(in []TASPolicyRuleGroup) []TASPolicyRuleGroup {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]TASPolicyRuleGroup, len(in))
+	for i := range in {
+		in[i].DeepCopyInto(&out[i])
+	}
+
+	return out
+}
@@ -0,0 +1,422 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package validation checks Telemetry Policies for errors which would stop their strategies from being enforced.
+package validation
+
+import (
+	"regexp"
+	"time"
+
+	strategy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/dontschedule"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/evict"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// metricNamePattern matches the metric names accepted by the CRD. The metric cache uses / to build its keys.
+var metricNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+var logicalOperators = []string{string(telempol.AllOf), string(telempol.AnyOf)}
+
+var aggregations = []string{string(telempol.Avg), string(telempol.Count), string(telempol.Max), string(telempol.Min),
+	string(telempol.Sum)}
+
+var scopes = []string{string(telempol.NodeScope), string(telempol.PodScope)}
+
+const maxPercentile = 100
+
+var weekdays = []string{time.Sunday.String(), time.Monday.String(), time.Tuesday.String(), time.Wednesday.String(),
+	time.Thursday.String(), time.Friday.String(), time.Saturday.String()}
+
+// ValidatePolicy returns an error for each invalid field of the policy.
+// Strategy types must be registered and each strategy implementing strategy.Validator is checked by it as well.
+func ValidatePolicy(policy *telempol.TASPolicy) field.ErrorList {
+	allErrs := field.ErrorList{}
+	strategiesPath := field.NewPath("spec", "strategies")
+
+	if len(policy.Spec.Strategies) == 0 {
+		allErrs = append(allErrs, field.Required(strategiesPath, "at least one strategy is needed"))
+	}
+
+	for name, spec := range policy.Spec.Strategies {
+		allErrs = append(allErrs, validateStrategy(strategiesPath.Key(name), name, policy.Name, spec)...)
+	}
+
+	allErrs = append(allErrs, validateMetrics(field.NewPath("spec", "metrics"), policy.Spec.Metrics)...)
+
+	return allErrs
+}
+
+// validateMetrics checks the metrics computed by the policy. Each metric reads either a source metric or the metrics of
+// a numeric expression, which can't include itself or another expression metric of the policy, since expression
+// metrics are computed in no particular order.
+func validateMetrics(path *field.Path, metrics []telempol.TASPolicyMetric) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	derived := map[string]bool{}
+
+	for _, metric := range metrics {
+		derived[metric.Name] = derived[metric.Name] || metric.Expression != ""
+	}
+
+	for i, metric := range metrics {
+		metricPath := path.Index(i)
+
+		switch {
+		case metric.Name == "":
+			allErrs = append(allErrs, field.Required(metricPath.Child("name"), "metrics need a name"))
+		case !metricNamePattern.MatchString(metric.Name):
+			allErrs = append(allErrs, field.Invalid(metricPath.Child("name"), metric.Name, "must match "+metricNamePattern.String()))
+		case names[metric.Name]:
+			allErrs = append(allErrs, field.Duplicate(metricPath.Child("name"), metric.Name))
+		}
+
+		names[metric.Name] = true
+
+		switch {
+		case metric.Source == "" && metric.Expression == "":
+			allErrs = append(allErrs, field.Required(metricPath.Child("source"), "a source or an expression is needed"))
+		case metric.Source != "" && metric.Expression != "":
+			allErrs = append(allErrs, field.Forbidden(metricPath.Child("expression"), "can't be set together with source"))
+		case metric.Source != "":
+			if !metricNamePattern.MatchString(metric.Source) || metric.Source == metric.Name {
+				allErrs = append(allErrs, field.Invalid(metricPath.Child("source"), metric.Source,
+					"must match "+metricNamePattern.String()+" and differ from the name"))
+			}
+		default:
+			allErrs = append(allErrs, validateMetricExpression(metricPath.Child("expression"), metric, derived)...)
+		}
+
+		if metric.Min != nil && metric.Max != nil && metric.Min.Cmp(*metric.Max) > 0 {
+			allErrs = append(allErrs, field.Invalid(metricPath.Child("max"), metric.Max.String(), "must not be less than min"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateMetricExpression compiles the expression of a metric, which must be numeric and read neither the metric
+// itself nor another expression metric of the policy.
+func validateMetricExpression(path *field.Path, metric telempol.TASPolicyMetric, derived map[string]bool) field.ErrorList {
+	expr, err := strategy.ParseExpression(metric.Expression)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, metric.Expression, err.Error())}
+	}
+
+	allErrs := field.ErrorList{}
+
+	if expr.Bool {
+		allErrs = append(allErrs, field.Invalid(path, metric.Expression, "must evaluate to a number"))
+	}
+
+	for _, name := range expr.Metrics {
+		switch {
+		case !metricNamePattern.MatchString(name):
+			allErrs = append(allErrs, field.Invalid(path, metric.Expression, "metric "+name+" must match "+metricNamePattern.String()))
+		case name == metric.Name:
+			allErrs = append(allErrs, field.Invalid(path, metric.Expression, "must not read the metric it computes"))
+		case derived[name]:
+			allErrs = append(allErrs, field.Invalid(path, metric.Expression, "must not read the expression metric "+name))
+		}
+	}
+
+	return allErrs
+}
+
+// validateStrategy checks the strategy type, logical operator and rules of a single strategy.
+func validateStrategy(path *field.Path, strategyType string, policyName string,
+	spec telempol.TASPolicyStrategy) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	str, err := strategy.New(strategyType, spec)
+	if err != nil {
+		allErrs = append(allErrs, field.NotSupported(path, strategyType, strategy.RegisteredTypes()))
+	}
+
+	if spec.LogicalOperator != "" && !contains(logicalOperators, string(spec.LogicalOperator)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("logicalOperator"), string(spec.LogicalOperator), logicalOperators))
+	}
+
+	if len(spec.Rules) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("rules"), "at least one rule is needed"))
+	}
+
+	names := map[string]bool{}
+
+	for i, rule := range spec.Rules {
+		allErrs = append(allErrs, validateRule(path.Child("rules").Index(i), rule)...)
+
+		if rule.Name != "" && names[rule.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("rules").Index(i).Child("name"), rule.Name))
+		}
+
+		names[rule.Name] = true
+	}
+
+	if spec.Group != nil {
+		allErrs = append(allErrs, validateGroup(path.Child("group"), *spec.Group, names)...)
+	}
+
+	for i, window := range spec.ActiveWindows {
+		allErrs = append(allErrs, validateWindow(path.Child("activeWindows").Index(i), window)...)
+	}
+
+	if spec.FailClosed && strategyType != dontschedule.StrategyType {
+		allErrs = append(allErrs, field.Forbidden(path.Child("failClosed"), "only supported by "+dontschedule.StrategyType))
+	}
+
+	if spec.PodSelector != nil {
+		if strategyType != evict.StrategyType {
+			allErrs = append(allErrs, field.Forbidden(path.Child("podSelector"), "only supported by "+evict.StrategyType))
+		}
+
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(spec.PodSelector,
+			metav1validation.LabelSelectorValidationOptions{}, path.Child("podSelector"))...)
+	}
+
+	if validator, ok := str.(strategy.Validator); ok {
+		str.SetPolicyName(policyName)
+		allErrs = append(allErrs, validator.Validate(path)...)
+	}
+
+	return allErrs
+}
+
+// validateWindow checks the days, times and time zone of an active window. A window can't start and end at the same
+// time, since it would be ambiguous whether it lasts a whole day or never applies.
+func validateWindow(path *field.Path, window telempol.TASPolicyActiveWindow) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, day := range window.Days {
+		if !contains(weekdays, day) {
+			allErrs = append(allErrs, field.NotSupported(path.Child("days").Index(i), day, weekdays))
+		}
+	}
+
+	times := []struct {
+		path  *field.Path
+		value string
+	}{{path.Child("start"), window.Start}, {path.Child("end"), window.End}}
+
+	for _, clock := range times {
+		if _, err := time.Parse(strategy.WindowTimeFormat, clock.value); err != nil {
+			allErrs = append(allErrs, field.Invalid(clock.path, clock.value, "must be a time formatted as HH:MM"))
+		}
+	}
+
+	if window.Start == window.End {
+		allErrs = append(allErrs, field.Invalid(path.Child("end"), window.End, "must not be equal to start"))
+	}
+
+	if _, err := strategy.LoadLocation(window.TimeZone); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("timeZone"), window.TimeZone, "must be an IANA time zone name"))
+	}
+
+	return allErrs
+}
+
+// validateGroup checks that every group in the tree sets exactly one field, names a rule of the strategy and that
+// atLeast counts can be reached.
+func validateGroup(path *field.Path, group telempol.TASPolicyRuleGroup, names map[string]bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+	set := 0
+
+	for _, isSet := range []bool{group.Rule != "", group.AnyOf != nil, group.AllOf != nil, group.NoneOf != nil,
+		group.AtLeast != nil} {
+		if isSet {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return append(allErrs, field.Invalid(path, set, "exactly one of rule, anyOf, allOf, noneOf and atLeast must be set"))
+	}
+
+	children := []struct {
+		path   *field.Path
+		groups []telempol.TASPolicyRuleGroup
+	}{{path.Child("anyOf"), group.AnyOf}, {path.Child("allOf"), group.AllOf}, {path.Child("noneOf"), group.NoneOf}}
+
+	switch {
+	case group.Rule != "" && !names[group.Rule]:
+		allErrs = append(allErrs, field.NotFound(path.Child("rule"), group.Rule))
+	case group.AtLeast != nil:
+		if group.AtLeast.Count < 1 || int(group.AtLeast.Count) > len(group.AtLeast.Of) {
+			allErrs = append(allErrs, field.Invalid(path.Child("atLeast", "count"), group.AtLeast.Count,
+				"must be between 1 and the number of groups"))
+		}
+
+		children[0].path, children[0].groups = path.Child("atLeast", "of"), group.AtLeast.Of
+	}
+
+	for _, list := range children {
+		if list.groups != nil && len(list.groups) == 0 {
+			allErrs = append(allErrs, field.Required(list.path, "at least one group is needed"))
+		}
+
+		for i, child := range list.groups {
+			allErrs = append(allErrs, validateGroup(list.path.Index(i), child, names)...)
+		}
+	}
+
+	return allErrs
+}
+
+// validateRule checks the metric name or expression and the operator of a rule. Range operators need a range with
+// ordered bounds, other operators can't have a range. The series of the rule metrics need a valid label selector and
+// aggregation.
+func validateRule(path *field.Path, rule telempol.TASPolicyRule) field.ErrorList {
+	allErrs := field.ErrorList{}
+	needsOperator := true
+
+	switch {
+	case rule.Metricname == "" && rule.Expression == "":
+		allErrs = append(allErrs, field.Required(path.Child("metricname"), "a metricname or an expression is needed"))
+	case rule.Metricname != "" && rule.Expression != "":
+		allErrs = append(allErrs, field.Forbidden(path.Child("expression"), "can't be set together with metricname"))
+	case rule.Expression != "":
+		allErrs, needsOperator = validateExpression(path, rule, allErrs)
+	case !metricNamePattern.MatchString(rule.Metricname):
+		allErrs = append(allErrs, field.Invalid(path.Child("metricname"), rule.Metricname,
+			"must match "+metricNamePattern.String()))
+	}
+
+	if needsOperator && !strategy.IsSupportedOperator(rule.Operator) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("operator"), string(rule.Operator), strategy.SupportedOperators()))
+	}
+
+	switch {
+	case strategy.IsRangeOperator(rule.Operator) && rule.Range == nil:
+		allErrs = append(allErrs, field.Required(path.Child("range"), "needed by the "+string(rule.Operator)+" operator"))
+	case strategy.IsRangeOperator(rule.Operator) && rule.Range.Lower.Cmp(rule.Range.Upper) > 0:
+		allErrs = append(allErrs, field.Invalid(path.Child("range", "upper"), rule.Range.Upper.String(),
+			"must not be less than lower"))
+	case !strategy.IsRangeOperator(rule.Operator) && rule.Range != nil:
+		allErrs = append(allErrs, field.Forbidden(path.Child("range"), "only used by the InRange and OutOfRange operators"))
+	}
+
+	if rule.Relative != nil {
+		allErrs = append(allErrs, validateRelative(path.Child("relative"), rule)...)
+	}
+
+	if rule.Trend != nil {
+		allErrs = append(allErrs, validateTrend(path.Child("trend"), rule)...)
+	}
+
+	if rule.Aggregation != "" && !contains(aggregations, string(rule.Aggregation)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("aggregation"), string(rule.Aggregation), aggregations))
+	}
+
+	if rule.Scope != "" && !contains(scopes, string(rule.Scope)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("scope"), string(rule.Scope), scopes))
+	}
+
+	if rule.MetricSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(rule.MetricSelector,
+			metav1validation.LabelSelectorValidationOptions{}, path.Child("metricSelector"))...)
+	}
+
+	return allErrs
+}
+
+// validateTrend checks the model, horizon and smoothing factors of a trend. Trends predict the history of a single
+// metric, so expression rules can't have one.
+func validateTrend(path *field.Path, rule telempol.TASPolicyRule) field.ErrorList {
+	allErrs := field.ErrorList{}
+	trend := rule.Trend
+
+	if rule.Expression != "" {
+		allErrs = append(allErrs, field.Forbidden(path, "only used by metricname rules"))
+	}
+
+	if trend.Horizon.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("horizon"), trend.Horizon.String(), "must be positive"))
+	}
+
+	factors := []struct {
+		path  *field.Path
+		value *resource.Quantity
+	}{{path.Child("alpha"), trend.Alpha}, {path.Child("beta"), trend.Beta}}
+
+	switch trend.Model {
+	case telempol.Linear:
+		for _, factor := range factors {
+			if factor.value != nil {
+				allErrs = append(allErrs, field.Forbidden(factor.path, "only used by the ExponentialSmoothing model"))
+			}
+		}
+	case telempol.ExponentialSmoothing:
+		for _, factor := range factors {
+			if factor.value != nil && (factor.value.Sign() <= 0 || factor.value.Cmp(resource.MustParse("1")) > 0) {
+				allErrs = append(allErrs, field.Invalid(factor.path, factor.value.String(), "must be greater than 0 and at most 1"))
+			}
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("model"), string(trend.Model),
+			[]string{string(telempol.ExponentialSmoothing), string(telempol.Linear)}))
+	}
+
+	return allErrs
+}
+
+// validateRelative checks the statistic of a relative target. Relative targets replace the target, so they can't be
+// used by range operators or bool expressions.
+func validateRelative(path *field.Path, rule telempol.TASPolicyRule) field.ErrorList {
+	allErrs := field.ErrorList{}
+	relative := rule.Relative
+
+	switch relative.Statistic {
+	case telempol.Percentile:
+		if relative.Percentile < 0 || relative.Percentile > maxPercentile {
+			allErrs = append(allErrs, field.Invalid(path.Child("percentile"), relative.Percentile, "must be between 0 and 100"))
+		}
+	case telempol.Mean, telempol.Median:
+		if relative.Percentile != 0 {
+			allErrs = append(allErrs, field.Forbidden(path.Child("percentile"), "only used by the Percentile statistic"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("statistic"), string(relative.Statistic),
+			[]string{string(telempol.Mean), string(telempol.Median), string(telempol.Percentile)}))
+	}
+
+	if strategy.IsRangeOperator(rule.Operator) || (rule.Operator == "" && rule.Expression != "") {
+		allErrs = append(allErrs, field.Forbidden(path, "only used by operators comparing against the target"))
+	}
+
+	return allErrs
+}
+
+// validateExpression compiles the rule expression. Bool expressions can't have an operator, numeric ones need one.
+func validateExpression(path *field.Path, rule telempol.TASPolicyRule, allErrs field.ErrorList) (field.ErrorList, bool) {
+	expr, err := strategy.ParseExpression(rule.Expression)
+	if err != nil {
+		return append(allErrs, field.Invalid(path.Child("expression"), rule.Expression, err.Error())), false
+	}
+
+	for _, name := range expr.Metrics {
+		if !metricNamePattern.MatchString(name) {
+			allErrs = append(allErrs, field.Invalid(path.Child("expression"), rule.Expression,
+				"metric "+name+" must match "+metricNamePattern.String()))
+		}
+	}
+
+	if expr.Bool && rule.Operator != "" {
+		return append(allErrs, field.Forbidden(path.Child("operator"), "bool expressions are violated when true")), false
+	}
+
+	return allErrs, !expr.Bool
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,270 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	_ "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/deschedule"
+	_ "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/dontschedule"
+	_ "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/labeling"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func policyWith(strategies map[string]telempol.TASPolicyStrategy) *telempol.TASPolicy {
+	return &telempol.TASPolicy{ObjectMeta: metav1.ObjectMeta{Name: "validation-test", Namespace: "default"},
+		Spec: telempol.TASPolicySpec{Strategies: strategies}}
+}
+
+func TestValidatePolicy(t *testing.T) {
+	rule := telempol.TASPolicyRule{Metricname: "temperature", Operator: "GreaterThan", Target: resource.MustParse("90")}
+	half, two := resource.MustParse("0.5"), resource.MustParse("2")
+	labelRule := func(operator telempol.Operator, labels ...string) telempol.TASPolicyRule {
+		return telempol.TASPolicyRule{Metricname: "temperature", Operator: operator, Target: resource.MustParse("90"), Labels: labels}
+	}
+
+	tests := []struct {
+		name       string
+		strategies map[string]telempol.TASPolicyStrategy
+		metrics    []telempol.TASPolicyMetric
+		wantFields []string
+	}{
+		{name: "valid policy",
+			strategies: map[string]telempol.TASPolicyStrategy{
+				"dontschedule": {Rules: []telempol.TASPolicyRule{rule}},
+				"labeling": {LogicalOperator: "allOf", Rules: []telempol.TASPolicyRule{
+					labelRule("GreaterThan", "hot=true"), labelRule("GreaterThan", "hot=very")}},
+			}},
+		{name: "no strategies", wantFields: []string{"spec.strategies"}},
+		{name: "unknown strategy",
+			strategies: map[string]telempol.TASPolicyStrategy{"reschedule": {Rules: []telempol.TASPolicyRule{rule}}},
+			wantFields: []string{"spec.strategies[reschedule]"}},
+		{name: "unknown operator and empty metric name",
+			strategies: map[string]telempol.TASPolicyStrategy{"deschedule": {Rules: []telempol.TASPolicyRule{
+				{Metricname: "temperature", Operator: "GreatThan"}, {Operator: "LessThan"}}}},
+			wantFields: []string{"spec.strategies[deschedule].rules[0].operator", "spec.strategies[deschedule].rules[1].metricname"}},
+		{name: "metric name with slash",
+			strategies: map[string]telempol.TASPolicyStrategy{"deschedule": {Rules: []telempol.TASPolicyRule{
+				{Metricname: "node/temperature", Operator: "LessThan"}}}},
+			wantFields: []string{"spec.strategies[deschedule].rules[0].metricname"}},
+		{name: "bad logical operator and no rules",
+			strategies: map[string]telempol.TASPolicyStrategy{"deschedule": {LogicalOperator: "oneOf"}},
+			wantFields: []string{"spec.strategies[deschedule].logicalOperator", "spec.strategies[deschedule].rules"}},
+		{name: "malformed labels",
+			strategies: map[string]telempol.TASPolicyStrategy{"labeling": {Rules: []telempol.TASPolicyRule{
+				labelRule("GreaterThan", "hot", "a=b=c", "bad name=true", "hot=not valid")}}},
+			wantFields: []string{"spec.strategies[labeling].rules[0].labels[0]", "spec.strategies[labeling].rules[0].labels[1]",
+				"spec.strategies[labeling].rules[0].labels[2]", "spec.strategies[labeling].rules[0].labels[3]"}},
+		{name: "range operators",
+			strategies: map[string]telempol.TASPolicyStrategy{"deschedule": {Rules: []telempol.TASPolicyRule{
+				{Metricname: "temperature", Operator: "InRange",
+					Range: &telempol.TASPolicyRuleRange{Lower: resource.MustParse("0.5"), Upper: resource.MustParse("1")}},
+				{Metricname: "temperature", Operator: "OutOfRange"},
+				{Metricname: "temperature", Operator: "InRange",
+					Range: &telempol.TASPolicyRuleRange{Lower: resource.MustParse("2"), Upper: resource.MustParse("1")}},
+				{Metricname: "temperature", Operator: "GreaterOrEqual", Range: &telempol.TASPolicyRuleRange{}}}}},
+			wantFields: []string{"spec.strategies[deschedule].rules[1].range", "spec.strategies[deschedule].rules[2].range.upper",
+				"spec.strategies[deschedule].rules[3].range"}},
+		{name: "expression rules",
+			strategies: map[string]telempol.TASPolicyStrategy{"deschedule": {Rules: []telempol.TASPolicyRule{
+				{Expression: "metrics.power / metrics.power_limit > 0.9 && metrics.temp > 70"},
+				{Expression: "metrics.power / metrics.power_limit", Operator: "GreaterThan", Target: resource.MustParse("0.9")},
+				{Expression: "metrics.temp > 70", Operator: "Equals"},
+				{Expression: "metrics.power / metrics.power_limit"},
+				{Expression: "metrics.temp >"},
+				{Metricname: "temp", Expression: "metrics.temp > 70"}}}},
+			wantFields: []string{"spec.strategies[deschedule].rules[2].operator", "spec.strategies[deschedule].rules[3].operator",
+				"spec.strategies[deschedule].rules[4].expression", "spec.strategies[deschedule].rules[5].expression",
+				"spec.strategies[deschedule].rules[5].operator"}},
+		{name: "rule groups",
+			strategies: map[string]telempol.TASPolicyStrategy{"deschedule": {
+				Rules: []telempol.TASPolicyRule{
+					{Name: "temp", Metricname: "temperature", Operator: "GreaterThan"},
+					{Name: "power", Metricname: "power", Operator: "GreaterThan"},
+					{Name: "power", Metricname: "fan", Operator: "LessThan"}},
+				Group: &telempol.TASPolicyRuleGroup{AllOf: []telempol.TASPolicyRuleGroup{
+					{Rule: "temp"},
+					{Rule: "fan"},
+					{Rule: "temp", AnyOf: []telempol.TASPolicyRuleGroup{{Rule: "power"}}},
+					{NoneOf: []telempol.TASPolicyRuleGroup{}},
+					{AtLeast: &telempol.TASPolicyAtLeast{Count: 3, Of: []telempol.TASPolicyRuleGroup{{Rule: "temp"}, {Rule: "power"}}}}}}}},
+			wantFields: []string{"spec.strategies[deschedule].rules[2].name", "spec.strategies[deschedule].group.allOf[1].rule",
+				"spec.strategies[deschedule].group.allOf[2]", "spec.strategies[deschedule].group.allOf[3].noneOf",
+				"spec.strategies[deschedule].group.allOf[4].atLeast.count"}},
+		{name: "relative targets",
+			strategies: map[string]telempol.TASPolicyStrategy{"deschedule": {Rules: []telempol.TASPolicyRule{
+				{Metricname: "temperature", Operator: "GreaterThan", Relative: &telempol.TASPolicyRuleRelative{Statistic: "Percentile", Percentile: 90}},
+				{Metricname: "temperature", Operator: "GreaterThan", Relative: &telempol.TASPolicyRuleRelative{Statistic: "Percentile", Percentile: 101}},
+				{Metricname: "temperature", Operator: "GreaterThan", Relative: &telempol.TASPolicyRuleRelative{Statistic: "Mean", Percentile: 50}},
+				{Metricname: "temperature", Operator: "GreaterThan", Relative: &telempol.TASPolicyRuleRelative{Statistic: "Mode"}},
+				{Metricname: "temperature", Operator: "InRange", Range: &telempol.TASPolicyRuleRange{},
+					Relative: &telempol.TASPolicyRuleRelative{Statistic: "Median"}},
+				{Expression: "metrics.temp > 70", Relative: &telempol.TASPolicyRuleRelative{Statistic: "Median"}}}}},
+			wantFields: []string{"spec.strategies[deschedule].rules[1].relative.percentile",
+				"spec.strategies[deschedule].rules[2].relative.percentile", "spec.strategies[deschedule].rules[3].relative.statistic",
+				"spec.strategies[deschedule].rules[4].relative", "spec.strategies[deschedule].rules[5].relative"}},
+		{name: "trends",
+			strategies: map[string]telempol.TASPolicyStrategy{"dontschedule": {Rules: []telempol.TASPolicyRule{
+				{Metricname: "temperature", Operator: "GreaterThan", Trend: &telempol.TASPolicyRuleTrend{Model: "Linear",
+					Horizon: metav1.Duration{Duration: time.Minute}}},
+				{Metricname: "temperature", Operator: "GreaterThan", Trend: &telempol.TASPolicyRuleTrend{Model: "ExponentialSmoothing",
+					Horizon: metav1.Duration{Duration: time.Minute}, Alpha: &half, Beta: &two}},
+				{Metricname: "temperature", Operator: "GreaterThan", Trend: &telempol.TASPolicyRuleTrend{Model: "Linear", Alpha: &half}},
+				{Metricname: "temperature", Operator: "GreaterThan", Trend: &telempol.TASPolicyRuleTrend{Model: "Cubic",
+					Horizon: metav1.Duration{Duration: time.Minute}}},
+				{Expression: "metrics.temp > 70", Trend: &telempol.TASPolicyRuleTrend{Model: "Linear",
+					Horizon: metav1.Duration{Duration: time.Minute}}}}}},
+			wantFields: []string{"spec.strategies[dontschedule].rules[1].trend.beta", "spec.strategies[dontschedule].rules[2].trend.horizon",
+				"spec.strategies[dontschedule].rules[2].trend.alpha", "spec.strategies[dontschedule].rules[3].trend.model",
+				"spec.strategies[dontschedule].rules[4].trend"}},
+		{name: "series selection",
+			strategies: map[string]telempol.TASPolicyStrategy{"dontschedule": {Rules: []telempol.TASPolicyRule{
+				{Metricname: "gpu_temperature", Operator: "GreaterThan", Aggregation: "Max",
+					MetricSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"card": "card0"}}},
+				{Metricname: "gpu_temperature", Operator: "GreaterThan", Aggregation: "Median"},
+				{Metricname: "gpu_power", Operator: "GreaterThan", Aggregation: "Sum", Scope: "Pod"},
+				{Metricname: "gpu_power", Operator: "GreaterThan", Scope: "Container"},
+				{Metricname: "gpu_temperature", Operator: "GreaterThan", MetricSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "card", Operator: "In"}}}}}}},
+			wantFields: []string{"spec.strategies[dontschedule].rules[1].aggregation",
+				"spec.strategies[dontschedule].rules[4].metricSelector.matchExpressions[0].values",
+				"spec.strategies[dontschedule].rules[3].scope"}},
+		{name: "active windows",
+			strategies: map[string]telempol.TASPolicyStrategy{"deschedule": {Rules: []telempol.TASPolicyRule{
+				{Metricname: "temperature", Operator: "GreaterThan"}}, ActiveWindows: []telempol.TASPolicyActiveWindow{
+				{Days: []string{"Saturday", "Sunday"}, Start: "22:00", End: "06:00", TimeZone: "Europe/Dublin"},
+				{Days: []string{"Funday"}, Start: "9:00am", End: "17:00"},
+				{Start: "08:00", End: "08:00", TimeZone: "Mars/Olympus"}}}},
+			wantFields: []string{"spec.strategies[deschedule].activeWindows[1].days[0]",
+				"spec.strategies[deschedule].activeWindows[1].start", "spec.strategies[deschedule].activeWindows[2].end",
+				"spec.strategies[deschedule].activeWindows[2].timeZone"}},
+		{name: "fail closed",
+			strategies: map[string]telempol.TASPolicyStrategy{
+				"dontschedule": {Rules: []telempol.TASPolicyRule{rule}, FailClosed: true},
+				"deschedule":   {Rules: []telempol.TASPolicyRule{rule}, FailClosed: true}},
+			wantFields: []string{"spec.strategies[deschedule].failClosed"}},
+		{name: "pod selector",
+			strategies: map[string]telempol.TASPolicyStrategy{
+				"evict": {Rules: []telempol.TASPolicyRule{rule}, PodSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "In"}}}},
+				"deschedule": {Rules: []telempol.TASPolicyRule{rule}, PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "batch"}}}},
+			wantFields: []string{"spec.strategies[evict].podSelector.matchExpressions[0].values",
+				"spec.strategies[deschedule].podSelector"}},
+		{name: "policy metrics",
+			strategies: map[string]telempol.TASPolicyStrategy{"deschedule": {Rules: []telempol.TASPolicyRule{rule}}},
+			metrics: []telempol.TASPolicyMetric{
+				{Name: "power_ratio", Expression: "metrics.power / metrics.power_budget", Max: &two},
+				{Name: "power_watts", Source: "power_milliwatts", Scale: &half, Min: &two, Max: &half},
+				{Name: "power_ratio", Source: "power"},
+				{Name: "power_excess", Expression: "metrics.power_ratio > 1"},
+				{Name: "power_spare", Source: "power", Expression: "1 - metrics.power_ratio"},
+				{Name: "power/2"}},
+			wantFields: []string{"spec.metrics[1].max", "spec.metrics[2].name", "spec.metrics[3].expression",
+				"spec.metrics[3].expression", "spec.metrics[4].expression", "spec.metrics[5].name", "spec.metrics[5].source"}},
+		{name: "conflicting label operators",
+			strategies: map[string]telempol.TASPolicyStrategy{"labeling": {Rules: []telempol.TASPolicyRule{
+				labelRule("GreaterThan", "card0=hot"), labelRule("LessThan", "card0=cold")}}},
+			wantFields: []string{"spec.strategies[labeling].rules[1].operator"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			gotFields := []string{}
+			policy := policyWith(tt.strategies)
+			policy.Spec.Metrics = tt.metrics
+
+			for _, err := range ValidatePolicy(policy) {
+				gotFields = append(gotFields, err.Field)
+			}
+
+			sort.Strings(gotFields)
+			sort.Strings(tt.wantFields)
+
+			if len(gotFields) != len(tt.wantFields) || (len(gotFields) > 0 && !reflect.DeepEqual(gotFields, tt.wantFields)) {
+				t.Errorf("Got invalid fields %v, want %v", gotFields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	tests := []struct {
+		name        string
+		operation   admissionv1.Operation
+		version     string
+		policy      interface{}
+		wantAllowed bool
+	}{
+		{name: "valid policy allowed", operation: admissionv1.Create, wantAllowed: true,
+			policy: policyWith(map[string]telempol.TASPolicyStrategy{"dontschedule": {Rules: []telempol.TASPolicyRule{
+				{Metricname: "temperature", Operator: "LessThan"}}}})},
+		{name: "invalid policy denied", operation: admissionv1.Update, wantAllowed: false,
+			policy: policyWith(map[string]telempol.TASPolicyStrategy{"dontschedule": {Rules: []telempol.TASPolicyRule{
+				{Metricname: "temperature", Operator: "Above"}}}})},
+		{name: "delete not validated", operation: admissionv1.Delete, wantAllowed: true,
+			policy: policyWith(nil)},
+		{name: "valid v1alpha1 policy allowed", operation: admissionv1.Create, version: v1alpha1.Version, wantAllowed: true,
+			policy: &v1alpha1.TASPolicy{ObjectMeta: metav1.ObjectMeta{Name: "validation-test"},
+				Spec: v1alpha1.TASPolicySpec{Strategies: map[string]v1alpha1.TASPolicyStrategy{"dontschedule": {
+					Rules: []v1alpha1.TASPolicyRule{{Metricname: "temperature", Operator: "LessThan", Target: 10}}}}}}},
+		{name: "invalid v1alpha1 policy denied", operation: admissionv1.Create, version: v1alpha1.Version, wantAllowed: false,
+			policy: &v1alpha1.TASPolicy{ObjectMeta: metav1.ObjectMeta{Name: "validation-test"},
+				Spec: v1alpha1.TASPolicySpec{Strategies: map[string]v1alpha1.TASPolicyStrategy{"dontschedule": {
+					LogicalOperator: "oneOf", Rules: []v1alpha1.TASPolicyRule{{Metricname: "temperature", Operator: "LessThan"}}}}}}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			raw, _ := json.Marshal(tt.policy)
+			review := admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{UID: "review-uid",
+				Kind:      metav1.GroupVersionKind{Group: telempol.Group, Version: tt.version, Kind: "TASPolicy"},
+				Operation: tt.operation, Object: runtime.RawExtension{Raw: raw}}}
+			body, _ := json.Marshal(review)
+
+			recorder := httptest.NewRecorder()
+			ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader(body)))
+
+			got := admissionv1.AdmissionReview{}
+			if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil || got.Response == nil {
+				t.Errorf("Cannot decode admission response %v: %v", recorder.Body.String(), err)
+
+				return
+			}
+
+			if got.Response.UID != "review-uid" || got.Response.Allowed != tt.wantAllowed {
+				t.Errorf("Got response %+v, want allowed %v", got.Response, tt.wantAllowed)
+			}
+
+			if !tt.wantAllowed && (got.Response.Result == nil || got.Response.Result.Details == nil ||
+				len(got.Response.Result.Details.Causes) == 0) {
+				t.Errorf("Denied response without field causes: %+v", got.Response.Result)
+			}
+		})
+	}
+}
+
+func TestStartServer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := StartServer(ctx, "0", "missing.crt", "missing.key"); err == nil {
+		t.Errorf("StartServer() without certificates returned no error")
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telempol "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+const (
+	l2 = 2
+	// Path is the URL path the admission webhook is served on.
+	Path              = "/validate-taspolicy"
+	readHeaderTimeout = 5 * time.Second
+	writeTimeout      = 10 * time.Second
+	shutdownTimeout   = 20 * time.Second
+	maxBodyBytes      = 3 * 1024 * 1024
+)
+
+var policyKind = schema.GroupKind{Group: telempol.Group, Kind: "TASPolicy"}
+
+// ServeHTTP answers an AdmissionReview for a Telemetry Policy. Policies failing validation are denied with the
+// list of invalid fields.
+func ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	review := admissionv1.AdmissionReview{}
+
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&review)
+	if err != nil || review.Request == nil {
+		klog.V(l2).InfoS("cannot decode admission review", "component", "webhook")
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	review.Response = Review(review.Request)
+	review.Request = nil
+
+	body, err := json.Marshal(review)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := w.Write(body); err != nil {
+		klog.V(l2).InfoS("cannot write admission response: "+err.Error(), "component", "webhook")
+	}
+}
+
+// Review validates the policy in the admission request and returns the admission response.
+func Review(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	response := &admissionv1.AdmissionResponse{UID: request.UID, Allowed: true}
+
+	if request.Operation != admissionv1.Create && request.Operation != admissionv1.Update {
+		return response
+	}
+
+	policy, err := decodePolicy(request)
+	if err != nil {
+		response.Allowed = false
+		response.Result = &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusBadRequest,
+			Reason: metav1.StatusReasonBadRequest, Message: fmt.Sprintf("cannot decode policy: %v", err)}
+
+		return response
+	}
+
+	if allErrs := ValidatePolicy(policy); len(allErrs) > 0 {
+		status := apierrors.NewInvalid(policyKind, policy.Name, allErrs).ErrStatus
+		response.Allowed = false
+		response.Result = &status
+
+		klog.V(l2).InfoS("Rejected policy "+policy.Namespace+"/"+policy.Name+": "+allErrs.ToAggregate().Error(),
+			"component", "webhook")
+	}
+
+	return response
+}
+
+// decodePolicy reads the policy from the admission request. Policies sent as v1alpha1 are converted to v1beta1, so
+// both versions are validated the same way.
+func decodePolicy(request *admissionv1.AdmissionRequest) (*telempol.TASPolicy, error) {
+	if request.Kind.Version == v1alpha1.Version {
+		old := v1alpha1.TASPolicy{}
+		if err := json.Unmarshal(request.Object.Raw, &old); err != nil {
+			return nil, err
+		}
+
+		return telempol.ConvertFromV1alpha1(&old), nil
+	}
+
+	policy := &telempol.TASPolicy{}
+	if err := json.Unmarshal(request.Object.Raw, policy); err != nil {
+		return nil, err
+	}
+
+	telempol.SetDefaults(policy)
+
+	return policy, nil
+}
+
+// StartServer serves the admission webhook over HTTPS on the given port.
+// The API server doesn't present a client certificate by default, so none is required.
+// It serves until the context is done, then drains the requests in flight before returning. It returns an error if
+// the server fails.
+func StartServer(ctx context.Context, port string, certFile string, keyFile string) error {
+	mx := http.NewServeMux()
+	mx.HandleFunc(Path, ServeHTTP)
+
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           mx,
+		ReadHeaderTimeout: readHeaderTimeout,
+		WriteTimeout:      writeTimeout,
+		TLSConfig:         &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+
+	errs := make(chan error, 1)
+
+	go func() {
+		klog.V(l2).InfoS("Policy webhook listening on HTTPS "+port, "component", "webhook")
+		errs <- srv.ListenAndServeTLS(certFile, keyFile)
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("webhook server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("webhook server shutdown failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,299 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetryscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/intel/platform-aware-scheduling/extender"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/dontschedule"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/scheduleonmetric"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// Results of a dontschedule rule on a node in an explanation.
+const (
+	rulePassed   = "Passed"
+	ruleViolated = "Violated"
+	ruleNoValue  = "NoValue"
+)
+
+var (
+	errNoPod    = errors.New("a pod or the namespace and name of a pod are needed")
+	errNoLookup = errors.New("pods can't be looked up by name without a kube client")
+)
+
+// ExplainArgs is the request of the explain endpoint. It holds either the pod to explain or the namespace and name of a
+// pod to look up, and optionally the names of the nodes to explain. Without node names every node of the cluster and
+// every node with a value for a rule of the pod policy is explained.
+type ExplainArgs struct {
+	Pod       *v1.Pod  `json:"pod,omitempty"`
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	NodeNames []string `json:"nodeNames,omitempty"`
+}
+
+// ExplainResult is the response of the explain endpoint. Policy is the namespace/name of the policy resolved for the
+// pod and Notes lists why strategies of the policy aren't applied, if they aren't. Nodes are sorted by their rank, then
+// by name.
+type ExplainResult struct {
+	Policy string            `json:"policy,omitempty"`
+	Notes  []string          `json:"notes,omitempty"`
+	Nodes  []NodeExplanation `json:"nodes,omitempty"`
+}
+
+// NodeExplanation is how the filter and prioritize requests treat a node. Filtered is true when the dontschedule
+// strategy removes the node, with the Reason of the filter response and whether it's Unresolvable, and Rules holds the
+// outcome of each of its rules. Score and Rank are those of the prioritize response, Rank starting at 1. Nodes without
+// a value for the scheduleonmetric rule have neither.
+type NodeExplanation struct {
+	Name         string            `json:"name"`
+	Filtered     bool              `json:"filtered"`
+	Reason       string            `json:"reason,omitempty"`
+	Unresolvable bool              `json:"unresolvable,omitempty"`
+	Rules        []RuleExplanation `json:"rules,omitempty"`
+	Priority     *RuleExplanation  `json:"priority,omitempty"`
+	Score        *int64            `json:"score,omitempty"`
+	Rank         int               `json:"rank,omitempty"`
+}
+
+// RuleExplanation is a rule applied to a node. Value is the metric value of the node, read at Timestamp and Staleness
+// old when the explanation was made. Result is Passed, Violated or NoValue for dontschedule rules.
+type RuleExplanation struct {
+	Rule      string       `json:"rule"`
+	Target    string       `json:"target"`
+	Value     string       `json:"value,omitempty"`
+	Predicted string       `json:"predicted,omitempty"`
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+	Staleness string       `json:"staleness,omitempty"`
+	Result    string       `json:"result,omitempty"`
+}
+
+// Explain manages the requests of the explain endpoint. It returns, for each node, the metric values the pod policy
+// reads and how they decide the filter and prioritize responses, so a pod which isn't scheduled as expected can be
+// understood without reading the extender logs.
+func (m MetricsExtender) Explain(w http.ResponseWriter, r *http.Request) {
+	extender.Handle(m.explainPod)(w, r)
+}
+
+// explainPod looks up the pod of the request if needed and explains it. Requests without a pod to explain are answered
+// with a 400 and pods which can't be explained with a 404.
+func (m MetricsExtender) explainPod(ctx context.Context, args *ExplainArgs) (*ExplainResult, error) {
+	klog.V(l2).InfoS("Explain request received", "component", "extender")
+
+	pod, err := m.lookupPod(ctx, args)
+	if err != nil {
+		return nil, extender.WithStatus(err, http.StatusBadRequest)
+	}
+
+	result, err := m.explain(pod, args.NodeNames)
+	if err != nil {
+		return nil, extender.WithStatus(err, http.StatusNotFound)
+	}
+
+	return &result, nil
+}
+
+// lookupPod returns the pod the request explains, looking it up if needed.
+func (m MetricsExtender) lookupPod(ctx context.Context, args *ExplainArgs) (*v1.Pod, error) {
+	if args.Pod != nil {
+		return args.Pod, nil
+	}
+
+	if args.Namespace == "" || args.Name == "" {
+		return nil, errNoPod
+	}
+
+	if m.KubeClient == nil {
+		return nil, errNoLookup
+	}
+
+	pod, err := m.KubeClient.CoreV1().Pods(args.Namespace).Get(ctx, args.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot look up pod %v/%v: %w", args.Namespace, args.Name, err)
+	}
+
+	return pod, nil
+}
+
+// explain evaluates the dontschedule and scheduleonmetric strategies of the pod policy on the nodes.
+func (m MetricsExtender) explain(pod *v1.Pod, nodeNames []string) (ExplainResult, error) {
+	policy, err := m.getPolicyFromPod(pod)
+	if err != nil {
+		return ExplainResult{}, err
+	}
+
+	result := ExplainResult{Policy: policy.Namespace + "/" + policy.Name}
+	now := core.Now()
+	nodes := map[string]*NodeExplanation{}
+	node := func(name string) *NodeExplanation {
+		if _, ok := nodes[name]; !ok {
+			nodes[name] = &NodeExplanation{Name: name}
+		}
+
+		return nodes[name]
+	}
+
+	strategy, err := m.getDontScheduleStrategy(policy)
+	evaluations := map[string]core.NodeEvaluation{}
+
+	if err != nil {
+		result.Notes = append(result.Notes, dontschedule.StrategyType+": "+err.Error())
+	} else {
+		evaluations = core.EvaluateNodes(telemetrypolicy.TASPolicyStrategy(strategy), m.cache)
+		for nodeName := range evaluations {
+			node(nodeName)
+		}
+	}
+
+	if err := m.explainPriorities(policy, nodeNames, node, now); err != nil {
+		result.Notes = append(result.Notes, scheduleonmetric.StrategyType+": "+err.Error())
+	}
+
+	if len(nodeNames) == 0 {
+		nodeNames = m.knownNodeNames(nodes)
+	}
+
+	for _, name := range nodeNames {
+		explanation := node(name)
+
+		if len(strategy.Rules) > 0 {
+			evaluation := nodeEvaluation(evaluations, strategy, name)
+			explanation.Reason, explanation.Unresolvable = failureReason(policy, strategy, evaluation)
+			explanation.Filtered = explanation.Reason != ""
+
+			for _, rule := range evaluation.Rules {
+				explanation.Rules = append(explanation.Rules, explainRule(rule, now))
+			}
+		}
+
+		result.Nodes = append(result.Nodes, *explanation)
+	}
+
+	sort.Slice(result.Nodes, func(i, j int) bool {
+		a, b := result.Nodes[i], result.Nodes[j]
+		if (a.Rank == 0) != (b.Rank == 0) {
+			return a.Rank != 0
+		}
+
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+
+		return a.Name < b.Name
+	})
+
+	return result, nil
+}
+
+// explainPriorities adds the value, score and rank of each node for the scheduleonmetric rule, as prioritize would
+// return them for a request with the passed nodes, or with every node with a value if none are passed.
+func (m MetricsExtender) explainPriorities(policy telemetrypolicy.TASPolicy, nodeNames []string, node func(string) *NodeExplanation,
+	now time.Time) error {
+	rule, err := m.getSchedulingRule(policy)
+	if err != nil {
+		return err
+	}
+
+	nodeData, err := core.RuleMetrics(rule, m.cache)
+	if err != nil {
+		return fmt.Errorf("%w, %v", err, core.RuleName(rule))
+	}
+
+	resolved, err := core.ResolveTarget(rule, nodeData)
+	if err != nil {
+		return fmt.Errorf("%w, %v", err, core.RuleName(rule))
+	}
+
+	if len(nodeNames) == 0 {
+		nodeNames = make([]string, 0, len(nodeData))
+		for name := range nodeData {
+			nodeNames = append(nodeNames, name)
+		}
+	}
+
+	priorities, err := m.prioritizeNodesForRule(policy, rule, nodeNames)
+	if err != nil {
+		return err
+	}
+
+	for i, priority := range priorities {
+		metric := nodeData[priority.Host]
+		score := priority.Score
+		explanation := node(priority.Host)
+		explanation.Score, explanation.Rank = &score, i+1
+		explanation.Priority = &RuleExplanation{
+			Rule:      core.RuleName(resolved),
+			Target:    core.TargetString(resolved),
+			Value:     metric.Value.String(),
+			Timestamp: &metav1.Time{Time: metric.Timestamp},
+			Staleness: now.Sub(metric.Timestamp).Round(time.Second).String(),
+		}
+	}
+
+	return nil
+}
+
+// explainRule describes the outcome of a dontschedule rule on a node.
+func explainRule(rule core.RuleEvaluation, now time.Time) RuleExplanation {
+	explanation := RuleExplanation{
+		Rule:   core.RuleName(rule.Rule),
+		Target: core.TargetString(rule.Rule),
+		Result: rulePassed,
+	}
+
+	if rule.Metric == nil {
+		explanation.Result = ruleNoValue
+
+		return explanation
+	}
+
+	explanation.Value = rule.Metric.Value.String()
+	explanation.Timestamp = &metav1.Time{Time: rule.Metric.Timestamp}
+	explanation.Staleness = now.Sub(rule.Metric.Timestamp).Round(time.Second).String()
+
+	if rule.Violated {
+		explanation.Result = ruleViolated
+	}
+
+	if rule.Value.Cmp(rule.Metric.Value) != 0 {
+		explanation.Predicted = rule.Value.String()
+	}
+
+	return explanation
+}
+
+// knownNodeNames returns the nodes of the cluster, if the extender watches them, and the nodes with a value for a rule.
+func (m MetricsExtender) knownNodeNames(nodes map[string]*NodeExplanation) []string {
+	nodeNames := []string{}
+
+	if m.Nodes != nil {
+		clusterNodes, err := m.Nodes.List(labels.Everything())
+		if err != nil {
+			klog.V(l4).InfoS("cannot list nodes: "+err.Error(), "component", "extender")
+		}
+
+		for _, node := range clusterNodes {
+			if _, ok := nodes[node.Name]; !ok {
+				nodeNames = append(nodeNames, node.Name)
+			}
+		}
+	}
+
+	for name := range nodes {
+		nodeNames = append(nodeNames, name)
+	}
+
+	return nodeNames
+}
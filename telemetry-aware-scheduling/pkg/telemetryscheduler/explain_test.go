@@ -0,0 +1,94 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetryscheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMetricsExtender_Explain(t *testing.T) {
+	pod := twoNodeArgument.Pod
+	kubeClient := fake.NewSimpleClientset(pod)
+
+	type node struct {
+		filtered bool
+		result   string
+		rank     int
+	}
+
+	tests := []struct {
+		name       string
+		args       ExplainArgs
+		wantStatus int
+		want       map[string]node
+	}{
+		{"pod", ExplainArgs{Pod: pod}, http.StatusOK,
+			map[string]node{"node A": {true, ruleViolated, 1}, "node B": {false, rulePassed, 2}}},
+		{"pod looked up", ExplainArgs{Namespace: pod.Namespace, Name: pod.Name}, http.StatusOK,
+			map[string]node{"node A": {true, ruleViolated, 1}, "node B": {false, rulePassed, 2}}},
+		{"node names", ExplainArgs{Pod: pod, NodeNames: []string{"node B", "node C"}}, http.StatusOK,
+			map[string]node{"node B": {false, rulePassed, 1}, "node C": {}}},
+		{"unknown pod", ExplainArgs{Namespace: pod.Namespace, Name: "small pod"}, http.StatusBadRequest, nil},
+		{"no pod", ExplainArgs{}, http.StatusBadRequest, nil},
+		{"no policy", ExplainArgs{Pod: noPolicyPod.Pod}, http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			policyCache := cache.MockSelfUpdatingCache()
+			m := MetricsExtender{cache: policyCache, KubeClient: kubeClient}
+
+			if err := policyCache.WritePolicy(testPolicy1.Namespace, testPolicy1.Name, testPolicy1); err != nil {
+				t.Fatal(err)
+			}
+
+			err := policyCache.WriteMetric("dummyMetric1", metrics.TestNodeMetricCustomInfo([]string{"node A", "node B"}, []int64{50, 30}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			argsAsJSON, err := json.Marshal(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "http://localhost/scheduler/explain", bytes.NewReader(argsAsJSON))
+			w := httptest.NewRecorder()
+			m.Explain(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Explain() status = %v, want %v: %v", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			result := ExplainResult{}
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("problem unmarshalling response %v", err)
+			}
+
+			if len(result.Nodes) != len(tt.want) {
+				t.Fatalf("Explain() nodes = %+v, want %v", result.Nodes, tt.want)
+			}
+
+			for _, got := range result.Nodes {
+				want := tt.want[got.Name]
+				if got.Filtered != want.filtered || got.Rank != want.rank {
+					t.Errorf("Explain() node %v filtered %v ranked %v, want %v", got.Name, got.Filtered, got.Rank, want)
+				}
+
+				if want.result != "" && (len(got.Rules) != 1 || got.Rules[0].Result != want.result || got.Rules[0].Timestamp == nil) {
+					t.Errorf("Explain() node %v rules = %+v, want %v", got.Name, got.Rules, want.result)
+				}
+			}
+		})
+	}
+}
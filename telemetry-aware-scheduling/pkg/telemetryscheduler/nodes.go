@@ -0,0 +1,51 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetryscheduler
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	extenderV1 "k8s.io/kube-scheduler/extender/v1"
+)
+
+// WatchNodes returns a lister of the cluster nodes, kept up to date by an informer until the context is done. With it
+// the extender knows the nodes of the cluster when the scheduler only sends node names, as it does for extenders
+// configured with nodeCacheCapable.
+func WatchNodes(ctx context.Context, kubeClient kubernetes.Interface) corelisters.NodeLister {
+	source := cache.NewListWatchFromClient(
+		kubeClient.CoreV1().RESTClient(),
+		"nodes",
+		v1.NamespaceAll,
+		fields.Everything(),
+	)
+	indexer, nodeController := cache.NewIndexerInformer(source, &v1.Node{}, 0, cache.ResourceEventHandlerFuncs{}, cache.Indexers{})
+
+	go nodeController.Run(ctx.Done())
+
+	return corelisters.NewNodeLister(indexer)
+}
+
+// requestNodeNames returns the names of the nodes in a scheduler request: the names of the nodes it holds, or the
+// node names it holds instead when the extender is nodeCacheCapable.
+func requestNodeNames(args extenderV1.ExtenderArgs) []string {
+	if args.Nodes == nil {
+		if args.NodeNames == nil {
+			return nil
+		}
+
+		return *args.NodeNames
+	}
+
+	nodeNames := make([]string, 0, len(args.Nodes.Items))
+	for _, node := range args.Nodes.Items {
+		nodeNames = append(nodeNames, node.Name)
+	}
+
+	return nodeNames
+}
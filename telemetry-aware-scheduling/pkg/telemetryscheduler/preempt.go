@@ -0,0 +1,88 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetryscheduler
+
+import (
+	"context"
+
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
+	"k8s.io/klog/v2"
+	extenderV1 "k8s.io/kube-scheduler/extender/v1"
+)
+
+// ProcessPreemption manages the preemption requests from the scheduler. Preempting pods on a node the dontschedule
+// strategy of the pod policy filters out doesn't let the pod run there, so those nodes are dropped from the candidates.
+// The victims of the other nodes are kept as the scheduler chose them.
+func (m MetricsExtender) ProcessPreemption(_ context.Context, args *extenderV1.ExtenderPreemptionArgs) (*extenderV1.ExtenderPreemptionResult, error) {
+	klog.V(l2).InfoS("Preemption request received", "component", "extender")
+
+	return m.preemptNodes(*args), nil
+}
+
+// preemptNodes returns the candidate nodes of the preemption which pass the dontschedule strategy, with their
+// victims. Victims are always returned by UID, as the scheduler expects from extenders.
+func (m MetricsExtender) preemptNodes(args extenderV1.ExtenderPreemptionArgs) *extenderV1.ExtenderPreemptionResult {
+	candidates := metaVictims(args)
+	result := &extenderV1.ExtenderPreemptionResult{NodeNameToMetaVictims: candidates}
+
+	if args.Pod == nil {
+		return result
+	}
+
+	policy, err := m.getPolicyFromPod(args.Pod)
+	if err != nil {
+		klog.V(l4).InfoS("Keeping all preemption candidates: "+err.Error(), "component", "extender")
+
+		return result
+	}
+
+	dontscheduleStrategy, err := m.getDontScheduleStrategy(policy)
+	if err != nil {
+		klog.V(l4).InfoS("Keeping all preemption candidates: "+err.Error(), "component", "extender")
+
+		return result
+	}
+
+	evaluations := core.EvaluateNodes(telemetrypolicy.TASPolicyStrategy(dontscheduleStrategy), m.cache)
+	result.NodeNameToMetaVictims = map[string]*extenderV1.MetaVictims{}
+
+	for nodeName, victims := range candidates {
+		reason, _ := failureReason(policy, dontscheduleStrategy, nodeEvaluation(evaluations, dontscheduleStrategy, nodeName))
+		if reason != "" {
+			klog.V(l2).InfoS("Not preempting pods on "+nodeName+": "+reason, "component", "extender")
+
+			continue
+		}
+
+		result.NodeNameToMetaVictims[nodeName] = victims
+	}
+
+	return result
+}
+
+// metaVictims returns the victims of each candidate node by UID. The scheduler sends them by UID to nodeCacheCapable
+// extenders and as pods to the others.
+func metaVictims(args extenderV1.ExtenderPreemptionArgs) map[string]*extenderV1.MetaVictims {
+	if args.NodeNameToMetaVictims != nil {
+		return args.NodeNameToMetaVictims
+	}
+
+	candidates := make(map[string]*extenderV1.MetaVictims, len(args.NodeNameToVictims))
+
+	for nodeName, victims := range args.NodeNameToVictims {
+		if victims == nil {
+			continue
+		}
+
+		meta := &extenderV1.MetaVictims{Pods: []*extenderV1.MetaPod{}, NumPDBViolations: victims.NumPDBViolations}
+		for _, pod := range victims.Pods {
+			meta.Pods = append(meta.Pods, &extenderV1.MetaPod{UID: string(pod.UID)})
+		}
+
+		candidates[nodeName] = meta
+	}
+
+	return candidates
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2022 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetryscheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/intel/platform-aware-scheduling/extender"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	extenderV1 "k8s.io/kube-scheduler/extender/v1"
+)
+
+func TestMetricsExtender_ProcessPreemption(t *testing.T) {
+	victim := func(uid string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{UID: types.UID("victim-" + uid)}}
+	}
+	metaVictims := map[string]*extenderV1.MetaVictims{
+		"node A": {Pods: []*extenderV1.MetaPod{{UID: "victim-a"}}},
+		"node B": {Pods: []*extenderV1.MetaPod{{UID: "victim-b"}}, NumPDBViolations: 1},
+		"node C": {Pods: []*extenderV1.MetaPod{{UID: "victim-c"}}},
+	}
+
+	tests := []struct {
+		name string
+		args extenderV1.ExtenderPreemptionArgs
+		want []string
+	}{
+		{"meta victims", extenderV1.ExtenderPreemptionArgs{Pod: twoNodeArgument.Pod, NodeNameToMetaVictims: metaVictims},
+			[]string{"node B", "node C"}},
+		{"victims", extenderV1.ExtenderPreemptionArgs{Pod: twoNodeArgument.Pod, NodeNameToVictims: map[string]*extenderV1.Victims{
+			"node A": {Pods: []*v1.Pod{victim("a")}}, "node B": {Pods: []*v1.Pod{victim("b")}, NumPDBViolations: 1}}},
+			[]string{"node B"}},
+		{"no policy", extenderV1.ExtenderPreemptionArgs{Pod: noPolicyPod.Pod, NodeNameToMetaVictims: metaVictims},
+			[]string{"node A", "node B", "node C"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			policyCache := cache.MockSelfUpdatingCache()
+			m := MetricsExtender{cache: policyCache}
+
+			if err := policyCache.WritePolicy(testPolicy1.Namespace, testPolicy1.Name, testPolicy1); err != nil {
+				t.Fatal(err)
+			}
+
+			err := policyCache.WriteMetric("dummyMetric1", metrics.TestNodeMetricCustomInfo([]string{"node A", "node B"}, []int64{50, 30}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			argsAsJSON, err := json.Marshal(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w := httptest.NewRecorder()
+			extender.Handle(m.ProcessPreemption)(w, httptest.NewRequest(http.MethodPost, "http://localhost/scheduler/preempt", bytes.NewReader(argsAsJSON)))
+
+			result := extenderV1.ExtenderPreemptionResult{}
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("problem unmarshalling response %v", err)
+			}
+
+			got := []string{}
+
+			for nodeName, victims := range result.NodeNameToMetaVictims {
+				got = append(got, nodeName)
+
+				if len(victims.Pods) != 1 || victims.Pods[0].UID != "victim-"+strings.ToLower(nodeName[len("node "):]) {
+					t.Errorf("ProcessPreemption() victims of %v = %+v", nodeName, victims.Pods)
+				}
+			}
+
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ProcessPreemption() nodes = %v, want %v", got, tt.want)
+			}
+
+			if victims, ok := result.NodeNameToMetaVictims["node B"]; ok && victims.NumPDBViolations != 1 {
+				t.Errorf("ProcessPreemption() PDB violations of node B = %v, want 1", victims.NumPDBViolations)
+			}
+		})
+	}
+}
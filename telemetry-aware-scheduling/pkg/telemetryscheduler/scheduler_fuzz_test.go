@@ -19,9 +19,10 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/intel/platform-aware-scheduling/extender"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
-	telpolv1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telpolv1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -286,7 +287,8 @@ func getPolicyStrategy(policyName, metricName string, ruleOperator RuleOperator,
 	return telpolv1.TASPolicyStrategy{
 		PolicyName: policyName,
 		Rules: []telpolv1.TASPolicyRule{
-			{Metricname: metricName, Operator: ruleOperator.GetRuleOperatorName(), Target: int64(threshold)}},
+			{Metricname: metricName, Operator: telpolv1.Operator(ruleOperator.GetRuleOperatorName()),
+				Target: *resource.NewQuantity(int64(threshold), resource.DecimalSI)}},
 	}
 }
 
@@ -516,7 +518,7 @@ func FuzzMetricsExtenderFilter(f *testing.F) {
 		mockedRequest.Header.Add("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		m.Filter(w, mockedRequest)
+		extender.Handle(m.Filter)(w, mockedRequest)
 		validateFilterExpectations(t, w, hasDontScheduleRule, numberOfNodes, numberOfViolatingNodes)
 	})
 }
@@ -563,7 +565,7 @@ func FuzzMetricsExtenderPrioritize(f *testing.F) {
 		mockedRequest.Header.Add("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
-		m.Prioritize(w, mockedRequest)
+		extender.Handle(m.Prioritize)(w, mockedRequest)
 
 		validatePrioritizeExpectations(t, hasScheduleOnRule, ruleOperator, numberOfNodes, nodeMetricValues, prioritizedNodes, w)
 	})
@@ -11,13 +11,17 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
 	"k8s.io/klog/v2"
 
+	"github.com/intel/platform-aware-scheduling/extender"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
-	telpolv1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
+	telpolv1 "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 	telpolclient "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/client/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -58,12 +62,12 @@ var testPolicy1 = telpolv1.TASPolicy{
 			"scheduleonmetric": {
 				PolicyName: "test-policy",
 				Rules: []telpolv1.TASPolicyRule{
-					{Metricname: "dummyMetric1", Operator: "GreaterThan", Target: 0}},
+					{Metricname: "dummyMetric1", Operator: "GreaterThan", Target: resource.MustParse("0")}},
 			},
 			"dontschedule": {
 				PolicyName: "test-policy",
 				Rules: []telpolv1.TASPolicyRule{
-					{Metricname: "dummyMetric1", Operator: "GreaterThan", Target: 40},
+					{Metricname: "dummyMetric1", Operator: "GreaterThan", Target: resource.MustParse("40")},
 				},
 			},
 		},
@@ -78,12 +82,12 @@ var testPolicy2 = telpolv1.TASPolicy{
 			"scheduleonmetric": {
 				PolicyName: "test-policy",
 				Rules: []telpolv1.TASPolicyRule{
-					{Metricname: "dummyMetric1", Operator: "GreaterThan", Target: 0}},
+					{Metricname: "dummyMetric1", Operator: "GreaterThan", Target: resource.MustParse("0")}},
 			},
 			"dontschedule": {
 				PolicyName: "test-policy",
 				Rules: []telpolv1.TASPolicyRule{
-					{Metricname: "dummyMetric1", Operator: "GreaterThan", Target: 40},
+					{Metricname: "dummyMetric1", Operator: "GreaterThan", Target: resource.MustParse("40")},
 				},
 			},
 		},
@@ -148,7 +152,7 @@ func TestMetricsExtender_prescheduleChecks(t *testing.T) {
 			tt.args.r.Header.Add("Content-Type", "application/json")
 			tt.args.r.Body = io.NopCloser(bytes.NewReader(argsAsJSON))
 			w := httptest.NewRecorder()
-			m.Prioritize(w, tt.args.r)
+			extender.Handle(m.Prioritize)(w, tt.args.r)
 			result := extenderV1.HostPriorityList{}
 			b := w.Body.Bytes()
 			err = json.Unmarshal(b, &result)
@@ -248,7 +252,7 @@ func TestMetricsExtender_Prioritize(t *testing.T) {
 			tt.args.r.Header.Add("Content-Type", "application/json")
 			tt.args.r.Body = io.NopCloser(bytes.NewReader(argsAsJSON))
 			w := httptest.NewRecorder()
-			m.Prioritize(w, tt.args.r)
+			extender.Handle(m.Prioritize)(w, tt.args.r)
 			result := extenderV1.HostPriorityList{}
 			b := w.Body.Bytes()
 			err = json.Unmarshal(b, &result)
@@ -283,6 +287,146 @@ func TestMetricsExtender_Prioritize(t *testing.T) {
 	}
 }
 
+// inactivePolicy returns a copy of the policy whose dontschedule strategy only applies in an hour from now.
+func inactivePolicy(policy telpolv1.TASPolicy) telpolv1.TASPolicy {
+	out := policy.DeepCopy()
+	start := time.Now().UTC().Add(time.Hour)
+	dontschedule := out.Spec.Strategies["dontschedule"]
+	dontschedule.ActiveWindows = []telpolv1.TASPolicyActiveWindow{
+		{Start: start.Format(core.WindowTimeFormat), End: start.Add(time.Hour).Format(core.WindowTimeFormat)}}
+	out.Spec.Strategies["dontschedule"] = dontschedule
+
+	return *out
+}
+
+func failClosedPolicy(policy telpolv1.TASPolicy) telpolv1.TASPolicy {
+	out := policy.DeepCopy()
+	dontschedule := out.Spec.Strategies["dontschedule"]
+	dontschedule.FailClosed = true
+	out.Spec.Strategies["dontschedule"] = dontschedule
+
+	return *out
+}
+
+func TestMetricsExtender_filterNodesReasons(t *testing.T) {
+	tests := []struct {
+		name             string
+		policy           telpolv1.TASPolicy
+		metric           metrics.NodeMetricsInfo
+		wantFailed       map[string]string
+		wantUnresolvable map[string]string
+	}{
+		{"violated rule", testPolicy1, metrics.TestNodeMetricCustomInfo([]string{"node A", "node B"}, []int64{50, 30}),
+			map[string]string{"node A": "policy default/test-policy dontschedule: dummyMetric1 is 50, violating GreaterThan 40"},
+			map[string]string{}},
+		{"missing metric passes", testPolicy1, metrics.TestNodeMetricCustomInfo([]string{"node A"}, []int64{30}),
+			map[string]string{}, map[string]string{}},
+		{"missing metric fails closed", failClosedPolicy(testPolicy1), metrics.TestNodeMetricCustomInfo([]string{"node A"}, []int64{50}),
+			map[string]string{"node A": "policy default/test-policy dontschedule: dummyMetric1 is 50, violating GreaterThan 40"},
+			map[string]string{"node B": "policy default/test-policy dontschedule: no value for dummyMetric1 and the strategy fails closed"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			policyCache := cache.MockSelfUpdatingCache()
+			m := MetricsExtender{cache: policyCache}
+
+			if err := policyCache.WritePolicy(tt.policy.Namespace, tt.policy.Name, tt.policy); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := policyCache.WriteMetric("dummyMetric1", tt.metric); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := m.filterNodes(twoNodeArgument)
+			if err != nil {
+				t.Fatalf("filterNodes() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(map[string]string(result.FailedNodes), tt.wantFailed) {
+				t.Errorf("filterNodes() failed nodes = %v, want %v", result.FailedNodes, tt.wantFailed)
+			}
+
+			if !reflect.DeepEqual(map[string]string(result.FailedAndUnresolvableNodes), tt.wantUnresolvable) {
+				t.Errorf("filterNodes() unresolvable nodes = %v, want %v", result.FailedAndUnresolvableNodes, tt.wantUnresolvable)
+			}
+		})
+	}
+}
+
+func TestMetricsExtender_filterNodesTargetOverrides(t *testing.T) {
+	policyCache := cache.MockSelfUpdatingCache()
+	m := MetricsExtender{cache: policyCache}
+
+	// The controller caches the policy as it's stored, so the strategies don't name their policy.
+	policy := testPolicy1.DeepCopy()
+	for name, spec := range policy.Spec.Strategies {
+		spec.PolicyName = ""
+		policy.Spec.Strategies[name] = spec
+	}
+
+	if err := policyCache.WritePolicy(policy.Namespace, policy.Name, *policy); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := policyCache.WriteMetric("dummyMetric1", metrics.TestNodeMetricCustomInfo([]string{"node A", "node B"}, []int64{50, 50})); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides := map[string]map[string]resource.Quantity{
+		"node A": {"default.test-policy.dummyMetric1": resource.MustParse("60")},
+		"node B": {"staging.test-policy.dummyMetric1": resource.MustParse("60")},
+	}
+	for nodeName, targets := range overrides {
+		if err := policyCache.WriteNodeTargets(nodeName, targets); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := m.filterNodes(twoNodeArgument)
+	if err != nil {
+		t.Fatalf("filterNodes() error = %v", err)
+	}
+
+	want := map[string]string{"node B": "policy default/test-policy dontschedule: dummyMetric1 is 50, violating GreaterThan 40"}
+	if !reflect.DeepEqual(map[string]string(result.FailedNodes), want) {
+		t.Errorf("filterNodes() failed nodes = %v, want %v", result.FailedNodes, want)
+	}
+
+	if result.Nodes == nil || len(result.Nodes.Items) != 1 || result.Nodes.Items[0].Name != "node A" {
+		t.Errorf("filterNodes() nodes = %v, want node A passing with its own target", result.Nodes)
+	}
+}
+
+func TestMetricsExtender_nodeCacheCapable(t *testing.T) {
+	policyCache := cache.MockSelfUpdatingCache()
+	m := MetricsExtender{cache: policyCache}
+
+	if err := policyCache.WritePolicy(testPolicy1.Namespace, testPolicy1.Name, testPolicy1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := policyCache.WriteMetric("dummyMetric1", metrics.TestNodeMetricCustomInfo([]string{"node A", "node B"}, []int64{50, 30})); err != nil {
+		t.Fatal(err)
+	}
+
+	args := extenderV1.ExtenderArgs{Pod: twoNodeArgument.Pod, NodeNames: &[]string{"node A", "node B", "node C"}}
+
+	filtered, err := m.filterNodes(args)
+	if err != nil || filtered.Nodes != nil || !reflect.DeepEqual(*filtered.NodeNames, []string{"node B", "node C"}) {
+		t.Errorf("filterNodes() = %+v, want only the node names node B and node C", filtered)
+	}
+
+	prioritized := m.prioritizeNodes(args)
+	want := extenderV1.HostPriorityList{{Host: "node A", Score: maxScore}, {Host: "node B", Score: maxScore - 1}}
+
+	if prioritized == nil || !reflect.DeepEqual(*prioritized, want) {
+		t.Errorf("prioritizeNodes() = %v, want %v", prioritized, want)
+	}
+}
+
 func TestMetricsExtender_Filter(t *testing.T) {
 	dummyClient, _ := telpolclient.New(*metrics.DummyRestClientConfig(), "default")
 
@@ -324,6 +468,15 @@ func TestMetricsExtender_Filter(t *testing.T) {
 				metrics.TestNodeMetricCustomInfo([]string{"node A", "node B"}, []int64{50, 30})},
 			wanted: extenderV1.ExtenderFilterResult{Nodes: &v1.NodeList{}, NodeNames: &[]string{"node A"}, FailedNodes: map[string]string{"node A": ""}},
 		},
+		{name: "inactive strategy filters no node",
+			fields: fields{*dummyClient, cache.MockSelfUpdatingCache(),
+				metrics.NewDummyMetricsClient(metrics.InstanceOfMockMetricClientMap),
+				inactivePolicy(testPolicy1)},
+			args: args{
+				httptest.NewRequest(http.MethodPost, "http://localhost/scheduler/prioritize", nil),
+				metrics.TestNodeMetricCustomInfo([]string{"node A", "node B"}, []int64{50, 30})},
+			wanted: extenderV1.ExtenderFilterResult{Nodes: &v1.NodeList{}, FailedNodes: map[string]string{}},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -350,7 +503,7 @@ func TestMetricsExtender_Filter(t *testing.T) {
 			tt.args.r.Body = io.NopCloser(bytes.NewReader(argsAsJSON))
 			tt.args.r.Header.Add("Content-Type", "application/json")
 			w := httptest.NewRecorder()
-			m.Filter(w, tt.args.r)
+			extender.Handle(m.Filter)(w, tt.args.r)
 			result := extenderV1.ExtenderFilterResult{}
 			b := w.Body.Bytes()
 			err = json.Unmarshal(b, &result)
@@ -5,21 +5,24 @@
 package telemetryscheduler
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
 
+	"github.com/intel/platform-aware-scheduling/extender"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/cache"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/metrics"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/core"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/dontschedule"
 	"github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/strategies/scheduleonmetric"
-	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1alpha1"
+	telemetrypolicy "github.com/intel/platform-aware-scheduling/telemetry-aware-scheduling/pkg/telemetrypolicy/api/v1beta1"
 	extenderV1 "k8s.io/kube-scheduler/extender/v1"
 )
 
@@ -31,17 +34,21 @@ const (
 
 var (
 	tasPolicy       = "telemetry-policy"
-	errReqBody      = errors.New("request body empty")
+	errNoReqPod     = errors.New("no pod in the request")
 	errNonode       = errors.New("no nodes in the list")
 	errNoPolicy     = errors.New("no policy found")
 	errNoRules      = errors.New("no rules found")
 	errDontschedule = errors.New("dontschedule not found")
+	errInactive     = errors.New("strategy outside its active windows")
 	errNull         = errors.New("")
 )
 
-// MetricsExtender holds information on the cache holding scheduling strategies and metrics.
+// MetricsExtender holds information on the cache holding scheduling strategies and metrics. KubeClient, if set, is used
+// to look up the pods explained by name, and Nodes to explain every node of the cluster.
 type MetricsExtender struct {
-	cache cache.Reader
+	cache      cache.Reader
+	KubeClient kubernetes.Interface
+	Nodes      corelisters.NodeLister
 }
 
 // NewMetricsExtender returns a new metric Extender with the cache passed to it.
@@ -52,59 +59,33 @@ func NewMetricsExtender(newCache cache.Reader) MetricsExtender {
 }
 
 // Prioritize manages all prioritize requests from the scheduler extender.
-// It decodes the package, checks its policy, and performs error checking.
-// It then calls the prioritize logic and writes a response to the scheduler.
-func (m MetricsExtender) Prioritize(w http.ResponseWriter, r *http.Request) {
+// It checks the request and its policy, then calls the prioritize logic.
+func (m MetricsExtender) Prioritize(_ context.Context, args *extenderV1.ExtenderArgs) (*extenderV1.HostPriorityList, error) {
 	klog.V(l2).InfoS("Received prioritize request", "component", "extender")
 
-	extenderArgs, err := m.DecodeExtenderRequest(r)
-	if err != nil {
-		klog.V(l2).InfoS("failed to prioritize: "+err.Error(), "component", "extender")
-
-		return
-	}
-
-	if len(extenderArgs.Nodes.Items) == 0 {
-		klog.V(l2).InfoS("bad extender arguments. No nodes in list", "component", "extender")
-
-		return
+	if err := checkExtenderArgs(args); err != nil {
+		return nil, err
 	}
 
-	if _, ok := extenderArgs.Pod.Labels[tasPolicy]; !ok {
-		klog.V(l2).InfoS("no policy associated with pod", "component", "extender")
-		w.WriteHeader(http.StatusBadRequest)
+	if _, ok := args.Pod.Labels[tasPolicy]; !ok {
+		return nil, extender.WithStatus(fmt.Errorf("pod %v: %w", args.Pod.Name, errNoPolicy), http.StatusBadRequest)
 	}
 
-	prioritizedNodes := m.prioritizeNodes(extenderArgs)
-
-	if prioritizedNodes == nil {
-		w.WriteHeader(http.StatusNotFound)
-	}
-
-	m.WritePrioritizeResponse(w, prioritizedNodes)
+	return m.prioritizeNodes(*args), nil
 }
 
-// DecodeExtenderRequest reads the json request into the expected struct.
-// It returns an error of the request is not in the required format.
-func (m MetricsExtender) DecodeExtenderRequest(r *http.Request) (extenderV1.ExtenderArgs, error) {
-	var args extenderV1.ExtenderArgs
-	if r.Body == nil {
-		return args, fmt.Errorf("%w", errReqBody)
+// checkExtenderArgs checks a filter or prioritize request holds a pod and either nodes or, when the extender is
+// nodeCacheCapable, node names.
+func checkExtenderArgs(args *extenderV1.ExtenderArgs) error {
+	if args.Pod == nil {
+		return extender.WithStatus(errNoReqPod, http.StatusBadRequest)
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
-		return args, fmt.Errorf("error decoding request: %w", err)
+	if len(requestNodeNames(*args)) == 0 {
+		return extender.WithStatus(errNonode, http.StatusBadRequest)
 	}
 
-	if err := r.Body.Close(); err != nil {
-		return args, fmt.Errorf("cannot decode request %w", err)
-	}
-
-	if args.Nodes == nil {
-		return args, fmt.Errorf("%w", errNonode)
-	}
-
-	return args, nil
+	return nil
 }
 
 // prioritizeNodes implements the logic for the prioritize scheduler call.
@@ -123,7 +104,7 @@ func (m MetricsExtender) prioritizeNodes(args extenderV1.ExtenderArgs) *extender
 		return &extenderV1.HostPriorityList{}
 	}
 
-	chosenNodes, err := m.prioritizeNodesForRule(scheduleRule, args.Nodes)
+	chosenNodes, err := m.prioritizeNodesForRule(policy, scheduleRule, requestNodeNames(args))
 	if err != nil {
 		klog.V(l2).InfoS(err.Error(), "component", "extender")
 
@@ -150,12 +131,17 @@ func (m MetricsExtender) getPolicyFromPod(pod *v1.Pod) (telemetrypolicy.TASPolic
 	return telemetrypolicy.TASPolicy{}, fmt.Errorf("pod spec for pod %v: %w", pod.Name, errNoPolicy)
 }
 
-// getSchedulingRule does basic validation on the scheduling rule. Returns the rule if it seems useful.
+// getSchedulingRule does basic validation on the scheduling rule. Returns the rule if it seems useful and the
+// strategy is in one of its active windows.
 func (m MetricsExtender) getSchedulingRule(policy telemetrypolicy.TASPolicy) (telemetrypolicy.TASPolicyRule, error) {
-	_, ok := policy.Spec.Strategies[scheduleonmetric.StrategyType]
+	rawStrategy, ok := policy.Spec.Strategies[scheduleonmetric.StrategyType]
+	if ok && !core.InWindows(rawStrategy.ActiveWindows, core.Now()) {
+		return telemetrypolicy.TASPolicyRule{}, fmt.Errorf("failed to schedule: %w", errInactive)
+	}
+
 	if ok && len(policy.Spec.Strategies[scheduleonmetric.StrategyType].Rules) > 0 {
 		out := policy.Spec.Strategies[scheduleonmetric.StrategyType].Rules[0]
-		if len(out.Metricname) > 0 {
+		if len(out.Metricname) > 0 || len(out.Expression) > 0 {
 			return out, nil
 		}
 	}
@@ -165,24 +151,31 @@ func (m MetricsExtender) getSchedulingRule(policy telemetrypolicy.TASPolicy) (te
 
 // prioritizeNodesForRule returns the nodes listed in order of priority after applying the appropriate telemetry rule.
 // Priorities are ordinal - there is no relationship between the outputted priorities and the metrics - simply an order of preference.
-func (m MetricsExtender) prioritizeNodesForRule(rule telemetrypolicy.TASPolicyRule, nodes *v1.NodeList) (extenderV1.HostPriorityList, error) {
+// Nodes overriding the rule target with an annotation are ranked by their margin to their own target.
+func (m MetricsExtender) prioritizeNodesForRule(policy telemetrypolicy.TASPolicy, rule telemetrypolicy.TASPolicyRule,
+	nodeNames []string) (extenderV1.HostPriorityList, error) {
 	filteredNodeData := metrics.NodeMetricsInfo{}
 
-	nodeData, err := m.cache.ReadMetric(rule.Metricname)
+	nodeData, err := core.RuleMetrics(rule, m.cache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prioritize: %w, %v ", err, core.RuleName(rule))
+	}
+
+	rule, err = core.ResolveTarget(rule, nodeData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prioritize: %w, %v ", err, rule.Metricname)
+		return nil, fmt.Errorf("failed to prioritize: %w, %v ", err, core.RuleName(rule))
 	}
 	// Here we pull out nodes that have metrics but aren't in the filtered list
-	for _, node := range nodes.Items {
-		if v, ok := nodeData[node.Name]; ok {
-			filteredNodeData[node.Name] = v
+	for _, nodeName := range nodeNames {
+		if v, ok := nodeData[nodeName]; ok {
+			filteredNodeData[nodeName] = v
 		}
 	}
 
 	outputNodes := extenderV1.HostPriorityList{}
 
-	metricsOutput := fmt.Sprintf("%v for nodes: ", rule.Metricname)
-	orderedNodes := core.OrderedList(filteredNodeData, rule.Operator)
+	metricsOutput := fmt.Sprintf("%v for nodes: ", core.RuleName(rule))
+	orderedNodes := core.OrderedList(filteredNodeData, rule, m.cache.ReadTargetOverrides(core.TargetOverrideKey(policy.Namespace, policy.Name, rule)))
 
 	for i, node := range orderedNodes {
 		metricsOutput = fmt.Sprint(metricsOutput, " [ ", node.NodeName, " :", node.MetricValue.AsDec(), "]")
@@ -195,57 +188,42 @@ func (m MetricsExtender) prioritizeNodesForRule(rule telemetrypolicy.TASPolicyRu
 	return outputNodes, nil
 }
 
-// WritePrioritizeResponse writes out the results of prioritize in the response to the scheduler.
-func (m MetricsExtender) WritePrioritizeResponse(w http.ResponseWriter, result *extenderV1.HostPriorityList) {
-	encoder := json.NewEncoder(w)
-	if err := encoder.Encode(result); err != nil {
-		klog.V(l4).InfoS("Encode error: "+err.Error(), "component", "extender")
-		http.Error(w, "Encode error ", http.StatusBadRequest)
-	}
-}
-
 // Filter manages all filter requests from the scheduler.
-// It decodes the request, checks its policy and registers it.
-// It then calls the filter logic and writes a response to the scheduler.
-func (m MetricsExtender) Filter(w http.ResponseWriter, r *http.Request) {
+// It checks the request, then calls the filter logic on the policy of the pod.
+func (m MetricsExtender) Filter(_ context.Context, args *extenderV1.ExtenderArgs) (*extenderV1.ExtenderFilterResult, error) {
 	klog.V(l2).InfoS("Filter request received", "component", "extender")
 
-	extenderArgs, err := m.DecodeExtenderRequest(r)
-	if err != nil {
-		klog.V(l2).InfoS("cannot filter "+err.Error(), "component", "extender")
-
-		return
+	if err := checkExtenderArgs(args); err != nil {
+		return nil, err
 	}
 
-	filteredNodes := m.filterNodes(extenderArgs)
-
-	if filteredNodes == nil {
-		klog.V(l2).InfoS("No filtered nodes returned", "component", "extender")
-		w.WriteHeader(http.StatusNotFound)
+	result, err := m.filterNodes(*args)
+	if err != nil {
+		return nil, extender.WithStatus(err, http.StatusNotFound)
 	}
 
-	m.WriteFilterResponse(w, filteredNodes)
+	return result, nil
 }
 
-// Bind binds the pod to the node. Not implemented by TAS, hence response with StatusNotFound.
-func (m MetricsExtender) Bind(w http.ResponseWriter, _ *http.Request) {
-	w.WriteHeader(http.StatusNotFound)
+// Bind binds the pod to the node. Not implemented by TAS.
+func (m MetricsExtender) Bind(context.Context, *extenderV1.ExtenderBindingArgs) (*extenderV1.ExtenderBindingResult, error) {
+	return nil, extender.ErrNotSupported
 }
 
 // filterNodes takes in the arguments for the scheduler and filters nodes based on the pod's dontschedule strategy - if it has one in an attached policy.
-func (m MetricsExtender) filterNodes(args extenderV1.ExtenderArgs) *extenderV1.ExtenderFilterResult {
-	availableNodeNames := ""
-
-	var filteredNodes []v1.Node
-
+// Nodes violating the strategy are failed with the rules they violate. Nodes without a value for a rule of a fail closed
+// strategy can't pass until the metric is reported, so preempting pods doesn't help and they are failed as unresolvable.
+// The passing nodes are returned in the shape of the request: as nodes and their names, or only as names when the
+// extender is nodeCacheCapable.
+func (m MetricsExtender) filterNodes(args extenderV1.ExtenderArgs) (*extenderV1.ExtenderFilterResult, error) {
+	availableNodeNames := []string{}
 	failedNodes := extenderV1.FailedNodesMap{}
+	unresolvableNodes := extenderV1.FailedNodesMap{}
 	result := extenderV1.ExtenderFilterResult{}
 
 	policy, err := m.getPolicyFromPod(args.Pod)
 	if err != nil {
-		klog.V(l2).InfoS("get policy from pod failed "+err.Error(), "component", "extender")
-
-		return nil
+		return nil, fmt.Errorf("get policy from pod failed: %w", err)
 	}
 
 	dontscheduleStrategy, err := m.getDontScheduleStrategy(policy)
@@ -253,45 +231,128 @@ func (m MetricsExtender) filterNodes(args extenderV1.ExtenderArgs) *extenderV1.E
 		klog.V(l4).InfoS("Returning all nodes "+err.Error(), "component", "extender")
 
 		return &extenderV1.ExtenderFilterResult{
-			Nodes: args.Nodes,
-		}
+			Nodes:     args.Nodes,
+			NodeNames: args.NodeNames,
+		}, nil
 	}
 
-	violatingNodes := dontscheduleStrategy.Violated(m.cache)
+	evaluations := core.EvaluateNodes(telemetrypolicy.TASPolicyStrategy(dontscheduleStrategy), m.cache)
 
-	if len(args.Nodes.Items) == 0 {
-		klog.V(l2).InfoS("No nodes to compare", "component", "extender")
-
-		return nil
+	nodeNames := requestNodeNames(args)
+	if len(nodeNames) == 0 {
+		return nil, errNonode
 	}
 
-	for _, node := range args.Nodes.Items {
-		if _, ok := violatingNodes[node.Name]; ok {
-			failedNodes[node.Name] = strings.Join([]string{"Node violates"}, policy.Name)
-		} else {
-			filteredNodes = append(filteredNodes, node)
-			availableNodeNames += node.Name + " "
+	for _, nodeName := range nodeNames {
+		evaluation := nodeEvaluation(evaluations, dontscheduleStrategy, nodeName)
+		reason, unresolvable := failureReason(policy, dontscheduleStrategy, evaluation)
+
+		switch {
+		case reason == "":
+			availableNodeNames = append(availableNodeNames, nodeName)
+		case unresolvable:
+			unresolvableNodes[nodeName] = reason
+		default:
+			failedNodes[nodeName] = reason
+		}
+
+		if reason != "" {
+			klog.V(l2).InfoS(nodeName+" failed: "+reason, "component", "extender")
 		}
 	}
 
-	nodeNames := strings.Split(availableNodeNames, " ")
 	result = extenderV1.ExtenderFilterResult{
-		Nodes: &v1.NodeList{
-			Items: filteredNodes,
-		},
-		NodeNames:   &nodeNames,
-		FailedNodes: failedNodes,
-		Error:       "",
+		NodeNames:                  &availableNodeNames,
+		FailedNodes:                failedNodes,
+		FailedAndUnresolvableNodes: unresolvableNodes,
+		Error:                      "",
+	}
+
+	if args.Nodes != nil {
+		result.Nodes = &v1.NodeList{}
+
+		for _, node := range args.Nodes.Items {
+			if _, ok := failedNodes[node.Name]; !ok {
+				if _, ok := unresolvableNodes[node.Name]; !ok {
+					result.Nodes.Items = append(result.Nodes.Items, node)
+				}
+			}
+		}
 	}
 
 	if len(availableNodeNames) > 0 {
-		klog.V(l2).InfoS("Filtered nodes for "+policy.Name+": "+availableNodeNames, "component", "extender")
+		klog.V(l2).InfoS("Filtered nodes for "+policy.Name+": "+strings.Join(availableNodeNames, " "), "component", "extender")
 	}
 
-	return &result
+	return &result, nil
+}
+
+// nodeEvaluation returns the evaluation of the strategy on a node, with no value for any rule if the node has none.
+func nodeEvaluation(evaluations map[string]core.NodeEvaluation, strategy dontschedule.Strategy, nodeName string) core.NodeEvaluation {
+	if evaluation, ok := evaluations[nodeName]; ok {
+		return evaluation
+	}
+
+	evaluation := core.NodeEvaluation{Rules: make([]core.RuleEvaluation, len(strategy.Rules))}
+	for i, rule := range strategy.Rules {
+		evaluation.Rules[i].Rule = rule
+	}
+
+	return evaluation
+}
+
+// failureReason returns why the dontschedule strategy fails a node, naming the policy, the strategy and each rule
+// with the value it failed on, and whether the failure is unresolvable. It returns an empty reason if the node passes.
+func failureReason(policy telemetrypolicy.TASPolicy, strategy dontschedule.Strategy, evaluation core.NodeEvaluation) (string, bool) {
+	prefix := "policy " + policy.Namespace + "/" + policy.Name + " " + dontschedule.StrategyType + ": "
+
+	if strategy.FailClosed {
+		missing := []string{}
+
+		for _, rule := range evaluation.Rules {
+			if rule.Metric == nil {
+				missing = append(missing, core.RuleName(rule.Rule))
+			}
+		}
+
+		if len(missing) > 0 {
+			return prefix + "no value for " + strings.Join(missing, ", ") + " and the strategy fails closed", true
+		}
+	}
+
+	if !evaluation.Violated {
+		return "", false
+	}
+
+	violations := []string{}
+
+	for _, rule := range evaluation.Rules {
+		if rule.Violated {
+			violations = append(violations, violation(rule))
+		}
+	}
+
+	return prefix + strings.Join(violations, "; "), false
+}
+
+// violation describes a violated rule with the value observed on the node, and the predicted value for trend rules
+// violated by their prediction.
+func violation(rule core.RuleEvaluation) string {
+	name := core.RuleName(rule.Rule)
+	if rule.Rule.Operator == "" {
+		return name + " is true"
+	}
+
+	observed := rule.Value.String()
+	if rule.Metric != nil && rule.Value.Cmp(rule.Metric.Value) != 0 {
+		observed = rule.Metric.Value.String() + ", predicted " + rule.Value.String()
+	}
+
+	return fmt.Sprintf("%v is %v, violating %v %v", name, observed, rule.Rule.Operator, core.TargetString(rule.Rule))
 }
 
 // getDontScheduleStrategy pulls the dontschedule strategy from a telemetry policy passed to it.
+// A strategy outside its active windows is an error, so no node is filtered out.
 func (m MetricsExtender) getDontScheduleStrategy(policy telemetrypolicy.TASPolicy) (dontschedule.Strategy, error) {
 	rawStrategy := policy.Spec.Strategies[dontschedule.StrategyType]
 
@@ -299,16 +360,13 @@ func (m MetricsExtender) getDontScheduleStrategy(policy telemetrypolicy.TASPolic
 		return dontschedule.Strategy{}, fmt.Errorf("strategy failed: %w", errDontschedule)
 	}
 
+	if !core.InWindows(rawStrategy.ActiveWindows, core.Now()) {
+		return dontschedule.Strategy{}, fmt.Errorf("strategy failed: %w", errInactive)
+	}
+
 	dontscheduleStrategy := (dontschedule.Strategy)(rawStrategy)
+	dontscheduleStrategy.PolicyName = policy.Name
+	dontscheduleStrategy.PolicyNamespace = policy.Namespace
 
 	return dontscheduleStrategy, nil
 }
-
-// WriteFilterResponse takes the ExtenderFilterResults struct and writes it as a http response if valid.
-func (m MetricsExtender) WriteFilterResponse(w http.ResponseWriter, result *extenderV1.ExtenderFilterResult) {
-	encoder := json.NewEncoder(w)
-	if err := encoder.Encode(result); err != nil {
-		klog.V(l4).InfoS("Encode error "+err.Error(), "component", "extender")
-		http.Error(w, "Encode error", http.StatusBadRequest)
-	}
-}